@@ -0,0 +1,84 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func init() {
+	wallet.SetDecisionHasher(DecisionHash)
+}
+
+// DecisionHash returns the hash of the decision bytes for the network type
+// of uid. It fails if bs is not the canonical bytes of a decision.
+func DecisionHash(uid string, bs []byte) ([]byte, error) {
+	mod := ForUID(uid)
+	if mod == nil {
+		return nil, errors.IllegalArgumentError.Errorf("UnknownNetworkType(uid=%s)", uid)
+	}
+	d := new(networkTypeSectionDecision)
+	if _, err := codec.UnmarshalFromBytes(bs, d); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidDecision")
+	}
+	if !bytes.Equal(codec.MustMarshalToBytes(d), bs) {
+		return nil, errors.IllegalArgumentError.New("NonCanonicalDecision")
+	}
+	return mod.Hash(bs), nil
+}
+
+// DecisionWalletProvider returns the wallet provider for signing the
+// decision. Wallets implementing module.PayloadSigner sign the decision
+// instead of its hash.
+func DecisionWalletProvider(wp module.WalletProvider, d module.BytesHasher) module.WalletProvider {
+	ntsd, ok := d.(*networkTypeSectionDecision)
+	if !ok {
+		return wp
+	}
+	return &decisionWalletProvider{wp: wp, d: ntsd}
+}
+
+type decisionWalletProvider struct {
+	wp module.WalletProvider
+	d  *networkTypeSectionDecision
+}
+
+func (p *decisionWalletProvider) WalletFor(dsa string) module.BaseWallet {
+	w := p.wp.WalletFor(dsa)
+	if ps, ok := w.(module.PayloadSigner); ok {
+		return &decisionWallet{BaseWallet: w, ps: ps, d: p.d}
+	}
+	return w
+}
+
+type decisionWallet struct {
+	module.BaseWallet
+	ps module.PayloadSigner
+	d  *networkTypeSectionDecision
+}
+
+func (w *decisionWallet) Sign(data []byte) ([]byte, error) {
+	if !bytes.Equal(data, w.d.Hash()) {
+		return nil, errors.IllegalArgumentError.New("NotDecisionHash")
+	}
+	return w.ps.SignPayload(module.BTPDecisionSignType(w.d.mod.UID()), w.d.Bytes())
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/icon-project/goloop/block"
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/chain/index"
+	"github.com/icon-project/goloop/chain/pruning"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -97,6 +99,12 @@ type singleChain struct {
 
 	regulator *regulator
 
+	indexerMtx sync.Mutex
+	logIndexer *index.LogIndexer
+	txIndexer  *index.TxIndexer
+
+	pruner *pruning.Pruner
+
 	state      State
 	lastErr    error
 	mtx        sync.RWMutex
@@ -113,6 +121,7 @@ const (
 	DefaultContractDir = "contract"
 	DefaultCacheDir    = "cache"
 	DefaultTmpDBDir    = "tmp"
+	DefaultPruningDir  = "pruning"
 )
 
 func (c *singleChain) Database() db.Database {
@@ -246,6 +255,38 @@ func (c *singleChain) NephewsLimit() int {
 	return ConfigDefaultNephewLimit
 }
 
+func (c *singleChain) PeerBanThreshold() int {
+	if c.cfg.PeerBanThreshold != nil && *c.cfg.PeerBanThreshold < 0 {
+		return *c.cfg.PeerBanThreshold
+	}
+	return ConfigDefaultPeerBanThreshold
+}
+
+func (c *singleChain) PeerScoreHalfLife() time.Duration {
+	if c.cfg.PeerScoreHalfLife > 0 {
+		return time.Duration(c.cfg.PeerScoreHalfLife) * time.Millisecond
+	}
+	return ConfigDefaultPeerScoreHalfLife
+}
+
+func (c *singleChain) PeerBanDuration() time.Duration {
+	if c.cfg.PeerBanDuration > 0 {
+		return time.Duration(c.cfg.PeerBanDuration) * time.Millisecond
+	}
+	return ConfigDefaultPeerBanDuration
+}
+
+func (c *singleChain) BandwidthLimits() string {
+	return c.cfg.BandwidthLimits
+}
+
+func (c *singleChain) PacketCapture() string {
+	if len(c.cfg.PacketCapture) == 0 {
+		return ""
+	}
+	return c.cfg.ResolveAbsolute(c.cfg.PacketCapture)
+}
+
 func (c *singleChain) ValidateTxOnSend() bool {
 	return c.cfg.ValidateTxOnSend
 }
@@ -409,7 +450,7 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 		return errors.Wrapf(err, "UnknownCacheStrategy(%s)", c.cfg.NodeCache)
 	}
 	cacheDir := path.Join(chainDir, DefaultCacheDir)
-	c.database = cache.AttachManager(cdb, cacheDir, mLevel, fLevel, stores)
+	c.database = cache.AttachManager(pruning.AttachTracker(cdb), cacheDir, mLevel, fLevel, stores)
 	return nil
 }
 
@@ -482,6 +523,96 @@ func (c *singleChain) prepareManagers() error {
 	return nil
 }
 
+func (c *singleChain) startIndexers() error {
+	c.indexerMtx.Lock()
+	defer c.indexerMtx.Unlock()
+
+	if c.cfg.LogIndex {
+		li, err := index.NewLogIndexer(c.database, c.bm, c.sm, c.logger)
+		if err != nil {
+			return err
+		}
+		if err := li.Start(); err != nil {
+			return err
+		}
+		c.logIndexer = li
+	}
+	if c.cfg.TxIndex {
+		ti, err := index.NewTxIndexer(c.database, c.bm, c.sm,
+			c.GenesisStorage().Height(), c.logger)
+		if err != nil {
+			return err
+		}
+		if err := ti.Start(); err != nil {
+			return err
+		}
+		c.txIndexer = ti
+	}
+	return nil
+}
+
+func (c *singleChain) stopIndexers() {
+	c.indexerMtx.Lock()
+	defer c.indexerMtx.Unlock()
+
+	if c.logIndexer != nil {
+		c.logIndexer.Stop()
+		c.logIndexer = nil
+	}
+	if c.txIndexer != nil {
+		c.txIndexer.Stop()
+		c.txIndexer = nil
+	}
+}
+
+func (c *singleChain) inspectIndexers() map[string]interface{} {
+	c.indexerMtx.Lock()
+	defer c.indexerMtx.Unlock()
+
+	if c.logIndexer == nil && c.txIndexer == nil {
+		return nil
+	}
+	m := make(map[string]interface{})
+	if c.logIndexer != nil {
+		m["log"] = c.logIndexer.Status()
+	}
+	if c.txIndexer != nil {
+		m["tx"] = c.txIndexer.Status()
+	}
+	return m
+}
+
+// InspectIndexers returns the status of the indexers of the chain.
+func InspectIndexers(c module.Chain, informal bool) map[string]interface{} {
+	if sc, ok := c.(*singleChain); ok {
+		return sc.inspectIndexers()
+	}
+	return nil
+}
+
+func (c *singleChain) startPruner() error {
+	if c.cfg.PruningWindow > 0 {
+		p, err := pruning.NewPruner(c.database, c.bm, c.sm,
+			c.GenesisStorage().Height(), c.cfg.PruningWindow, c.cfg.PruningCheckpoint,
+			path.Join(c.cfg.AbsBaseDir(), DefaultPruningDir), c.cfg.DBType, c.logger)
+		if err != nil {
+			return err
+		}
+		if err := p.Start(); err != nil {
+			return err
+		}
+		c.pruner = p
+	}
+	return nil
+}
+
+func (c *singleChain) stopPruner() {
+	if c.pruner != nil {
+		c.pruner.Stop()
+		c.pruner = nil
+	}
+}
+
 func (c *singleChain) releaseManagers() {
 	if c.cs != nil {
 		c.cs.Term()
@@ -14,12 +14,15 @@ import (
 )
 
 const (
-	ConfigDefaultNormalTxPoolSize = 5000
-	ConfigDefaultPatchTxPoolSize  = 1000
-	ConfigDefaultMaxBlockTxBytes  = 1024 * 1024
-	ConfigDefaultTxTimeout        = 5000 * time.Millisecond
-	ConfigDefaultChildrenLimit    = 10
-	ConfigDefaultNephewLimit      = 10
+	ConfigDefaultNormalTxPoolSize  = 5000
+	ConfigDefaultPatchTxPoolSize   = 1000
+	ConfigDefaultMaxBlockTxBytes   = 1024 * 1024
+	ConfigDefaultTxTimeout         = 5000 * time.Millisecond
+	ConfigDefaultChildrenLimit     = 10
+	ConfigDefaultNephewLimit       = 10
+	ConfigDefaultPeerBanThreshold  = -100
+	ConfigDefaultPeerScoreHalfLife = 10 * time.Minute
+	ConfigDefaultPeerBanDuration   = time.Hour
 )
 
 const (
@@ -37,17 +40,26 @@ type Config struct {
 	Platform string `json:"platform,omitempty"`
 
 	// static
-	SeedAddr         string `json:"seed_addr"`
-	Role             uint   `json:"role"`
-	ConcurrencyLevel int    `json:"concurrency_level,omitempty"`
-	NormalTxPoolSize int    `json:"normal_tx_pool,omitempty"`
-	PatchTxPoolSize  int    `json:"patch_tx_pool,omitempty"`
-	MaxBlockTxBytes  int    `json:"max_block_tx_bytes,omitempty"`
-	NodeCache        string `json:"node_cache,omitempty"`
-	AutoStart        bool   `json:"auto_start,omitempty"`
-	ChildrenLimit    *int   `json:"children_limit,omitempty"`
-	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
-	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	SeedAddr          string `json:"seed_addr"`
+	Role              uint   `json:"role"`
+	ConcurrencyLevel  int    `json:"concurrency_level,omitempty"`
+	NormalTxPoolSize  int    `json:"normal_tx_pool,omitempty"`
+	PatchTxPoolSize   int    `json:"patch_tx_pool,omitempty"`
+	MaxBlockTxBytes   int    `json:"max_block_tx_bytes,omitempty"`
+	NodeCache         string `json:"node_cache,omitempty"`
+	AutoStart         bool   `json:"auto_start,omitempty"`
+	ChildrenLimit     *int   `json:"children_limit,omitempty"`
+	NephewsLimit      *int   `json:"nephews_limit,omitempty"`
+	PeerBanThreshold  *int   `json:"peer_ban_threshold,omitempty"`
+	PeerScoreHalfLife int64  `json:"peer_score_half_life,omitempty"`
+	PeerBanDuration   int64  `json:"peer_ban_duration,omitempty"`
+	BandwidthLimits   string `json:"bandwidth_limits,omitempty"`
+	PacketCapture     string `json:"packet_capture,omitempty"`
+	ValidateTxOnSend  bool   `json:"validate_tx_on_send,omitempty"`
+	LogIndex          bool   `json:"log_index,omitempty"`
+	TxIndex           bool   `json:"tx_index,omitempty"`
+	PruningWindow     int64  `json:"pruning_window,omitempty"`
+	PruningCheckpoint int64  `json:"pruning_checkpoint,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	IndexerRetryDelayMin = time.Second
+	IndexerRetryDelayMax = time.Minute
+)
+
+const (
+	IndexerStopped  = "stopped"
+	IndexerRunning  = "running"
+	IndexerRetrying = "retrying"
+)
+
+// indexerStatus keeps the status of an indexer to be inspected.
+type indexerStatus struct {
+	lock    sync.Mutex
+	state   string
+	height  int64
+	retries int
+	lastErr error
+}
+
+func (s *indexerStatus) setState(state string, height int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.state = state
+	s.height = height
+	s.retries = 0
+	s.lastErr = nil
+}
+
+// onFailure records the error, and returns the delay before retrying.
+// The delay doubles for each consecutive failure up to
+// IndexerRetryDelayMax.
+func (s *indexerStatus) onFailure(err error) time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.state = IndexerRetrying
+	s.lastErr = err
+	delay := IndexerRetryDelayMin
+	for i := 0; i < s.retries && delay < IndexerRetryDelayMax; i++ {
+		delay *= 2
+	}
+	if delay > IndexerRetryDelayMax {
+		delay = IndexerRetryDelayMax
+	}
+	s.retries += 1
+	return delay
+}
+
+// Status returns the state, the next height to be indexed, number of
+// consecutive failures and the last error of them.
+func (s *indexerStatus) Status() map[string]interface{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	m := map[string]interface{}{
+		"state":  s.state,
+		"height": s.height,
+	}
+	if s.state == "" {
+		m["state"] = IndexerStopped
+	}
+	if s.lastErr != nil {
+		m["retries"] = s.retries
+		m["lastError"] = s.lastErr.Error()
+	}
+	return m
+}
+
+// runIndexer calls index for each block from the height as soon as the
+// next block is finalized. Failures are retried with backoff until stop
+// is closed.
+func runIndexer(
+	name string, bm module.BlockManager, logger log.Logger, st *indexerStatus,
+	height int64, index func(height int64) error,
+	stop <-chan struct{}, done chan<- struct{},
+) {
+	defer close(done)
+	defer func() {
+		st.lock.Lock()
+		st.state = IndexerStopped
+		st.lock.Unlock()
+	}()
+
+	st.setState(IndexerRunning, height)
+	for {
+		err := waitForBlock(bm, height+1, stop)
+		if err == errStopped {
+			return
+		}
+		if err == nil {
+			err = index(height)
+		}
+		if err != nil {
+			delay := st.onFailure(err)
+			logger.Warnf("%s: fail to index height=%d retry after %s err=%+v",
+				name, height, delay, err)
+			select {
+			case <-stop:
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+		height += 1
+		st.setState(IndexerRunning, height)
+	}
+}
+
+var errStopped = errors.New("Stopped")
+
+func waitForBlock(bm module.BlockManager, height int64, stop <-chan struct{}) error {
+	bch, err := bm.WaitForBlock(height)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-stop:
+		return errStopped
+	case _, ok := <-bch:
+		if !ok {
+			return errors.InvalidStateError.Errorf("FailToWaitBlock(height=%d)", height)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestIndexerStatus(t *testing.T) {
+	var st indexerStatus
+	assert.Equal(t, map[string]interface{}{
+		"state":  IndexerStopped,
+		"height": int64(0),
+	}, st.Status())
+
+	st.setState(IndexerRunning, 10)
+	assert.Equal(t, map[string]interface{}{
+		"state":  IndexerRunning,
+		"height": int64(10),
+	}, st.Status())
+
+	err := errors.NotFoundError.New("NoReceipt")
+	delay := IndexerRetryDelayMin
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, delay, st.onFailure(err))
+		if delay *= 2; delay > IndexerRetryDelayMax {
+			delay = IndexerRetryDelayMax
+		}
+	}
+	assert.Equal(t, map[string]interface{}{
+		"state":     IndexerRetrying,
+		"height":    int64(10),
+		"retries":   10,
+		"lastError": err.Error(),
+	}, st.Status())
+
+	st.setState(IndexerRunning, 11)
+	assert.Equal(t, IndexerRetryDelayMin, st.onFailure(err))
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"encoding/binary"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	keyLogIndexRange = "index.logRange"
+)
+
+// LogLocator locates an event log of a normal transaction.
+type LogLocator struct {
+	Height   int64
+	TxIndex  int
+	LogIndex int
+}
+
+func (l LogLocator) Compare(l2 LogLocator) int {
+	switch {
+	case l.Height != l2.Height:
+		return compareInt64(l.Height, l2.Height)
+	case l.TxIndex != l2.TxIndex:
+		return compareInt64(int64(l.TxIndex), int64(l2.TxIndex))
+	default:
+		return compareInt64(int64(l.LogIndex), int64(l2.LogIndex))
+	}
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+type logRange struct {
+	From int64
+	To   int64
+}
+
+// LogIndex maps event logs from their signature and address.
+// It only keeps locators of the logs, so the logs need to be retrieved
+// from the receipts. Locators are stored for each height, so indexing
+// a block doesn't need to update entries of other heights.
+type LogIndex struct {
+	bk    db.Bucket
+	index *db.CodedBucket
+	props *db.CodedBucket
+}
+
+func NewLogIndex(dbase db.Database) (*LogIndex, error) {
+	bk, err := dbase.GetBucket(db.EventLogIndex)
+	if err != nil {
+		return nil, err
+	}
+	props, err := db.NewCodedBucket(dbase, db.ChainProperty, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LogIndex{
+		bk:    bk,
+		index: db.NewCodedBucketFromBucket(bk, nil, nil),
+		props: props,
+	}, nil
+}
+
+// Range returns range of indexed heights [from, to).
+// It returns errors.NotFoundError if nothing is indexed.
+func (li *LogIndex) Range() (int64, int64, error) {
+	var r logRange
+	if err := li.props.Get(db.Raw(keyLogIndexRange), &r); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return 0, 0, errors.NotFoundError.New("LogIndexNotEnabled")
+		}
+		return 0, 0, err
+	}
+	return r.From, r.To, nil
+}
+
+func (li *LogIndex) setRange(from, to int64) error {
+	return li.props.Set(db.Raw(keyLogIndexRange), &logRange{from, to})
+}
+
+func logPrefixOf(addr module.Address, sig []byte) []byte {
+	if addr != nil {
+		buf := make([]byte, 0, len(sig)+len(addr.Bytes()))
+		buf = append(buf, addr.Bytes()...)
+		buf = append(buf, sig...)
+		return crypto.SHA3Sum256(buf)
+	}
+	return crypto.SHA3Sum256(sig)
+}
+
+func logKeyOf(prefix []byte, height int64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], uint64(height))
+	return key
+}
+
+// indexLogs adds event logs of normal transactions at the height.
+// rl is the list of receipts for the transactions, which is included in
+// the result of the next block.
+func (li *LogIndex) indexLogs(height int64, rl module.ReceiptList) error {
+	locs := make(map[string][]LogLocator)
+	var keys []string
+	add := func(key []byte, loc LogLocator) {
+		k := string(key)
+		if _, ok := locs[k]; !ok {
+			keys = append(keys, k)
+		}
+		locs[k] = append(locs[k], loc)
+	}
+	txIndex := 0
+	for rit := rl.Iterator(); rit.Has(); _, txIndex = rit.Next(), txIndex+1 {
+		r, err := rit.Get()
+		if err != nil {
+			return err
+		}
+		for it, idx := r.EventLogIterator(), 0; it.Has(); _, idx = it.Next(), idx+1 {
+			el, err := it.Get()
+			if err != nil {
+				return err
+			}
+			indexed := el.Indexed()
+			if len(indexed) < 1 {
+				continue
+			}
+			loc := LogLocator{
+				Height:   height,
+				TxIndex:  txIndex,
+				LogIndex: idx,
+			}
+			add(logKeyOf(logPrefixOf(nil, indexed[0]), height), loc)
+			add(logKeyOf(logPrefixOf(el.Address(), indexed[0]), height), loc)
+		}
+	}
+	// locators of a key are already in order, and indexing the same
+	// height again just overwrites them.
+	for _, k := range keys {
+		if err := li.index.Set(db.Raw(k), locs[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find calls cb for each locator of the logs with the signature in
+// the range of heights [from, to] in order. If addr is not nil, then it
+// only returns the logs from the address. It stops iteration if cb returns
+// false or an error.
+func (li *LogIndex) Find(
+	addr module.Address, sig []byte, from, to int64,
+	cb func(loc LogLocator) (bool, error),
+) error {
+	if from > to {
+		return nil
+	}
+	prefix := logPrefixOf(addr, sig)
+	iter, err := db.NewIterator(li.bk, &db.Range{
+		Start: logKeyOf(prefix, from),
+		Limit: logKeyOf(prefix, to+1),
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+	for iter.Next() {
+		var entries []LogLocator
+		if _, err := codec.BC.UnmarshalFromBytes(iter.Value(), &entries); err != nil {
+			return errors.CriticalFormatError.Wrap(err, "InvalidLogIndex")
+		}
+		for _, loc := range entries {
+			if cont, err := cb(loc); err != nil || !cont {
+				return err
+			}
+		}
+	}
+	return iter.Error()
+}
+
+// IndexLogsAt indexes event logs of the transactions at the height.
+// The block at height+1 should be finalized.
+func (li *LogIndex) IndexLogsAt(
+	bm module.BlockManager, sm module.ServiceManager, height int64,
+) error {
+	rblk, err := bm.GetBlockByHeight(height + 1)
+	if err != nil {
+		return err
+	}
+	rl, err := sm.ReceiptListFromResult(rblk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return err
+	}
+	return li.indexLogs(height, rl)
+}
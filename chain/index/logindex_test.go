@@ -0,0 +1,123 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const testHeight = 128
+
+func newTestReceiptList(dbase db.Database, logs ...[]module.Address) module.ReceiptList {
+	var rs []txresult.Receipt
+	to := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	for _, addrs := range logs {
+		r := txresult.NewReceipt(dbase, 0, to)
+		for _, addr := range addrs {
+			r.AddLog(addr, [][]byte{[]byte("Transfer(Address,int)"), addr.Bytes()}, nil)
+		}
+		r.SetResult(module.StatusSuccess, big.NewInt(0), big.NewInt(0), nil)
+		rs = append(rs, r)
+	}
+	return txresult.NewReceiptListFromSlice(dbase, rs)
+}
+
+func findAll(t *testing.T, li *LogIndex, addr module.Address, from, to int64) []LogLocator {
+	var locs []LogLocator
+	err := li.Find(addr, []byte("Transfer(Address,int)"), from, to, func(loc LogLocator) (bool, error) {
+		locs = append(locs, loc)
+		return true, nil
+	})
+	assert.NoError(t, err)
+	return locs
+}
+
+func TestLogIndex_Basic(t *testing.T) {
+	dbase := db.NewMapDB()
+	li, err := NewLogIndex(dbase)
+	assert.NoError(t, err)
+
+	_, _, err = li.Range()
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	addr1 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000011")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000012")
+
+	rl1 := newTestReceiptList(dbase, []module.Address{addr1, addr2}, nil, []module.Address{addr2})
+	rl2 := newTestReceiptList(dbase, []module.Address{addr1})
+
+	assert.NoError(t, li.indexLogs(testHeight-1, rl1))
+	assert.NoError(t, li.indexLogs(testHeight+1, rl2))
+	// indexing the same block again shouldn't make duplicate entries
+	assert.NoError(t, li.indexLogs(testHeight+1, rl2))
+	assert.NoError(t, li.setRange(testHeight-1, testHeight+2))
+
+	from, to, err := li.Range()
+	assert.NoError(t, err)
+	assert.EqualValues(t, testHeight-1, from)
+	assert.EqualValues(t, testHeight+2, to)
+
+	locs := findAll(t, li, nil, 0, testHeight*2)
+	assert.Equal(t, []LogLocator{
+		{testHeight - 1, 0, 0},
+		{testHeight - 1, 0, 1},
+		{testHeight - 1, 2, 0},
+		{testHeight + 1, 0, 0},
+	}, locs)
+
+	locs = findAll(t, li, addr2, 0, testHeight*2)
+	assert.Equal(t, []LogLocator{
+		{testHeight - 1, 0, 1},
+		{testHeight - 1, 2, 0},
+	}, locs)
+
+	locs = findAll(t, li, addr1, testHeight, testHeight+1)
+	assert.Equal(t, []LogLocator{
+		{testHeight + 1, 0, 0},
+	}, locs)
+
+	locs = findAll(t, li, nil, testHeight+2, testHeight*2)
+	assert.Empty(t, locs)
+}
+
+func TestLogIndex_OutOfOrder(t *testing.T) {
+	dbase := db.NewMapDB()
+	li, err := NewLogIndex(dbase)
+	assert.NoError(t, err)
+
+	addr := common.MustNewAddressFromString("cx0000000000000000000000000000000000000011")
+	for _, h := range []int64{10, 5, 7} {
+		rl := newTestReceiptList(dbase, []module.Address{addr})
+		assert.NoError(t, li.indexLogs(h, rl))
+	}
+
+	locs := findAll(t, li, addr, 0, 10)
+	assert.Equal(t, []LogLocator{
+		{5, 0, 0},
+		{7, 0, 0},
+		{10, 0, 0},
+	}, locs)
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// LogIndexer keeps the log index up to date with finalized blocks.
+type LogIndexer struct {
+	li  *LogIndex
+	bm  module.BlockManager
+	sm  module.ServiceManager
+	log log.Logger
+
+	status indexerStatus
+
+	lock sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewLogIndexer(
+	dbase db.Database, bm module.BlockManager, sm module.ServiceManager,
+	logger log.Logger,
+) (*LogIndexer, error) {
+	li, err := NewLogIndex(dbase)
+	if err != nil {
+		return nil, err
+	}
+	return &LogIndexer{
+		li:  li,
+		bm:  bm,
+		sm:  sm,
+		log: logger,
+	}, nil
+}
+
+// Start starts indexing. If nothing is indexed yet, it starts from the
+// last finalized block. Older blocks can be indexed with Backfill.
+// Failures are retried with backoff, and they are reported by Status.
+func (i *LogIndexer) Start() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.stop != nil {
+		return errors.InvalidStateError.New("AlreadyStarted")
+	}
+	_, to, err := i.li.Range()
+	if errors.NotFoundError.Equals(err) {
+		blk, err := i.bm.GetLastBlock()
+		if err != nil {
+			return err
+		}
+		to = blk.Height()
+		if err := i.li.setRange(to, to); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	i.stop = make(chan struct{})
+	i.done = make(chan struct{})
+	go runIndexer("LogIndexer", i.bm, i.log, &i.status, to, i.index,
+		i.stop, i.done)
+	return nil
+}
+
+func (i *LogIndexer) index(height int64) error {
+	if err := i.li.IndexLogsAt(i.bm, i.sm, height); err != nil {
+		return err
+	}
+	from, _, err := i.li.Range()
+	if err != nil {
+		return err
+	}
+	return i.li.setRange(from, height+1)
+}
+
+// Status returns the status of the indexer.
+func (i *LogIndexer) Status() map[string]interface{} {
+	return i.status.Status()
+}
+
+// Stop stops indexing and waits for it to finish.
+func (i *LogIndexer) Stop() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.stop == nil {
+		return
+	}
+	close(i.stop)
+	<-i.done
+	i.stop = nil
+	i.done = nil
+}
+
+// BackfillLogIndex indexes event logs of the blocks from the height to
+// the lowest indexed height. It indexes blocks in descending order, so the
+// indexed range stays contiguous even if it's interrupted. cb is called
+// after each block is indexed, and it stops on an error from cb.
+func BackfillLogIndex(
+	dbase db.Database, bm module.BlockManager, sm module.ServiceManager,
+	from int64, cb func(height int64) error,
+) error {
+	li, err := NewLogIndex(dbase)
+	if err != nil {
+		return err
+	}
+	lower, upper, err := li.Range()
+	if errors.NotFoundError.Equals(err) {
+		blk, err := bm.GetLastBlock()
+		if err != nil {
+			return err
+		}
+		lower, upper = blk.Height(), blk.Height()
+		if err := li.setRange(lower, upper); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	for height := lower - 1; height >= from; height-- {
+		if err := li.IndexLogsAt(bm, sm, height); err != nil {
+			return err
+		}
+		if err := li.setRange(height, upper); err != nil {
+			return err
+		}
+		if cb != nil {
+			if err := cb(height); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
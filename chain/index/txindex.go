@@ -0,0 +1,312 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	// txPageSize is number of locators in one entry of the index.
+	txPageSize = 64
+
+	keyTxIndexHeight = "index.txHeight"
+)
+
+// TxLocator locates a normal transaction.
+type TxLocator struct {
+	Height  int64
+	TxIndex int
+}
+
+func (l TxLocator) Compare(l2 TxLocator) int {
+	if l.Height != l2.Height {
+		return compareInt64(l.Height, l2.Height)
+	}
+	return compareInt64(int64(l.TxIndex), int64(l2.TxIndex))
+}
+
+// TxIndex maps transactions from the related addresses. Senders,
+// recipients and targets of the transfers are related to the transaction.
+// Locators for an address are kept in ascending order, and each of them
+// can be accessed by its position.
+type TxIndex struct {
+	index *db.CodedBucket
+	props *db.CodedBucket
+}
+
+func NewTxIndex(dbase db.Database) (*TxIndex, error) {
+	index, err := db.NewCodedBucket(dbase, db.TransactionsByAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	props, err := db.NewCodedBucket(dbase, db.ChainProperty, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TxIndex{
+		index: index,
+		props: props,
+	}, nil
+}
+
+// Height returns the next height to be indexed. All the transactions
+// below the height are indexed.
+// It returns errors.NotFoundError if nothing is indexed.
+func (ti *TxIndex) Height() (int64, error) {
+	var height int64
+	if err := ti.props.Get(db.Raw(keyTxIndexHeight), &height); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return 0, errors.NotFoundError.New("TxIndexNotEnabled")
+		}
+		return 0, err
+	}
+	return height, nil
+}
+
+func (ti *TxIndex) setHeight(height int64) error {
+	return ti.props.Set(db.Raw(keyTxIndexHeight), height)
+}
+
+func txPageKeyOf(addr module.Address, page int64) []byte {
+	bs := addr.Bytes()
+	key := make([]byte, len(bs)+8)
+	copy(key, bs)
+	binary.BigEndian.PutUint64(key[len(bs):], uint64(page))
+	return key
+}
+
+// Count returns number of transactions related to the address.
+func (ti *TxIndex) Count(addr module.Address) (int64, error) {
+	var count int64
+	if err := ti.index.Get(db.Raw(addr.Bytes()), &count); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return count, nil
+}
+
+func (ti *TxIndex) getPage(addr module.Address, page int64) ([]TxLocator, error) {
+	var entries []TxLocator
+	if err := ti.index.Get(db.Raw(txPageKeyOf(addr, page)), &entries); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entries, nil
+}
+
+// addLocators appends locators in ascending order. Locators not greater
+// than the last one are ignored, so indexing the same block again has
+// no effect.
+func (ti *TxIndex) addLocators(addr module.Address, locs []TxLocator) error {
+	count, err := ti.Count(addr)
+	if err != nil {
+		return err
+	}
+	var page int64
+	var entries []TxLocator
+	if count > 0 {
+		page = (count - 1) / txPageSize
+		if entries, err = ti.getPage(addr, page); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return errors.InvalidStateError.Errorf(
+				"NoLastPage(addr=%s,count=%d)", addr, count)
+		}
+	}
+	updated := false
+	for _, loc := range locs {
+		if len(entries) > 0 && entries[len(entries)-1].Compare(loc) >= 0 {
+			continue
+		}
+		if len(entries) == txPageSize {
+			if err := ti.index.Set(db.Raw(txPageKeyOf(addr, page)), entries); err != nil {
+				return err
+			}
+			page, entries, updated = page+1, nil, false
+		}
+		entries = append(entries, loc)
+		count += 1
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+	if err := ti.index.Set(db.Raw(txPageKeyOf(addr, page)), entries); err != nil {
+		return err
+	}
+	return ti.index.Set(db.Raw(addr.Bytes()), count)
+}
+
+// indexTransactions adds transactions at the height. addrs has
+// the related addresses for each normal transaction.
+func (ti *TxIndex) indexTransactions(height int64, addrs [][]module.Address) error {
+	locs := make(map[string][]TxLocator)
+	var keys []module.Address
+	for txIndex, as := range addrs {
+		for _, addr := range as {
+			if addr == nil {
+				continue
+			}
+			k := string(addr.Bytes())
+			ll, ok := locs[k]
+			if !ok {
+				keys = append(keys, addr)
+			}
+			loc := TxLocator{Height: height, TxIndex: txIndex}
+			if len(ll) > 0 && ll[len(ll)-1] == loc {
+				continue
+			}
+			locs[k] = append(ll, loc)
+		}
+	}
+	for _, addr := range keys {
+		if err := ti.addLocators(addr, locs[string(addr.Bytes())]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find calls cb for each locator of the transactions related to
+// the address, from the position before-1 down to 0, so the latest
+// transaction comes first. It stops iteration if cb returns false or
+// an error.
+func (ti *TxIndex) Find(
+	addr module.Address, before int64,
+	cb func(pos int64, loc TxLocator) (bool, error),
+) error {
+	if before <= 0 {
+		return nil
+	}
+	for page := (before - 1) / txPageSize; page >= 0; page-- {
+		entries, err := ti.getPage(addr, page)
+		if err != nil {
+			return err
+		}
+		for idx := len(entries) - 1; idx >= 0; idx-- {
+			pos := page*txPageSize + int64(idx)
+			if pos >= before {
+				continue
+			}
+			if cont, err := cb(pos, entries[idx]); err != nil || !cont {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type genesisJSON struct {
+	Accounts []struct {
+		Address common.Address `json:"address"`
+	} `json:"accounts"`
+}
+
+// relatedAddresses returns the addresses related to the transaction with
+// its receipt. Transfers from contracts are found by ICXTransfer event logs
+// of the receipt, so it doesn't need to execute the transaction again.
+// Only the logs emitted by the sender of the transfer are used, and malformed
+// ones are ignored, since any contract may emit logs of the same signature.
+func relatedAddresses(tx module.Transaction, r module.Receipt) ([]module.Address, error) {
+	var addrs []module.Address
+	if from := tx.From(); from != nil {
+		addrs = append(addrs, from)
+	} else {
+		// genesis transaction
+		gjs := new(genesisJSON)
+		if err := json.Unmarshal(tx.Bytes(), gjs); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidGenesis")
+		}
+		for i := range gjs.Accounts {
+			addrs = append(addrs, &gjs.Accounts[i].Address)
+		}
+	}
+	addrs = append(addrs, r.To())
+	for it := r.EventLogIterator(); it.Has(); _ = it.Next() {
+		ev, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		indexed := ev.Indexed()
+		if len(indexed) != 4 || string(indexed[0]) != txresult.EventLogICXTransfer {
+			continue
+		}
+		from, err := common.NewAddress(indexed[1])
+		if err != nil || !from.Equal(ev.Address()) {
+			continue
+		}
+		to, err := common.NewAddress(indexed[2])
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, from, to)
+	}
+	return addrs, nil
+}
+
+// IndexTransactionsAt indexes the transactions at the height with their
+// receipts. The block at height+1 should be finalized.
+func (ti *TxIndex) IndexTransactionsAt(
+	bm module.BlockManager, sm module.ServiceManager, height int64,
+) error {
+	blk, err := bm.GetBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	nblk, err := bm.GetBlockByHeight(height + 1)
+	if err != nil {
+		return err
+	}
+	rl, err := sm.ReceiptListFromResult(nblk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return err
+	}
+
+	var addrs [][]module.Address
+	for it := blk.NormalTransactions().Iterator(); it.Has(); it.Next() {
+		tx, txIndex, err := it.Get()
+		if err != nil {
+			return err
+		}
+		r, err := rl.Get(txIndex)
+		if err != nil {
+			return err
+		}
+		as, err := relatedAddresses(tx, r)
+		if err != nil {
+			return err
+		}
+		addrs = append(addrs, as)
+	}
+	if len(addrs) == 0 {
+		return nil
+	}
+	return ti.indexTransactions(height, addrs)
+}
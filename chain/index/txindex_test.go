@@ -0,0 +1,165 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+func findTxs(t *testing.T, ti *TxIndex, addr module.Address, before int64, limit int) []TxLocator {
+	var locs []TxLocator
+	err := ti.Find(addr, before, func(pos int64, loc TxLocator) (bool, error) {
+		locs = append(locs, loc)
+		return len(locs) < limit, nil
+	})
+	assert.NoError(t, err)
+	return locs
+}
+
+func TestTxIndex_Basic(t *testing.T) {
+	dbase := db.NewMapDB()
+	ti, err := NewTxIndex(dbase)
+	assert.NoError(t, err)
+
+	_, err = ti.Height()
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000011")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000012")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+
+	assert.NoError(t, ti.indexTransactions(1, [][]module.Address{
+		{addr1, score, addr2, addr2},
+		{addr2, addr1},
+	}))
+	// indexing the same block again shouldn't make duplicate entries
+	assert.NoError(t, ti.indexTransactions(1, [][]module.Address{
+		{addr1, score, addr2, addr2},
+		{addr2, addr1},
+	}))
+	assert.NoError(t, ti.indexTransactions(2, [][]module.Address{
+		{addr1, nil},
+	}))
+	assert.NoError(t, ti.setHeight(3))
+
+	height, err := ti.Height()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, height)
+
+	cnt, err := ti.Count(addr1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, cnt)
+	assert.Equal(t, []TxLocator{{2, 0}, {1, 1}, {1, 0}}, findTxs(t, ti, addr1, cnt, 10))
+	assert.Equal(t, []TxLocator{{1, 1}}, findTxs(t, ti, addr1, 2, 1))
+
+	cnt, err = ti.Count(addr2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, cnt)
+	assert.Equal(t, []TxLocator{{1, 1}, {1, 0}}, findTxs(t, ti, addr2, cnt, 10))
+
+	cnt, err = ti.Count(score)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, cnt)
+}
+
+func TestTxIndex_Pages(t *testing.T) {
+	dbase := db.NewMapDB()
+	ti, err := NewTxIndex(dbase)
+	assert.NoError(t, err)
+
+	addr := common.MustNewAddressFromString("hx0000000000000000000000000000000000000011")
+	total := txPageSize*2 + 3
+	for h := 0; h < total; h++ {
+		assert.NoError(t, ti.indexTransactions(int64(h), [][]module.Address{{addr}}))
+	}
+
+	cnt, err := ti.Count(addr)
+	assert.NoError(t, err)
+	assert.EqualValues(t, total, cnt)
+
+	locs := findTxs(t, ti, addr, cnt, total+1)
+	assert.Len(t, locs, total)
+	for i, loc := range locs {
+		assert.EqualValues(t, total-1-i, loc.Height)
+	}
+
+	locs = findTxs(t, ti, addr, txPageSize+1, 2)
+	assert.Equal(t, []TxLocator{{txPageSize, 0}, {txPageSize - 1, 0}}, locs)
+}
+
+type testTransaction struct {
+	module.Transaction
+	from module.Address
+	bs   []byte
+}
+
+func (tx *testTransaction) From() module.Address {
+	return tx.from
+}
+
+func (tx *testTransaction) Bytes() []byte {
+	return tx.bs
+}
+
+func TestTxIndex_RelatedAddresses(t *testing.T) {
+	dbase := db.NewMapDB()
+	sender := common.MustNewAddressFromString("hx0000000000000000000000000000000000000011")
+	receiver := common.MustNewAddressFromString("hx0000000000000000000000000000000000000012")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+
+	r := txresult.NewReceipt(dbase, 0, score)
+	r.AddLog(score, [][]byte{
+		[]byte(txresult.EventLogICXTransfer),
+		score.Bytes(), receiver.Bytes(), intconv.BigIntToBytes(big.NewInt(10)),
+	}, nil)
+	r.AddLog(score, [][]byte{[]byte("Transfer(Address,int)"), sender.Bytes()}, nil)
+	// transfer logs not emitted by the sender
+	other := common.MustNewAddressFromString("hx0000000000000000000000000000000000000013")
+	r.AddLog(score, [][]byte{
+		[]byte(txresult.EventLogICXTransfer),
+		other.Bytes(), other.Bytes(), intconv.BigIntToBytes(big.NewInt(10)),
+	}, nil)
+	// malformed transfer logs
+	r.AddLog(score, [][]byte{
+		[]byte(txresult.EventLogICXTransfer),
+		score.Bytes(), []byte{0x02}, intconv.BigIntToBytes(big.NewInt(10)),
+	}, nil)
+	addrs, err := relatedAddresses(&testTransaction{from: sender}, r)
+	assert.NoError(t, err)
+	assert.Equal(t, []module.Address{sender, score, score, receiver}, addrs)
+
+	gtx := &testTransaction{bs: []byte(`{"accounts":[` +
+		`{"name":"god","address":"hx0000000000000000000000000000000000000011","balance":"0x10"},` +
+		`{"name":"treasury","address":"hx0000000000000000000000000000000000000012"}` +
+		`],"message":"genesis"}`)}
+	r = txresult.NewReceipt(dbase, 0, common.MustNewAddressFromString("cx0000000000000000000000000000000000000000"))
+	addrs, err = relatedAddresses(gtx, r)
+	assert.NoError(t, err)
+	assert.Len(t, addrs, 3)
+	assert.True(t, sender.Equal(addrs[0]))
+	assert.True(t, receiver.Equal(addrs[1]))
+}
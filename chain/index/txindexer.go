@@ -0,0 +1,113 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package index
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// TxIndexer keeps the transaction index up to date with finalized blocks.
+type TxIndexer struct {
+	ti   *TxIndex
+	bm   module.BlockManager
+	sm   module.ServiceManager
+	base int64
+	log  log.Logger
+
+	status indexerStatus
+
+	lock sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewTxIndexer returns a new indexer. base is the lowest height of
+// the chain, where it starts indexing if nothing is indexed yet.
+func NewTxIndexer(
+	dbase db.Database, bm module.BlockManager, sm module.ServiceManager,
+	base int64, logger log.Logger,
+) (*TxIndexer, error) {
+	ti, err := NewTxIndex(dbase)
+	if err != nil {
+		return nil, err
+	}
+	return &TxIndexer{
+		ti:   ti,
+		bm:   bm,
+		sm:   sm,
+		base: base,
+		log:  logger,
+	}, nil
+}
+
+// Start starts indexing. Transactions are indexed in ascending order,
+// so it catches up from the last indexed height before following
+// new blocks. Failures are retried with backoff, and they are reported
+// by Status.
+func (i *TxIndexer) Start() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.stop != nil {
+		return errors.InvalidStateError.New("AlreadyStarted")
+	}
+	height, err := i.ti.Height()
+	if errors.NotFoundError.Equals(err) {
+		height = i.base
+		if err := i.ti.setHeight(height); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	i.stop = make(chan struct{})
+	i.done = make(chan struct{})
+	go runIndexer("TxIndexer", i.bm, i.log, &i.status, height, i.index,
+		i.stop, i.done)
+	return nil
+}
+
+func (i *TxIndexer) index(height int64) error {
+	if err := i.ti.IndexTransactionsAt(i.bm, i.sm, height); err != nil {
+		return err
+	}
+	return i.ti.setHeight(height + 1)
+}
+
+// Status returns the status of the indexer.
+func (i *TxIndexer) Status() map[string]interface{} {
+	return i.status.Status()
+}
+
+// Stop stops indexing and waits for it to finish.
+func (i *TxIndexer) Stop() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.stop == nil {
+		return
+	}
+	close(i.stop)
+	<-i.done
+	i.stop = nil
+	i.done = nil
+}
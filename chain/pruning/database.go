@@ -0,0 +1,264 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pruning
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	dbFlagTracker = "pruningTracker"
+)
+
+// prunableBuckets maps the buckets having unreferenced entries after
+// pruning to the tags of them in keySet.
+var prunableBuckets = map[db.BucketID]byte{
+	db.MerkleTrie:  'M',
+	db.BytesByHash: 'S',
+}
+
+func bucketOfTag(tag byte) (db.BucketID, bool) {
+	for id, t := range prunableBuckets {
+		if t == tag {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// keySet is a set of keys of the prunable buckets stored in a temporary
+// database. As a database, it returns the values in src for the keys in
+// the set, so it can be the target of merkle.CopyContext without copying
+// the values.
+type keySet struct {
+	src  db.Database
+	keys db.Bucket
+}
+
+func newKeySet(tmp db.Database, id db.BucketID, src db.Database) (*keySet, error) {
+	keys, err := tmp.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &keySet{src: src, keys: keys}, nil
+}
+
+func keyOf(tag byte, key []byte) []byte {
+	k := make([]byte, 0, len(key)+1)
+	k = append(k, tag)
+	return append(k, key...)
+}
+
+func (s *keySet) add(id db.BucketID, key []byte) error {
+	return s.keys.Set(keyOf(prunableBuckets[id], key), []byte{1})
+}
+
+func (s *keySet) has(id db.BucketID, key []byte) (bool, error) {
+	return s.keys.Has(keyOf(prunableBuckets[id], key))
+}
+
+func (s *keySet) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := s.src.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	_, prunable := prunableBuckets[id]
+	return &keySetBucket{set: s, id: id, src: bk, prunable: prunable}, nil
+}
+
+func (s *keySet) Close() error {
+	return nil
+}
+
+type keySetBucket struct {
+	set      *keySet
+	id       db.BucketID
+	src      db.Bucket
+	prunable bool
+}
+
+func (bk *keySetBucket) Get(key []byte) ([]byte, error) {
+	if !bk.prunable {
+		return bk.src.Get(key)
+	}
+	if has, err := bk.set.has(bk.id, key); err != nil || !has {
+		return nil, err
+	}
+	return bk.src.Get(key)
+}
+
+func (bk *keySetBucket) Has(key []byte) (bool, error) {
+	if !bk.prunable {
+		return bk.src.Has(key)
+	}
+	return bk.set.has(bk.id, key)
+}
+
+func (bk *keySetBucket) Set(key []byte, value []byte) error {
+	if !bk.prunable {
+		return errors.UnsupportedError.Errorf("NotPrunableBucket(id=%q)", bk.id)
+	}
+	return bk.set.add(bk.id, key)
+}
+
+func (bk *keySetBucket) Delete(key []byte) error {
+	return errors.UnsupportedError.New("DeleteOnKeySet")
+}
+
+// tracker records the keys written to the prunable buckets while pruning
+// is in progress, so the entries written by new blocks are not swept even
+// if they were unreferenced before.
+type tracker struct {
+	db.Database
+
+	lock    sync.RWMutex
+	written *keySet
+}
+
+func (t *tracker) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := t.Database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := prunableBuckets[id]; !ok {
+		return bk, nil
+	}
+	return &trackedBucket{Bucket: bk, tracker: t, id: id}, nil
+}
+
+// NewBatch returns the batch of the underlying database, which records the
+// keys of the prunable buckets on Write.
+func (t *tracker) NewBatch() db.Batch {
+	return &trackedBatch{Batch: db.NewBatch(t.Database), tracker: t}
+}
+
+func (t *tracker) startTracking(written *keySet) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.written = written
+}
+
+func (t *tracker) stopTracking() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.written = nil
+}
+
+// deleteUnless deletes the keys which are not in marks and not written
+// since it starts tracking. Writes are blocked while it deletes the keys.
+func (t *tracker) deleteUnless(id db.BucketID, keys [][]byte, marks *keySet) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	batch := db.NewBatch(t.Database)
+	for _, key := range keys {
+		if has, err := marks.has(id, key); err != nil {
+			return 0, err
+		} else if has {
+			continue
+		}
+		if t.written != nil {
+			if has, err := t.written.has(id, key); err != nil {
+				return 0, err
+			} else if has {
+				continue
+			}
+		}
+		if err := batch.Delete(id, key); err != nil {
+			return 0, err
+		}
+	}
+	cnt := batch.Len()
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+type trackedBucket struct {
+	db.Bucket
+	tracker *tracker
+	id      db.BucketID
+}
+
+func (bk *trackedBucket) Set(key []byte, value []byte) error {
+	bk.tracker.lock.RLock()
+	defer bk.tracker.lock.RUnlock()
+	if bk.tracker.written != nil {
+		if err := bk.tracker.written.add(bk.id, key); err != nil {
+			return err
+		}
+	}
+	return bk.Bucket.Set(key, value)
+}
+
+type trackedKey struct {
+	id  db.BucketID
+	key []byte
+}
+
+type trackedBatch struct {
+	db.Batch
+	tracker *tracker
+	keys    []trackedKey
+}
+
+func (b *trackedBatch) Set(id db.BucketID, key, value []byte) error {
+	if err := b.Batch.Set(id, key, value); err != nil {
+		return err
+	}
+	if _, ok := prunableBuckets[id]; ok {
+		b.keys = append(b.keys, trackedKey{id: id, key: append([]byte{}, key...)})
+	}
+	return nil
+}
+
+func (b *trackedBatch) Write() error {
+	b.tracker.lock.RLock()
+	defer b.tracker.lock.RUnlock()
+	if b.tracker.written != nil {
+		for _, k := range b.keys {
+			if err := b.tracker.written.add(k.id, k.key); err != nil {
+				return err
+			}
+		}
+	}
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	b.keys = nil
+	return nil
+}
+
+// AttachTracker returns the database tracking writes on it for online
+// pruning. Pruner requires the database returned by it.
+func AttachTracker(database db.Database) db.Database {
+	t := &tracker{Database: database}
+	return db.WithFlags(t, db.Flags{
+		dbFlagTracker: t,
+	})
+}
+
+func trackerOf(database db.Database) *tracker {
+	if t, ok := db.GetFlag(database, dbFlagTracker).(*tracker); ok {
+		return t
+	}
+	return nil
+}
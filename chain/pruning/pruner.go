@@ -0,0 +1,396 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pruning
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	// MinBlocksInCycle is minimum number of blocks to prune at once.
+	// Marking walks whole state to keep, so it shouldn't run too often.
+	MinBlocksInCycle = 1000
+
+	// MaxBlocksInCycle is maximum number of blocks to prune at once.
+	MaxBlocksInCycle = 100000
+
+	// MinWindow is minimum number of recent blocks keeping their state.
+	MinWindow = 16
+
+	keysInSweep = 1000
+
+	keyPruningState = "pruning.state"
+
+	tmpDBName = "pruning"
+)
+
+const (
+	bucketCandidates db.BucketID = "c"
+	bucketMarks      db.BucketID = "m"
+	bucketWritten    db.BucketID = "w"
+)
+
+type pruningState struct {
+	// Height is the lowest height of the blocks not pruned yet.
+	Height int64
+
+	// Checkpoints are the heights lower than Height keeping their state.
+	Checkpoints []int64
+}
+
+// Pruner keeps the state only for the recent blocks and the checkpoints.
+// It prunes the state of older blocks periodically with mark-and-sweep.
+//
+// Entries of MerkleTrie and BytesByHash reachable from the world state and
+// the extension of the blocks to prune become candidates. Then, entries
+// reachable from the state of the blocks keeping their state are marked.
+// Receipts are not pruned, so they are not exported for the candidates. Candidates which
+// are not marked and not written during the cycle are deleted.
+type Pruner struct {
+	dbase      db.Database
+	tracker    *tracker
+	props      *db.CodedBucket
+	bm         module.BlockManager
+	sm         module.ServiceManager
+	base       int64
+	window     int64
+	checkpoint int64
+	tmpDir     string
+	tmpType    string
+	log        log.Logger
+
+	lock sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPruner returns a new pruner. dbase should be the one returned by
+// AttachTracker. base is the lowest height of the chain, window is number
+// of recent blocks keeping their state, and the state of the blocks at the
+// multiples of checkpoint are also kept if checkpoint is positive.
+// Key sets for each cycle are stored in the temporary database of tmpType
+// at tmpDir.
+func NewPruner(
+	dbase db.Database, bm module.BlockManager, sm module.ServiceManager,
+	base, window, checkpoint int64, tmpDir, tmpType string, logger log.Logger,
+) (*Pruner, error) {
+	t := trackerOf(dbase)
+	if t == nil {
+		return nil, errors.InvalidStateError.New("NoTrackerAttached")
+	}
+	if window < MinWindow {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidWindow(window=%d,min=%d)", window, MinWindow)
+	}
+	if checkpoint < 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidCheckpoint(checkpoint=%d)", checkpoint)
+	}
+	props, err := db.NewCodedBucket(dbase, db.ChainProperty, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Pruner{
+		dbase:      dbase,
+		tracker:    t,
+		props:      props,
+		bm:         bm,
+		sm:         sm,
+		base:       base,
+		window:     window,
+		checkpoint: checkpoint,
+		tmpDir:     tmpDir,
+		tmpType:    tmpType,
+		log:        logger,
+	}, nil
+}
+
+func (p *Pruner) loadState() (*pruningState, error) {
+	st := new(pruningState)
+	if err := p.props.Get(db.Raw(keyPruningState), st); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return &pruningState{Height: p.base}, nil
+		}
+		return nil, err
+	}
+	return st, nil
+}
+
+// PrunedHeight returns the lowest height of the blocks not pruned yet.
+// The state of the blocks lower than it is not available except
+// the checkpoints.
+func (p *Pruner) PrunedHeight() (int64, error) {
+	st, err := p.loadState()
+	if err != nil {
+		return 0, err
+	}
+	return st.Height, nil
+}
+
+// Start starts pruning in background.
+func (p *Pruner) Start() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stop != nil {
+		return errors.InvalidStateError.New("AlreadyStarted")
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(p.stop, p.done)
+	return nil
+}
+
+func (p *Pruner) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	for {
+		st, err := p.loadState()
+		if err != nil {
+			p.log.Errorf("Pruner: fail to load state err=%+v", err)
+			return
+		}
+		// prune when the blocks to prune are enough
+		if err := p.waitForBlock(st.Height+p.window+MinBlocksInCycle-1, stop); err != nil {
+			if !errors.InterruptedError.Equals(err) {
+				p.log.Warnf("Pruner: fail to wait block err=%+v", err)
+			}
+			return
+		}
+		last, err := p.bm.GetLastBlock()
+		if err != nil {
+			p.log.Errorf("Pruner: fail to get last block err=%+v", err)
+			return
+		}
+		to := last.Height() - p.window + 1
+		if to > st.Height+MaxBlocksInCycle {
+			to = st.Height + MaxBlocksInCycle
+		}
+		if err := p.prune(st, to, stop); err != nil {
+			if !errors.InterruptedError.Equals(err) {
+				p.log.Errorf("Pruner: fail to prune from=%d to=%d err=%+v",
+					st.Height, to, err)
+			}
+			return
+		}
+	}
+}
+
+func (p *Pruner) waitForBlock(height int64, stop <-chan struct{}) error {
+	bch, err := p.bm.WaitForBlock(height)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-stop:
+		return errors.ErrInterrupted
+	case _, ok := <-bch:
+		if !ok {
+			return errors.ErrInterrupted
+		}
+		return nil
+	}
+}
+
+func (p *Pruner) isCheckpoint(height int64) bool {
+	return p.checkpoint > 0 && height%p.checkpoint == 0
+}
+
+// exportResults exports the state of the results of the blocks to the set. For the
+// results to keep, validator lists are also exported, and missing entries
+// are regarded as failure. Otherwise, missing entries are ignored as they
+// may be deleted by the interrupted cycle.
+func (p *Pruner) exportResults(set *keySet, heights []int64, retained bool, stop <-chan struct{}) error {
+	for _, height := range heights {
+		blk, err := p.bm.GetBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		ctx := merkle.NewCopyContext(p.dbase, set)
+		ctx.SetProgressCallback(func(height int64, resolved, unresolved int) error {
+			select {
+			case <-stop:
+				return errors.ErrInterrupted
+			default:
+				return nil
+			}
+		})
+		var vh []byte
+		if retained {
+			vh = blk.NextValidatorsHash()
+		}
+		err = p.sm.ExportState(blk.Result(), vh, ctx.TargetDB())
+		if !retained && errors.NotFoundError.Equals(err) {
+			p.log.Debugf("Pruner: ignore missing entry height=%d err=%v", height, err)
+		} else if err != nil {
+			return errors.Wrapf(err, "fail to export result height=%d", height)
+		}
+	}
+	return nil
+}
+
+// prune prunes the state of the blocks in [st.Height, to) except
+// the checkpoints.
+func (p *Pruner) prune(st *pruningState, to int64, stop <-chan struct{}) error {
+	from := st.Height
+	p.log.Infof("Pruner: start pruning from=%d to=%d", from, to)
+
+	_ = os.RemoveAll(p.tmpDir)
+	tmp, err := db.Open(p.tmpDir, p.tmpType, tmpDBName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.RemoveAll(p.tmpDir)
+	}()
+	candidates, err := newKeySet(tmp, bucketCandidates, p.dbase)
+	if err != nil {
+		return err
+	}
+	marks, err := newKeySet(tmp, bucketMarks, p.dbase)
+	if err != nil {
+		return err
+	}
+	written, err := newKeySet(tmp, bucketWritten, p.dbase)
+	if err != nil {
+		return err
+	}
+
+	last, err := p.bm.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	p.tracker.startTracking(written)
+	defer p.tracker.stopTracking()
+
+	var pruned []int64
+	checkpoints := append([]int64{}, st.Checkpoints...)
+	for height := from; height < to; height++ {
+		if p.isCheckpoint(height) {
+			checkpoints = append(checkpoints, height)
+		} else {
+			pruned = append(pruned, height)
+		}
+	}
+	if err := p.exportResults(candidates, pruned, false, stop); err != nil {
+		return err
+	}
+
+	// results written before tracking are referenced by the blocks up to
+	// the next of the last block.
+	if err := p.waitForBlock(last.Height()+1, stop); err != nil {
+		return err
+	}
+	if last, err = p.bm.GetLastBlock(); err != nil {
+		return err
+	}
+	retained := append([]int64{}, checkpoints...)
+	for height := to; height <= last.Height(); height++ {
+		retained = append(retained, height)
+	}
+	if err := p.exportResults(marks, retained, true, stop); err != nil {
+		return err
+	}
+
+	cnt, err := p.sweep(candidates, marks, stop)
+	if err != nil {
+		return err
+	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i] < checkpoints[j]
+	})
+	if err := p.props.Set(db.Raw(keyPruningState), &pruningState{
+		Height:      to,
+		Checkpoints: checkpoints,
+	}); err != nil {
+		return err
+	}
+	p.log.Infof("Pruner: finish pruning from=%d to=%d deleted=%d", from, to, cnt)
+	return nil
+}
+
+// sweep deletes the candidates which are not marked.
+func (p *Pruner) sweep(candidates, marks *keySet, stop <-chan struct{}) (int, error) {
+	itr, err := db.NewIterator(candidates.keys, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer itr.Release()
+
+	var cnt int
+	keys := make(map[db.BucketID][][]byte)
+	flush := func() error {
+		for id, ks := range keys {
+			n, err := p.tracker.deleteUnless(id, ks, marks)
+			if err != nil {
+				return err
+			}
+			cnt += n
+			delete(keys, id)
+		}
+		return nil
+	}
+	var n int
+	for itr.Next() {
+		key := itr.Key()
+		id, ok := bucketOfTag(key[0])
+		if !ok {
+			return cnt, errors.InvalidStateError.Errorf("InvalidCandidate(key=%#x)", key)
+		}
+		keys[id] = append(keys[id], append([]byte{}, key[1:]...))
+		if n += 1; n%keysInSweep == 0 {
+			select {
+			case <-stop:
+				return cnt, errors.ErrInterrupted
+			default:
+			}
+			if err := flush(); err != nil {
+				return cnt, err
+			}
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return cnt, err
+	}
+	if err := flush(); err != nil {
+		return cnt, err
+	}
+	return cnt, nil
+}
+
+// Stop stops pruning and waits for it to finish. Interrupted cycle is
+// started again on next start.
+func (p *Pruner) Stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	p.stop = nil
+	p.done = nil
+}
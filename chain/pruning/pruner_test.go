@@ -0,0 +1,232 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pruning
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/module"
+)
+
+type testBlock struct {
+	module.Block
+	height int64
+	result []byte
+}
+
+func (b *testBlock) Height() int64              { return b.height }
+func (b *testBlock) Result() []byte             { return b.result }
+func (b *testBlock) NextValidatorsHash() []byte { return nil }
+
+type testBlockManager struct {
+	module.BlockManager
+	blocks []*testBlock
+	last   int64
+}
+
+func (bm *testBlockManager) GetLastBlock() (module.Block, error) {
+	return bm.blocks[bm.last], nil
+}
+
+func (bm *testBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	return bm.blocks[height], nil
+}
+
+func (bm *testBlockManager) WaitForBlock(height int64) (<-chan module.Block, error) {
+	if height > bm.last {
+		bm.last = height
+	}
+	bch := make(chan module.Block, 1)
+	bch <- bm.blocks[height]
+	return bch, nil
+}
+
+type testResult struct {
+	State    []byte
+	Receipts []byte
+}
+
+type testServiceManager struct {
+	module.ServiceManager
+	dbase db.Database
+}
+
+func (sm *testServiceManager) export(result []byte, receipts bool, dst db.Database) error {
+	var r testResult
+	codec.MustUnmarshalFromBytes(result, &r)
+	ctx := merkle.PrepareCopyContext(sm.dbase, dst)
+	trie_manager.NewImmutable(ctx.Builder().Database(), r.State).Resolve(ctx.Builder())
+	if receipts {
+		trie_manager.NewImmutable(ctx.Builder().Database(), r.Receipts).Resolve(ctx.Builder())
+	}
+	return ctx.Run()
+}
+
+func (sm *testServiceManager) ExportResult(result []byte, vh []byte, dst db.Database) error {
+	return sm.export(result, true, dst)
+}
+
+func (sm *testServiceManager) ExportState(result []byte, vh []byte, dst db.Database) error {
+	return sm.export(result, false, dst)
+}
+
+func TestPruner_prune(t *testing.T) {
+	dbase := AttachTracker(db.NewMapDB())
+	sm := &testServiceManager{dbase: dbase}
+	bm := &testBlockManager{}
+
+	mt := trie_manager.NewMutable(dbase, nil)
+	for height := int64(0); height <= 20; height++ {
+		_, err := mt.Set([]byte(fmt.Sprintf("key%d", height%4)), []byte(fmt.Sprintf("value%d", height)))
+		assert.NoError(t, err)
+		_, err = mt.Set([]byte("fixed"), []byte("value"))
+		assert.NoError(t, err)
+		ss := mt.GetSnapshot()
+		assert.NoError(t, ss.Flush())
+
+		rt := trie_manager.NewMutable(dbase, nil)
+		_, err = rt.Set([]byte("receipt"), []byte(fmt.Sprintf("receipt%d", height)))
+		assert.NoError(t, err)
+		rss := rt.GetSnapshot()
+		assert.NoError(t, rss.Flush())
+
+		bm.blocks = append(bm.blocks, &testBlock{
+			height: height,
+			result: codec.MustMarshalToBytes(&testResult{
+				State:    ss.Hash(),
+				Receipts: rss.Hash(),
+			}),
+		})
+	}
+	bm.last = 19
+
+	p, err := NewPruner(dbase, bm, sm, 0, MinWindow, 5, "", "mapdb", log.New())
+	assert.NoError(t, err)
+
+	st, err := p.loadState()
+	assert.NoError(t, err)
+	assert.Equal(t, &pruningState{Height: 0}, st)
+	assert.NoError(t, p.prune(st, 8, make(chan struct{})))
+
+	st, err = p.loadState()
+	assert.NoError(t, err)
+	assert.Equal(t, &pruningState{Height: 8, Checkpoints: []int64{0, 5}}, st)
+	assert.Equal(t, int64(20), bm.last)
+
+	bk, err := dbase.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	for _, blk := range bm.blocks {
+		var r testResult
+		codec.MustUnmarshalFromBytes(blk.result, &r)
+		has, err := bk.Has(r.State)
+		assert.NoError(t, err)
+
+		// receipts are kept for all heights
+		v, err := trie_manager.NewImmutable(dbase, r.Receipts).Get([]byte("receipt"))
+		assert.NoError(t, err, "height=%d", blk.height)
+		assert.Equal(t, []byte(fmt.Sprintf("receipt%d", blk.height)), v)
+
+		if blk.height >= 8 || blk.height%5 == 0 {
+			assert.True(t, has, "height=%d", blk.height)
+			assert.NoError(t, sm.ExportResult(blk.result, nil, db.NewMapDB()),
+				"height=%d", blk.height)
+		} else {
+			assert.False(t, has, "height=%d", blk.height)
+		}
+	}
+}
+
+func TestTracker_deleteUnless(t *testing.T) {
+	dbase := AttachTracker(db.NewMapDB())
+	tr := trackerOf(dbase)
+	tmp := db.NewMapDB()
+	marks, err := newKeySet(tmp, bucketMarks, dbase)
+	assert.NoError(t, err)
+	written, err := newKeySet(tmp, bucketWritten, dbase)
+	assert.NoError(t, err)
+
+	bk, err := dbase.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	keys := [][]byte{[]byte("k1"), []byte("k2"), []byte("k3")}
+	for _, k := range keys {
+		assert.NoError(t, bk.Set(k, k))
+	}
+
+	tr.startTracking(written)
+	assert.NoError(t, marks.add(db.BytesByHash, keys[0]))
+	// written while pruning
+	assert.NoError(t, bk.Set(keys[1], keys[1]))
+
+	cnt, err := tr.deleteUnless(db.BytesByHash, keys, marks)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cnt)
+	tr.stopTracking()
+
+	for i, k := range keys {
+		has, err := bk.Has(k)
+		assert.NoError(t, err)
+		assert.Equal(t, i != 2, has)
+	}
+
+	// key set returns the value only for the key in the set
+	mbk, err := marks.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	v, err := mbk.Get(keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, keys[0], v)
+	v, err = mbk.Get(keys[1])
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestTracker_batch(t *testing.T) {
+	dbase := AttachTracker(db.NewMapDB())
+	tr := trackerOf(dbase)
+	written, err := newKeySet(db.NewMapDB(), bucketWritten, dbase)
+	assert.NoError(t, err)
+
+	batch := db.NewBatch(dbase)
+	_, ok := batch.(*trackedBatch)
+	assert.True(t, ok)
+
+	tr.startTracking(written)
+	defer tr.stopTracking()
+	assert.NoError(t, batch.Set(db.BytesByHash, []byte("k1"), []byte("v1")))
+	assert.NoError(t, batch.Set(db.ChainProperty, []byte("k2"), []byte("v2")))
+	has, err := written.has(db.BytesByHash, []byte("k1"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	assert.NoError(t, batch.Write())
+	has, err = written.has(db.BytesByHash, []byte("k1"))
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	bk, err := dbase.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	v, err := bk.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), v)
+}
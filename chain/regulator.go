@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
 )
 
 const (
@@ -13,6 +14,13 @@ const (
 	ConfigDefaultCommitTimeout    = time.Second
 	ConfigDefaultBlockInterval    = time.Second
 	ConfigDefaultMinCommitTimeout = 200 * time.Millisecond
+
+	ConfigDefaultTimeoutPropose         = time.Second
+	ConfigDefaultTimeoutPrevote         = time.Second
+	ConfigDefaultTimeoutPrecommit       = time.Second
+	ConfigDefaultTimeoutNewRound        = time.Second
+	ConfigDefaultTimeoutDelta           = 0
+	ConfigDefaultTimeoutThresholdFactor = 2
 )
 
 type txExecutionEntry struct {
@@ -48,6 +56,7 @@ type regulator struct {
 	proposeTime      time.Time
 	blockInterval    time.Duration
 	minCommitTimeout time.Duration
+	roundTimeouts    module.RoundTimeouts
 
 	history      [30]txExecutionEntry
 	sum          txExecutionSum
@@ -83,6 +92,54 @@ func (r *regulator) SetBlockInterval(blockInterval time.Duration, commitTimeout
 	r.currentTxCount = txCount
 }
 
+func timeoutOrDefault(v, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	if v > module.MaxRoundTimeout {
+		return module.MaxRoundTimeout
+	}
+	return v
+}
+
+// SetRoundTimeouts sets timeouts for consensus rounds. Zero or negative
+// values are replaced with default values, and values over the maximum
+// are limited to it.
+func (r *regulator) SetRoundTimeouts(t module.RoundTimeouts) {
+	t.Propose = timeoutOrDefault(t.Propose, ConfigDefaultTimeoutPropose)
+	t.Prevote = timeoutOrDefault(t.Prevote, ConfigDefaultTimeoutPrevote)
+	t.Precommit = timeoutOrDefault(t.Precommit, ConfigDefaultTimeoutPrecommit)
+	t.NewRound = timeoutOrDefault(t.NewRound, ConfigDefaultTimeoutNewRound)
+	if t.Delta < 0 {
+		t.Delta = ConfigDefaultTimeoutDelta
+	} else if t.Delta > module.MaxRoundTimeout {
+		t.Delta = module.MaxRoundTimeout
+	}
+	if t.ThresholdFactor <= 0 {
+		t.ThresholdFactor = ConfigDefaultTimeoutThresholdFactor
+	} else if t.ThresholdFactor > module.MaxRoundThresholdFactor {
+		t.ThresholdFactor = module.MaxRoundThresholdFactor
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.roundTimeouts == t {
+		return
+	}
+
+	r.log.Printf("Regulator.SetRoundTimeouts(propose=%s,prevote=%s,precommit=%s,newRound=%s,delta=%s,factor=%d)",
+		t.Propose, t.Prevote, t.Precommit, t.NewRound, t.Delta, t.ThresholdFactor)
+	r.roundTimeouts = t
+}
+
+func (r *regulator) RoundTimeouts() module.RoundTimeouts {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.roundTimeouts
+}
+
 func (r *regulator) OnPropose(now time.Time) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -150,7 +207,15 @@ func NewRegulator(logger log.Logger) *regulator {
 		blockInterval:    ConfigDefaultBlockInterval,
 		minCommitTimeout: ConfigDefaultMinCommitTimeout,
 		currentTxCount:   ConfigDefaultTransactions,
-		log:              logger,
+		roundTimeouts: module.RoundTimeouts{
+			Propose:         ConfigDefaultTimeoutPropose,
+			Prevote:         ConfigDefaultTimeoutPrevote,
+			Precommit:       ConfigDefaultTimeoutPrecommit,
+			NewRound:        ConfigDefaultTimeoutNewRound,
+			Delta:           ConfigDefaultTimeoutDelta,
+			ThresholdFactor: ConfigDefaultTimeoutThresholdFactor,
+		},
+		log: logger,
 	}
 	r.addEntryInLock(ConfigDefaultTransactions, time.Second, 0)
 	return r
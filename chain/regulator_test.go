@@ -0,0 +1,75 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestRegulator_SetRoundTimeouts(t *testing.T) {
+	assert := assert.New(t)
+	r := NewRegulator(log.New())
+
+	def := module.RoundTimeouts{
+		Propose:         ConfigDefaultTimeoutPropose,
+		Prevote:         ConfigDefaultTimeoutPrevote,
+		Precommit:       ConfigDefaultTimeoutPrecommit,
+		NewRound:        ConfigDefaultTimeoutNewRound,
+		Delta:           ConfigDefaultTimeoutDelta,
+		ThresholdFactor: ConfigDefaultTimeoutThresholdFactor,
+	}
+	assert.Equal(def, r.RoundTimeouts())
+
+	rt := module.RoundTimeouts{
+		Propose:         2 * time.Second,
+		Prevote:         3 * time.Second,
+		Precommit:       4 * time.Second,
+		NewRound:        5 * time.Second,
+		Delta:           100 * time.Millisecond,
+		ThresholdFactor: 3,
+	}
+	r.SetRoundTimeouts(rt)
+	assert.Equal(rt, r.RoundTimeouts())
+
+	// zero or negative values are replaced with default values
+	r.SetRoundTimeouts(module.RoundTimeouts{Prevote: -time.Second, Delta: -1, ThresholdFactor: -1})
+	assert.Equal(def, r.RoundTimeouts())
+
+	// values over the maximum are limited
+	r.SetRoundTimeouts(module.RoundTimeouts{
+		Propose:         module.MaxRoundTimeout + 1,
+		Prevote:         time.Second,
+		Precommit:       time.Second,
+		NewRound:        module.MaxRoundTimeout * 2,
+		Delta:           module.MaxRoundTimeout + 1,
+		ThresholdFactor: module.MaxRoundThresholdFactor + 1,
+	})
+	assert.Equal(module.RoundTimeouts{
+		Propose:         module.MaxRoundTimeout,
+		Prevote:         time.Second,
+		Precommit:       time.Second,
+		NewRound:        module.MaxRoundTimeout,
+		Delta:           module.MaxRoundTimeout,
+		ThresholdFactor: module.MaxRoundThresholdFactor,
+	}, r.RoundTimeouts())
+}
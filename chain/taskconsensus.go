@@ -50,6 +50,8 @@ func (t *taskConsensus) Start() error {
 		return err
 	}
 	if err := t._start(t.chain); err != nil {
+		t.chain.stopPruner()
+		t.chain.stopIndexers()
 		t.chain.releaseManagers()
 		t.result.SetValue(err)
 		return err
@@ -62,6 +64,12 @@ func (t *taskConsensus) _start(c *singleChain) error {
 	if err := c.cs.Start(); err != nil {
 		return err
 	}
+	if err := c.startIndexers(); err != nil {
+		return err
+	}
+	if err := c.startPruner(); err != nil {
+		return err
+	}
 	c.srv.SetChain(c.cfg.Channel, c)
 	if err := c.nm.Start(); err != nil {
 		return err
@@ -71,6 +79,8 @@ func (t *taskConsensus) _start(c *singleChain) error {
 
 func (t *taskConsensus) Stop() {
 	t.chain.srv.RemoveChain(t.chain.cfg.Channel)
+	t.chain.stopPruner()
+	t.chain.stopIndexers()
 	t.chain.releaseManagers()
 	t.result.SetValue(errors.ErrInterrupted)
 }
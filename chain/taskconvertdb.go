@@ -0,0 +1,207 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	ConvertDBTask = "convert_db"
+)
+
+var convertDBStates = map[State]string{
+	Starting: "convert db starting",
+	Stopping: "convert db stopping",
+	Failed:   "convert db failed",
+	Finished: "convert db done",
+}
+
+type convertDBParams struct {
+	DBType string `json:"dbType"`
+}
+
+type taskConvertDB struct {
+	chain  *singleChain
+	result resultStore
+	from   string
+	dbtype string
+
+	entries int64
+	stopped int32
+}
+
+func (t *taskConvertDB) String() string {
+	return fmt.Sprintf("ConvertDB(from=%s,to=%s)", t.from, t.dbtype)
+}
+
+func (t *taskConvertDB) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("convert db to=%s entries=%d",
+			t.dbtype, atomic.LoadInt64(&t.entries))
+	default:
+		if st, ok := convertDBStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskConvertDB) Start() error {
+	if t.dbtype == t.from {
+		return errors.IllegalArgumentError.Errorf(
+			"SameDBType(type=%s)", t.dbtype)
+	}
+	go t.doConvert()
+	return nil
+}
+
+func (t *taskConvertDB) doConvert() {
+	err := t._convert()
+	t.result.SetValue(err)
+}
+
+func (t *taskConvertDB) onProgress(count int64) error {
+	if atomic.LoadInt32(&t.stopped) != 0 {
+		return errors.ErrInterrupted
+	}
+	atomic.StoreInt64(&t.entries, count)
+	return nil
+}
+
+func (t *taskConvertDB) _copyDatabase(dbpath, srcpath string) (rerr error) {
+	c := t.chain
+	os.RemoveAll(dbpath)
+	src, err := c.openDatabase(srcpath, t.from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	defer func() {
+		if rerr != nil {
+			os.RemoveAll(dbpath)
+		}
+	}()
+	dst, err := c.openDatabase(dbpath, t.dbtype)
+	if err != nil {
+		return err
+	}
+	err = db.Convert(dst, src, t.onProgress)
+	if cerr := dst.Close(); err == nil && cerr != nil {
+		err = errors.Wrap(cerr, "fail to close database")
+	}
+	if err != nil {
+		return err
+	}
+
+	// verify with the database opened again to be sure that all the entries
+	// are stored.
+	c.logger.Infof("Verify Database path=%s type=%s", dbpath, t.dbtype)
+	dst, err = c.openDatabase(dbpath, t.dbtype)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	return db.Verify(dst, src)
+}
+
+func (t *taskConvertDB) _convert() (rerr error) {
+	c := t.chain
+
+	c.releaseDatabase()
+	defer c.ensureDatabase()
+
+	chainDir := c.cfg.ResolveAbsolute(c.cfg.BaseDir)
+	dbpath := path.Join(chainDir, DefaultTmpDBDir)
+	target := path.Join(chainDir, DefaultDBDir)
+
+	c.logger.Infof("Convert Database path=%s type=%s -> path=%s type=%s",
+		target, t.from, dbpath, t.dbtype)
+	if err := t._copyDatabase(dbpath, target); err != nil {
+		return err
+	}
+	defer func() {
+		if rerr != nil {
+			os.RemoveAll(dbpath)
+		}
+	}()
+
+	dbbk := target + ".bk"
+	c.logger.Infof("Replace DB %s -> %s", dbpath, target)
+	os.RemoveAll(dbbk)
+	if err := os.Rename(target, dbbk); err != nil {
+		return errors.UnknownError.Errorf("fail on backup %s to %s",
+			target, dbbk)
+	}
+	defer func() {
+		if rerr != nil {
+			os.RemoveAll(target)
+			os.Rename(dbbk, target)
+		} else {
+			os.RemoveAll(dbbk)
+		}
+	}()
+	if err := os.Rename(dbpath, target); err != nil {
+		return errors.UnknownError.Errorf("fail on rename %s to %s",
+			dbpath, target)
+	}
+
+	c.cfg.DBType = t.dbtype
+	if err := c.cfg.Save(); err != nil {
+		c.cfg.DBType = t.from
+		return errors.UnknownError.Wrap(err, "fail to store configuration")
+	}
+	return nil
+}
+
+func (t *taskConvertDB) Stop() {
+	atomic.StoreInt32(&t.stopped, 1)
+}
+
+func (t *taskConvertDB) Wait() error {
+	return t.result.Wait()
+}
+
+func taskConvertDBFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	p := new(convertDBParams)
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, p); err != nil {
+			return nil, err
+		}
+	}
+	if p.DBType == "" {
+		p.DBType = string(db.PebbleDBBackend)
+	}
+	return &taskConvertDB{
+		chain:  c,
+		from:   c.cfg.DBType,
+		dbtype: p.DBType,
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(ConvertDBTask, taskConvertDBFactory)
+}
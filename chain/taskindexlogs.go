@@ -0,0 +1,125 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/chain/index"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	IndexLogsTask = "index_logs"
+)
+
+var indexLogsStates = map[State]string{
+	Starting: "index logs starting",
+	Stopping: "index logs stopping",
+	Failed:   "index logs failed",
+	Finished: "index logs done",
+}
+
+type indexLogsParams struct {
+	From *int64 `json:"from,omitempty"`
+}
+
+type taskIndexLogs struct {
+	chain  *singleChain
+	result resultStore
+	from   int64
+
+	current int64
+	stopped int32
+}
+
+func (t *taskIndexLogs) String() string {
+	return fmt.Sprintf("IndexLogs(from=%d)", t.from)
+}
+
+func (t *taskIndexLogs) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("index logs height=%d from=%d",
+			atomic.LoadInt64(&t.current), t.from)
+	default:
+		if st, ok := indexLogsStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskIndexLogs) Start() error {
+	if base := t.chain.GenesisStorage().Height(); t.from < base {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(from=%d,base=%d)", t.from, base)
+	}
+	if err := t.chain.prepareManagers(); err != nil {
+		return err
+	}
+	go t.doIndexLogs()
+	return nil
+}
+
+func (t *taskIndexLogs) doIndexLogs() {
+	c := t.chain
+	defer c.releaseManagers()
+
+	err := index.BackfillLogIndex(c.Database(), c.bm, c.sm, t.from, t.onProgress)
+	t.result.SetValue(err)
+}
+
+func (t *taskIndexLogs) onProgress(height int64) error {
+	if atomic.LoadInt32(&t.stopped) != 0 {
+		return errors.ErrInterrupted
+	}
+	atomic.StoreInt64(&t.current, height)
+	return nil
+}
+
+func (t *taskIndexLogs) Stop() {
+	atomic.StoreInt32(&t.stopped, 1)
+}
+
+func (t *taskIndexLogs) Wait() error {
+	return t.result.Wait()
+}
+
+func taskIndexLogsFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	p := new(indexLogsParams)
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, p); err != nil {
+			return nil, err
+		}
+	}
+	from := c.GenesisStorage().Height()
+	if p.From != nil {
+		from = *p.From
+	}
+	return &taskIndexLogs{
+		chain: c,
+		from:  from,
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(IndexLogsTask, taskIndexLogsFactory)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/network"
+)
+
+const (
+	ReplayPacketsTask = "replay_packets"
+)
+
+var replayPacketsStates = map[State]string{
+	Starting: "replay packets starting",
+	Stopping: "replay packets stopping",
+	Failed:   "replay packets failed",
+	Finished: "replay packets done",
+}
+
+type replayPacketsParams struct {
+	File  string  `json:"file"`
+	Speed float64 `json:"speed,omitempty"`
+}
+
+// taskReplayPackets delivers captured inbound packets to the reactors of
+// the chain without network connections, to reproduce a problem offline.
+type taskReplayPackets struct {
+	chain  *singleChain
+	result resultStore
+	file   string
+	speed  float64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+func (t *taskReplayPackets) String() string {
+	return fmt.Sprintf("ReplayPackets(file=%s,speed=%v)", t.file, t.speed)
+}
+
+func (t *taskReplayPackets) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("replay packets file=%s", t.file)
+	default:
+		if st, ok := replayPacketsStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskReplayPackets) Start() error {
+	f, err := os.Open(t.file)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrapf(err, "FailToOpen(file=%s)", t.file)
+	}
+	c := t.chain
+	if err := c.prepareManagers(); err != nil {
+		f.Close()
+		return err
+	}
+	c.sm.Start()
+	if err := c.cs.Start(); err != nil {
+		f.Close()
+		c.releaseManagers()
+		return err
+	}
+	go t.doReplay(f)
+	return nil
+}
+
+func (t *taskReplayPackets) doReplay(f *os.File) {
+	c := t.chain
+	defer c.releaseManagers()
+	defer f.Close()
+
+	cnt, err := network.ReplayCapture(c.nm, network.NewCaptureReader(f), t.speed, t.stop)
+	c.logger.Infof("Replay packets file=%s count=%d", t.file, cnt)
+	t.result.SetValue(err)
+}
+
+func (t *taskReplayPackets) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+func (t *taskReplayPackets) Wait() error {
+	return t.result.Wait()
+}
+
+func taskReplayPacketsFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	p := new(replayPacketsParams)
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, p); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.File) == 0 {
+		return nil, errors.IllegalArgumentError.New("NoCaptureFile")
+	}
+	if p.Speed < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidSpeed(speed=%v)", p.Speed)
+	}
+	return &taskReplayPackets{
+		chain: c,
+		file:  c.cfg.ResolveAbsolute(p.File),
+		speed: p.Speed,
+		stop:  make(chan struct{}),
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(ReplayPacketsTask, taskReplayPacketsFactory)
+}
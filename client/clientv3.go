@@ -413,6 +413,15 @@ func (c *ClientV3) GetScoreStatus(param *v3.ScoreAddressParam) (interface{}, err
 	return result, nil
 }
 
+func (c *ClientV3) GetTransactionsByAddress(param *v3.TransactionsByAddressParam) (interface{}, error) {
+	var result interface{}
+	_, err := c.Do("icx_getTransactionsByAddress", param, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *ClientV3) MonitorBlock(param *server.BlockRequest, cb func(v *server.BlockNotification), cancelCh <-chan bool) error {
 	resp := &server.BlockNotification{}
 	return c.Monitor("/block", param, resp, func(v interface{}) {
@@ -146,6 +146,10 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				param.NephewsLimit = &nephewsLimit
 			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.LogIndex, _ = fs.GetBool("log_index")
+			param.TxIndex, _ = fs.GetBool("tx_index")
+			param.PruningWindow, _ = fs.GetInt64("pruning_window")
+			param.PruningCheckpoint, _ = fs.GetInt64("pruning_checkpoint")
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -193,7 +197,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.String("node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	joinFlags.String("channel", "", "Channel")
 	joinFlags.String("secure_suites", "none,tls,ecdhe",
-		"Supported Secure suites with order (none,tls,ecdhe) - Comma separated string")
+		"Supported Secure suites with order (none,tls,ecdhe,noise) - Comma separated string")
 	joinFlags.String("secure_aeads", "chacha,aes128,aes256",
 		"Supported Secure AEAD with order (chacha,aes128,aes256) - Comma separated string")
 	joinFlags.Int64("default_wait_timeout", 0, "Default wait timeout in milli-second (0: disable)")
@@ -203,6 +207,10 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.Bool("log_index", false, "Index event logs for icx_getLogs")
+	joinFlags.Bool("tx_index", false, "Index transactions by address for icx_getTransactionsByAddress")
+	joinFlags.Int64("pruning_window", 0, "Number of recent blocks keeping their state, older state is pruned online (0: no pruning)")
+	joinFlags.Int64("pruning_checkpoint", 0, "Interval of heights keeping their state on pruning (0: no checkpoint)")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -480,6 +488,9 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	configFlags.String("value", "", "use if value starts with '-'.\n"+
 		"(if the third arg is used, this flag will be ignored)")
 
+	NewAddressBookCmd(rootCmd, &adminClient)
+	NewPeersCmd(rootCmd, &adminClient)
+
 	rootCmd.Use = "chain TASK CID PARAM"
 	rootCmd.Args = ArgsWithDefaultErrorFunc(cobra.ExactArgs(3))
 	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -499,6 +510,175 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	return rootCmd, vc
 }
 
+func NewAddressBookCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "addressbook",
+		Short: "Manage known peers of the chain",
+	}
+	parent.AddCommand(rootCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "ls CID",
+		Short: "List known peers in order of preference",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.Get(node.UrlChain+"/"+args[0]+"/addressbook", nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(listCmd)
+
+	clearCmd := &cobra.Command{
+		Use:   "clear CID",
+		Short: "Remove all known peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			_, err := client.Delete(node.UrlChain+"/"+args[0]+"/addressbook", &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(clearCmd)
+}
+
+func NewPeersCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "peers",
+		Short: "Manage peers of the chain",
+	}
+	parent.AddCommand(rootCmd)
+
+	peersUrl := func(cid string, res ...string) string {
+		return node.UrlChain + "/" + cid + "/peers" + strings.Join(res, "")
+	}
+	printGet := func(reqUrl string) error {
+		resp, err := client.Get(reqUrl, nil)
+		if err != nil {
+			return err
+		}
+		return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+	}
+	printDelete := func(reqUrl string) error {
+		var v string
+		if _, err := client.Delete(reqUrl, &v); err != nil {
+			return err
+		}
+		fmt.Println(v)
+		return nil
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "ls CID",
+		Short: "List connected peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printGet(peersUrl(args[0]))
+		},
+	}
+	rootCmd.AddCommand(listCmd)
+
+	disconnectCmd := &cobra.Command{
+		Use:   "disconnect CID ID",
+		Short: "Disconnect the peer",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printDelete(peersUrl(args[0], "/", url.PathEscape(args[1])))
+		},
+	}
+	rootCmd.AddCommand(disconnectCmd)
+
+	bansCmd := &cobra.Command{
+		Use:   "bans CID",
+		Short: "List banned peers and IP ranges",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printGet(peersUrl(args[0], "/bans"))
+		},
+	}
+	rootCmd.AddCommand(bansCmd)
+
+	banCmd := &cobra.Command{
+		Use:   "ban CID TARGET",
+		Short: "Ban the peer ID, IP address or IP range in CIDR notation",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &node.PeerBanParam{
+				Target:   args[1],
+				Duration: cmd.Flag("duration").Value.String(),
+			}
+			var v string
+			if _, err := client.PostWithJson(peersUrl(args[0], "/bans"), param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	banCmd.Flags().String("duration", "", "Duration of the ban (ex: 30m, 24h), default: peer_ban_duration of the chain")
+	rootCmd.AddCommand(banCmd)
+
+	unbanCmd := &cobra.Command{
+		Use:   "unban CID TARGET",
+		Short: "Remove the ban of the peer ID, IP address or IP range",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q := url.Values{"target": []string{args[1]}}
+			return printDelete(peersUrl(args[0], "/bans?", q.Encode()))
+		},
+	}
+	rootCmd.AddCommand(unbanCmd)
+
+	staticCmd := &cobra.Command{
+		Use:   "static",
+		Short: "Manage static peers which are always connected",
+	}
+	rootCmd.AddCommand(staticCmd)
+
+	staticListCmd := &cobra.Command{
+		Use:   "ls CID",
+		Short: "List static peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printGet(peersUrl(args[0], "/statics"))
+		},
+	}
+	staticCmd.AddCommand(staticListCmd)
+
+	staticAddCmd := &cobra.Command{
+		Use:   "add CID ADDRESS",
+		Short: "Add the address (host:port) to static peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &node.StaticPeerParam{Address: args[1]}
+			var v string
+			if _, err := client.PostWithJson(peersUrl(args[0], "/statics"), param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	staticCmd.AddCommand(staticAddCmd)
+
+	staticRemoveCmd := &cobra.Command{
+		Use:   "rm CID ADDRESS",
+		Short: "Remove the address from static peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q := url.Values{"address": []string{args[1]}}
+			return printDelete(peersUrl(args[0], "/statics?", q.Encode()))
+		},
+	}
+	staticCmd.AddCommand(staticRemoveCmd)
+}
+
 func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
 	var adminClient node.UnixDomainSockHttpClient
 	rootCmd, vc := NewCommand(parentCmd, parentVc, "system", "System info")
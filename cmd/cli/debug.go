@@ -17,6 +17,7 @@
 package cli
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	v3 "github.com/icon-project/goloop/server/v3"
 )
@@ -56,8 +58,9 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		Short: "Get trace of the transaction",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			param := &v3.TransactionHashParam{
+			param := &v3.TraceParam{
 				Hash: jsonrpc.HexBytes(args[0]),
+				Mode: cmd.Flag("mode").Value.String(),
 			}
 			trace, err := debugClient.Do("debug_getTrace", param, nil)
 			if err != nil {
@@ -67,6 +70,36 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		},
 	}
 	rootCmd.AddCommand(traceCmd)
+	traceCmd.Flags().String("mode", "", "Trace mode (invoke or call)")
+
+	stateDiffCmd := &cobra.Command{
+		Use:   "statediff",
+		Short: "Get state changes of the block",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &v3.StateDiffParam{}
+			if hash, _ := fs.GetString("hash"); hash != "" {
+				param.Hash = jsonrpc.HexBytes(hash)
+			} else if height, _ := fs.GetInt64("height"); height >= 0 {
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+			} else {
+				return fmt.Errorf("hash or height is required")
+			}
+			if txIndex, _ := fs.GetInt("tx_index"); txIndex >= 0 {
+				param.TxIndex = jsonrpc.HexInt(intconv.FormatInt(int64(txIndex)))
+			}
+			diff, err := debugClient.Do("debug_getStateDiff", param, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, diff.Result)
+		},
+	}
+	rootCmd.AddCommand(stateDiffCmd)
+	stateDiffCmd.Flags().String("hash", "", "Hash of the block")
+	stateDiffCmd.Flags().Int64("height", -1, "Height of the block")
+	stateDiffCmd.Flags().Int("tx_index", -1, "Index of the transaction in the block")
 
 	return rootCmd, vc
 }
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/network"
+	"github.com/icon-project/goloop/node"
+)
+
+func parseProtocolFilter(s string) (map[uint16]bool, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	m := make(map[uint16]bool)
+	for _, token := range strings.Split(s, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(token), 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid protocol %s", token)
+		}
+		m[uint16(v)] = true
+	}
+	return m, nil
+}
+
+func newPacketCaptureDumpCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s capture_file...", c),
+		Short: "Show packets in capture files",
+		Args:  cobra.MinimumNArgs(1),
+	}
+	flags := cmd.Flags()
+	protocols := flags.String("protocol", "", "Protocols to show(ex: 0x0100,0x0200), default: all")
+	direction := flags.String("direction", "", "Direction of packets to show(in or out), default: all")
+	payload := flags.Bool("payload", false, "Showing payload in hex")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		pf, err := parseProtocolFilter(*protocols)
+		if err != nil {
+			return err
+		}
+		if *direction != "" &&
+			*direction != network.CaptureIn.String() &&
+			*direction != network.CaptureOut.String() {
+			return fmt.Errorf("invalid direction %s", *direction)
+		}
+		for _, arg := range args {
+			f, err := os.Open(arg)
+			if err != nil {
+				return err
+			}
+			cr := network.NewCaptureReader(f)
+			for {
+				r, err := cr.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					f.Close()
+					return fmt.Errorf("fail to read file=%s err=%+v", arg, err)
+				}
+				if pf != nil && !pf[r.Protocol.Uint16()] {
+					continue
+				}
+				if *direction != "" && *direction != r.Direction.String() {
+					continue
+				}
+				fmt.Println(r.String())
+				if *payload {
+					fmt.Println(hex.EncodeToString(r.Payload))
+				}
+			}
+			f.Close()
+		}
+		return nil
+	}
+	return cmd
+}
+
+func newPacketCaptureReplayCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s CID capture_file", c),
+		Short: "Replay inbound packets in the capture file to reactors of the stopped chain",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+	}
+	flags := cmd.Flags()
+	nodeSock := flags.StringP("node_sock", "s", "", "Node Command Line Interface socket path")
+	speed := flags.Float64("speed", 0, "Speed of replay keeping intervals of packets(ex: 1 for real time), default: no delay")
+	MarkAnnotationRequired(flags, "node_sock")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := ValidateFlags(cmd.Flags()); err != nil {
+			return err
+		}
+		file, err := filepath.Abs(args[1])
+		if err != nil {
+			return err
+		}
+		client := node.NewUnixDomainSockHttpClient(*nodeSock)
+		param := map[string]interface{}{
+			"file":  file,
+			"speed": *speed,
+		}
+		reqUrl := node.UrlChain + "/" + args[0] + "/" + chain.ReplayPacketsTask
+		var v string
+		if _, err = client.PostWithJson(reqUrl, param, &v); err != nil {
+			return err
+		}
+		fmt.Println(v)
+		return nil
+	}
+	return cmd
+}
+
+func NewPacketCaptureCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{Use: c, Short: "Packet capture manipulation"}
+	cmd.AddCommand(newPacketCaptureDumpCmd("dump"))
+	cmd.AddCommand(newPacketCaptureReplayCmd("replay"))
+	return cmd
+}
@@ -196,6 +196,38 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 			},
 		})
 
+	txByAddressCmd := &cobra.Command{
+		Use:   "txbyaddress ADDRESS",
+		Short: "GetTransactionsByAddress",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.TransactionsByAddressParam{Address: jsonrpc.Address(args[0])}
+			limit, err := intconv.ParseInt(cmd.Flag("limit").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if limit != 0 {
+				param.Limit = jsonrpc.HexInt(intconv.FormatInt(limit))
+			}
+			cursor, err := intconv.ParseInt(cmd.Flag("cursor").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if cursor != -1 {
+				param.Cursor = jsonrpc.HexInt(intconv.FormatInt(cursor))
+			}
+			txs, err := rpcClient.GetTransactionsByAddress(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, txs)
+		},
+	}
+	rootCmd.AddCommand(txByAddressCmd)
+	txByAddressFlags := txByAddressCmd.Flags()
+	txByAddressFlags.Int("limit", 0, "Maximum number of transactions (0: uses server default value)")
+	txByAddressFlags.Int64("cursor", -1, "Cursor returned by the previous query (-1: from the latest)")
+
 	balanceCmd := &cobra.Command{
 		Use:   "balance ADDRESS",
 		Short: "GetBalance",
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
 	"io/ioutil"
@@ -28,6 +29,11 @@ type ServerConfig struct {
 	KeyPlugin     string            `json:"key_plugin,omitempty"`
 	KeyPlgOptions map[string]string `json:"key_plugin_options,omitempty"`
 
+	KeySigner     string `json:"key_signer,omitempty"`
+	KeySignerCert string `json:"key_signer_cert,omitempty"`
+	KeySignerKey  string `json:"key_signer_key,omitempty"`
+	KeySignerCA   string `json:"key_signer_ca,omitempty"`
+
 	Wallet module.Wallet `json:"-"`
 
 	LogLevel     string               `json:"log_level"`
@@ -52,6 +58,23 @@ func (cfg *ServerConfig) MakesureWallet(gen bool) error {
 	if cfg.Wallet != nil {
 		return nil
 	}
+	if cfg.KeySigner != "" {
+		var tlsConfig *tls.Config
+		if cfg.KeySignerCert != "" || cfg.KeySignerKey != "" || cfg.KeySignerCA != "" {
+			if c, err := wallet.NewSignerTLSConfig(
+				cfg.KeySignerCert, cfg.KeySignerKey, cfg.KeySignerCA, false); err != nil {
+				return err
+			} else {
+				tlsConfig = c
+			}
+		}
+		if w, err := wallet.OpenRemote(cfg.KeySigner, tlsConfig); err != nil {
+			return err
+		} else {
+			cfg.Wallet = w
+			return nil
+		}
+	}
 	if cfg.KeyPlugin != "" {
 		options := make(map[string]string)
 		for k, v := range cfg.KeyPlgOptions {
@@ -137,9 +160,11 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	}
 	rootPFlags := rootCmd.PersistentFlags()
 	rootPFlags.String("p2p", "127.0.0.1:8080", "Advertise ip-port of P2P")
-	rootPFlags.String("p2p_listen", "", "Listen ip-port of P2P")
+	rootPFlags.String("p2p_listen", "", "Listen ip-port of P2P, quic://ip-port to accept QUIC as well")
 	rootPFlags.String("rpc_addr", ":9080", "Listen ip-port of JSON-RPC")
 	rootPFlags.Bool("rpc_dump", false, "JSON-RPC Request, Response Dump flag")
+	rootPFlags.String("rpc_trusted_proxies", "",
+		"Trusted proxies to take client IP from X-Forwarded-For, comma-separated CIDR or IP")
 	rootPFlags.String("ee_socket", "", "Execution engine socket path")
 	rootPFlags.String("key_password", "", "Password for the KeyStore file")
 	rootPFlags.String("log_level", "debug", "Global log level (trace,debug,info,warn,error,fatal,panic)")
@@ -156,6 +181,10 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	rootPFlags.String("key_secret", "", "Secret (password) file for KeyStore")
 	rootPFlags.String("key_plugin", "", "KeyPlugin file for wallet")
 	rootPFlags.StringToString("key_plugin_options", nil, "KeyPlugin options")
+	rootPFlags.String("key_signer", "", "Remote signer address for wallet (unix:///path or tcp://host:port)")
+	rootPFlags.String("key_signer_cert", "", "TLS certificate file for remote signer")
+	rootPFlags.String("key_signer_key", "", "TLS private key file for remote signer")
+	rootPFlags.String("key_signer_ca", "", "TLS CA certificate file for remote signer")
 	//
 	rootPFlags.String("log_forwarder_vendor", "", "LogForwarder vendor (fluentd,logstash)")
 	rootPFlags.String("log_forwarder_address", "", "LogForwarder address")
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+func NewSignerCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c,
+		Short: "Run remote signer with keystore",
+		Args:  ArgsWithDefaultErrorFunc(cobra.NoArgs),
+	}
+	flags := cmd.Flags()
+	keystorePath := flags.StringP("key_store", "k", "keystore.json", "KeyStore file for wallet")
+	secret := flags.StringP("key_secret", "s", "", "Secret (password) file for KeyStore")
+	pass := flags.StringP("key_password", "p", "gochain", "Password for the KeyStore file")
+	listen := flags.StringP("listen", "l", "unix://signer.sock",
+		"Listen address (unix:///path or tcp://host:port)")
+	state := flags.String("state", "signer_state.json", "State file storing the last signed vote")
+	tlsCert := flags.String("tls_cert", "", "TLS certificate file (required for tcp)")
+	tlsKey := flags.String("tls_key", "", "TLS private key file (required for tcp)")
+	tlsCA := flags.String("tls_ca", "", "TLS CA certificate file verifying nodes (required for tcp)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ks, err := ioutil.ReadFile(*keystorePath)
+		if err != nil {
+			return errors.Errorf("fail to open KeyStore file=%s err=%+v", *keystorePath, err)
+		}
+		pb := []byte(*pass)
+		if *secret != "" {
+			if pb, err = ioutil.ReadFile(*secret); err != nil {
+				return errors.Errorf("fail to open KeySecret file=%s err=%+v", *secret, err)
+			}
+		}
+		w, err := wallet.NewFromKeyStore(ks, pb)
+		if err != nil {
+			return errors.Errorf("fail to decrypt KeyStore err=%+v", err)
+		}
+
+		var tlsConfig *tls.Config
+		if *tlsCert != "" || *tlsKey != "" || *tlsCA != "" {
+			if tlsConfig, err = wallet.NewSignerTLSConfig(*tlsCert, *tlsKey, *tlsCA, true); err != nil {
+				return err
+			}
+		}
+		s, err := wallet.NewSigner(w, *state)
+		if err != nil {
+			return err
+		}
+		if err := s.Listen(*listen, tlsConfig); err != nil {
+			return err
+		}
+
+		sc := make(chan os.Signal, 1)
+		signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sc
+			_ = s.Close()
+		}()
+
+		log.Printf("Signer %s listens on %s", w.Address(), *listen)
+		if err := s.Loop(); err != nil {
+			log.Printf("Signer stopped err=%+v", err)
+		}
+		return nil
+	}
+	return cmd
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+)
+
+func walIDsFromArgs(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return consensus.WALIDs(), nil
+	}
+	for _, arg := range args {
+		found := false
+		for _, id := range consensus.WALIDs() {
+			if arg == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"UnknownWAL(name=%s,known=%v)", arg, consensus.WALIDs())
+		}
+	}
+	return args, nil
+}
+
+type walDump struct {
+	Records []*consensus.WALRecord `json:"records"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+func newWALDumpCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s WAL_DIR [WAL_NAME...]", c),
+		Short: "Dump decoded messages of WALs (round, lock, commit, evidence)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(1)),
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ids, err := walIDsFromArgs(args[1:])
+		if err != nil {
+			return err
+		}
+		result := make(map[string]*walDump)
+		for _, id := range ids {
+			recs, err := consensus.ReadWALRecords(filepath.Join(args[0], id))
+			if consensus.IsNotExist(err) {
+				continue
+			}
+			dump := &walDump{Records: recs}
+			if err != nil {
+				dump.Error = err.Error()
+			}
+			result[id] = dump
+		}
+		return JsonPrettyPrintln(os.Stdout, result)
+	}
+	return cmd
+}
+
+func newWALLockCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s WAL_DIR", c),
+		Short: "Show the last locked height and round",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		lock, err := consensus.ReadWALLock(args[0])
+		if err != nil {
+			return err
+		}
+		return JsonPrettyPrintln(os.Stdout, lock)
+	}
+	return cmd
+}
+
+func copyWALFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func backupWAL(dir, id, backupDir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, id+"_*"))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := copyWALFile(f, filepath.Join(backupDir, filepath.Base(f))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newWALTruncateCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s WAL_DIR WAL_NAME RECORDS", c),
+		Short: "Truncate the WAL keeping the first RECORDS records",
+		Long: "Truncate the WAL keeping the first RECORDS records.\n" +
+			"Records are indexed from zero as shown by dump, so the record at RECORDS\n" +
+			"and the following are removed. The node shall be stopped.",
+		Args: ArgsWithDefaultErrorFunc(cobra.ExactArgs(3)),
+	}
+	flags := cmd.Flags()
+	backupDir := flags.String("backup_dir", "",
+		"Directory to copy WAL files before truncation (default: [WAL_DIR].[timestamp].bak)")
+	noBackup := flags.Bool("no_backup", false, "Truncate without backup")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		ids, err := walIDsFromArgs(args[1:2])
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n < 0 {
+			return errors.IllegalArgumentError.Errorf("InvalidRecords(records=%s)", args[2])
+		}
+		id := ids[0]
+		recs, err := consensus.ReadWALRecords(filepath.Join(dir, id))
+		if n > len(recs) {
+			return errors.IllegalArgumentError.Errorf(
+				"NotEnoughRecords(records=%d,valid=%d,err=%v)", n, len(recs), err)
+		}
+		if !*noBackup {
+			bd := *backupDir
+			if bd == "" {
+				bd = fmt.Sprintf("%s.%d.bak", filepath.Clean(dir), time.Now().Unix())
+			}
+			if err := backupWAL(dir, id, bd); err != nil {
+				return errors.Wrapf(err, "fail to backup WAL dir=%s", bd)
+			}
+			fmt.Fprintf(os.Stderr, "Backup %s WAL to %s\n", id, bd)
+		}
+		if err := consensus.TruncateWAL(filepath.Join(dir, id), n); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Truncate %s WAL records=%d removed=%d\n", id, n, len(recs)-n)
+		return nil
+	}
+	return cmd
+}
+
+func NewWALCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{Use: c, Short: "Consensus WAL inspection and repair"}
+	cmd.AddCommand(newWALDumpCmd("dump"))
+	cmd.AddCommand(newWALLockCmd("lock"))
+	cmd.AddCommand(newWALTruncateCmd("truncate"))
+	return cmd
+}
@@ -41,6 +41,7 @@ type GoChainConfig struct {
 	RPCDump       bool   `json:"rpc_dump"`
 	RPCDebug      bool   `json:"rpc_debug"`
 	RPCRosetta    bool   `json:"rpc_rosetta"`
+	RPCGraphQL    bool   `json:"rpc_graphql"`
 	RPCBatchLimit int    `json:"rpc_batch_limit,omitempty"`
 	EEInstances   int    `json:"ee_instances"`
 	Engines       string `json:"engines"`
@@ -111,12 +112,13 @@ func main() {
 	flag.StringVar(&saveKeyStore, "save_key_store", "", "File path for storing current KeyStore")
 	flag.StringVar(&cfg.Channel, "channel", "default", "Channel name for the chain")
 	flag.StringVar(&cfg.P2PAddr, "p2p", "127.0.0.1:8080", "Advertise ip-port of P2P")
-	flag.StringVar(&cfg.P2PListenAddr, "p2p_listen", "", "Listen ip-port of P2P")
+	flag.StringVar(&cfg.P2PListenAddr, "p2p_listen", "", "Listen ip-port of P2P, quic://ip-port to accept QUIC as well")
 	flag.IntVar(&cfg.NID, "nid", 0, "Chain Network ID")
 	flag.StringVar(&cfg.RPCAddr, "rpc", ":9080", "Listen ip-port of JSON-RPC")
 	flag.BoolVar(&cfg.RPCDump, "rpc_dump", false, "JSON-RPC Request, Response Dump flag")
 	flag.BoolVar(&cfg.RPCDebug, "rpc_debug", false, "JSON-RPC Debug enable")
 	flag.BoolVar(&cfg.RPCRosetta, "rpc_rosetta", false, "JSON-RPC Rosetta enable")
+	flag.BoolVar(&cfg.RPCGraphQL, "rpc_graphql", false, "GraphQL enable")
 	flag.IntVar(&cfg.RPCBatchLimit, "rpc_batch_limit", 10, "JSON-RPC batch limit")
 	flag.StringVar(&cfg.SeedAddr, "seed", "", "Ip-port of Seed")
 	flag.StringVar(&genesisStorage, "genesis_storage", "", "Genesis storage path")
@@ -490,6 +492,7 @@ func Execute(cmd *cobra.Command, args []string) {
 		JSONRPCDump:         cfg.RPCDump,
 		JSONRPCIncludeDebug: cfg.RPCDebug,
 		JSONRPCRosetta:      cfg.RPCRosetta,
+		GraphQL:             cfg.RPCGraphQL,
 		JSONRPCBatchLimit:   cfg.RPCBatchLimit,
 		WSMaxSession:        cfg.WSMaxSession,
 	}
@@ -48,7 +48,9 @@ func main() {
 	rootCmd.AddCommand(
 		cli.NewGStorageCmd("gs"),
 		cli.NewGenesisCmd("gn"),
-		cli.NewKeystoreCmd("ks"))
+		cli.NewKeystoreCmd("ks"),
+		cli.NewSignerCmd("signer"),
+		cli.NewWALCmd("wal"))
 
 	genMdCmd := cli.NewGenerateMarkdownCommand(rootCmd, nil)
 	genMdCmd.Hidden = true
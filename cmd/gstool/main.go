@@ -12,5 +12,7 @@ func main() {
 	cmd.AddCommand(cli.NewGStorageCmd("gs"))
 	cmd.AddCommand(cli.NewGenesisCmd("gn"))
 	cmd.AddCommand(cli.NewKeystoreCmd("ks"))
+	cmd.AddCommand(cli.NewPacketCaptureCmd("pc"))
+	cmd.AddCommand(cli.NewWALCmd("wal"))
 	cmd.Execute()
 }
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+// Batch collects changes on the buckets of a database, then writes them
+// at once. Changes are applied in the order they're added.
+type Batch interface {
+	Set(id BucketID, key, value []byte) error
+	Delete(id BucketID, key []byte) error
+
+	// Len returns number of changes in the batch.
+	Len() int
+
+	// Write writes all the changes. Either all or none of them are written
+	// if the database supports it. The batch is emptied on success.
+	Write() error
+}
+
+// Batcher is implemented by the databases supporting atomic Batch.
+type Batcher interface {
+	NewBatch() Batch
+}
+
+// NewBatch returns a batch for the database. If the database doesn't
+// support atomic batch, then the returned batch writes changes one by one.
+func NewBatch(database Database) Batch {
+	switch d := database.(type) {
+	case Batcher:
+		return d.NewBatch()
+	case *databaseContext:
+		return NewBatch(d.Database)
+	case *layerDBContext:
+		return NewBatch(d.LayerDB)
+	default:
+		return &bucketBatch{database: database}
+	}
+}
+
+type batchOp struct {
+	id    BucketID
+	key   []byte
+	value []byte
+
+	// deleted is true for Delete. value is nil for it.
+	deleted bool
+}
+
+// batchOps keeps changes to be written by the batch of each backend.
+type batchOps struct {
+	ops []batchOp
+}
+
+func (b *batchOps) Set(id BucketID, key, value []byte) error {
+	b.ops = append(b.ops, batchOp{
+		id:    id,
+		key:   append([]byte{}, key...),
+		value: append([]byte{}, value...),
+	})
+	return nil
+}
+
+func (b *batchOps) Delete(id BucketID, key []byte) error {
+	b.ops = append(b.ops, batchOp{
+		id:      id,
+		key:     append([]byte{}, key...),
+		deleted: true,
+	})
+	return nil
+}
+
+func (b *batchOps) Len() int {
+	return len(b.ops)
+}
+
+// applyTo adds the changes to the batch.
+func (b *batchOps) applyTo(batch Batch) error {
+	for _, op := range b.ops {
+		var err error
+		if op.deleted {
+			err = batch.Delete(op.id, op.key)
+		} else {
+			err = batch.Set(op.id, op.key, op.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bucketBatch writes changes through the buckets of the database.
+type bucketBatch struct {
+	batchOps
+	database Database
+}
+
+func (b *bucketBatch) Write() error {
+	for _, op := range b.ops {
+		bk, err := b.database.GetBucket(op.id)
+		if err != nil {
+			return err
+		}
+		if op.deleted {
+			err = bk.Delete(op.key)
+		} else {
+			err = bk.Set(op.key, op.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	b.ops = nil
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDatabase_Batch(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bk1, _ := testDB.GetBucket("hello")
+	bk2, _ := testDB.GetBucket("world")
+	assert.NoError(t, bk1.Set([]byte("k1"), []byte("old")))
+	assert.NoError(t, bk2.Set([]byte("k2"), []byte("old")))
+
+	batch := NewBatch(testDB)
+	assert.NoError(t, batch.Set("hello", []byte("k1"), []byte("v1")))
+	assert.NoError(t, batch.Set("hello", []byte("k3"), []byte("v3")))
+	assert.NoError(t, batch.Delete("world", []byte("k2")))
+	assert.NoError(t, batch.Set("world", []byte("k4"), []byte("v4")))
+	assert.Equal(t, 4, batch.Len())
+
+	// nothing is written before Write
+	v, err := bk1.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("old"), v)
+
+	assert.NoError(t, batch.Write())
+	assert.Equal(t, 0, batch.Len())
+
+	v, _ = bk1.Get([]byte("k1"))
+	assert.Equal(t, []byte("v1"), v)
+	v, _ = bk1.Get([]byte("k3"))
+	assert.Equal(t, []byte("v3"), v)
+	has, _ := bk2.Has([]byte("k2"))
+	assert.False(t, has)
+	v, _ = bk2.Get([]byte("k4"))
+	assert.Equal(t, []byte("v4"), v)
+}
+
+func TestDatabase_Batch(t *testing.T) {
+	for name, creator := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Batch(t, creator)
+		})
+	}
+	t.Run("layerdb", func(t *testing.T) {
+		testDatabase_Batch(t, func(name string, dir string) (Database, error) {
+			return NewLayerDB(NewMapDB()), nil
+		})
+	})
+	t.Run("context", func(t *testing.T) {
+		testDatabase_Batch(t, func(name string, dir string) (Database, error) {
+			return WithFlags(NewMapDB(), nil), nil
+		})
+	})
+}
+
+func TestLayerDB_FlushWithBatch(t *testing.T) {
+	real := NewMapDB()
+	rbk, _ := real.GetBucket("hello")
+	assert.NoError(t, rbk.Set([]byte("a"), []byte("ra")))
+
+	ldb := NewLayerDB(real)
+	bk1, _ := ldb.GetBucket("hello")
+	bk2, _ := ldb.GetBucket("world")
+	assert.NoError(t, bk1.Delete([]byte("a")))
+	assert.NoError(t, bk1.Set([]byte("b"), []byte("lb")))
+	assert.NoError(t, bk2.Set([]byte("c"), []byte("lc")))
+
+	assert.NoError(t, ldb.Flush(true))
+
+	has, _ := rbk.Has([]byte("a"))
+	assert.False(t, has)
+	v, _ := rbk.Get([]byte("b"))
+	assert.Equal(t, []byte("lb"), v)
+	wbk, _ := real.GetBucket("world")
+	v, _ = wbk.Get([]byte("c"))
+	assert.Equal(t, []byte("lc"), v)
+
+	// after flush, it works as the real database.
+	assert.NoError(t, bk1.Set([]byte("d"), []byte("ld")))
+	v, _ = rbk.Get([]byte("d"))
+	assert.Equal(t, []byte("ld"), v)
+}
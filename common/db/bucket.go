@@ -66,6 +66,15 @@ const (
 	// ListByMerkleRootBase is the base for the bucket that maps list
 	// from network type dependent merkle root(list)
 	ListByMerkleRootBase BucketID = "L"
+
+	// EventLogIndex maps locators of event logs from hash of the event
+	// signature (optionally with the address) and height. It's filled
+	// only if the chain is configured to index event logs.
+	EventLogIndex BucketID = "E"
+
+	// TransactionsByAddress maps locators of transactions from address.
+	// It's filled only if the chain is configured to index transactions.
+	TransactionsByAddress BucketID = "A"
 )
 
 // internalKey returns key prefixed with the bucket's id.
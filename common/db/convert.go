@@ -0,0 +1,133 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// convertBatchSize is the size of entries written at once on conversion.
+const convertBatchSize = 4 * 1024 * 1024
+
+// rawDatabase is implemented by the databases storing entries of all
+// the buckets in one key space with internalKey.
+type rawDatabase interface {
+	iterateRaw(cb func(key, value []byte) error) error
+	writeRaw(keys, values [][]byte, sync bool) error
+}
+
+func rawDatabasesOf(dst, src Database) (rawDatabase, rawDatabase, error) {
+	rsrc, ok := src.(rawDatabase)
+	if !ok {
+		return nil, nil, errors.UnsupportedError.Errorf("ConversionNotSupported(src=%T)", src)
+	}
+	rdst, ok := dst.(rawDatabase)
+	if !ok {
+		return nil, nil, errors.UnsupportedError.Errorf("ConversionNotSupported(dst=%T)", dst)
+	}
+	return rdst, rsrc, nil
+}
+
+// Convert copies all the entries in src to dst. Both of them should be
+// opened with the backends supporting conversion (goleveldb and pebble).
+// cb is called with number of copied entries after each batch is written,
+// and it stops on an error from cb. The last batch is written with sync,
+// so all the entries are durable on success.
+func Convert(dst, src Database, cb func(count int64) error) error {
+	rdst, rsrc, err := rawDatabasesOf(dst, src)
+	if err != nil {
+		return err
+	}
+	var keys, values [][]byte
+	var size int
+	var count int64
+	flush := func(sync bool) error {
+		if len(keys) == 0 {
+			return nil
+		}
+		if err := rdst.writeRaw(keys, values, sync); err != nil {
+			return err
+		}
+		count += int64(len(keys))
+		keys, values, size = keys[:0], values[:0], 0
+		if cb != nil {
+			return cb(count)
+		}
+		return nil
+	}
+	err = rsrc.iterateRaw(func(key, value []byte) error {
+		// keep the last batch not empty to be written with sync.
+		if size >= convertBatchSize {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+		// iterators may reuse the buffers, so they need to be copied.
+		keys = append(keys, append([]byte{}, key...))
+		values = append(values, append([]byte{}, value...))
+		size += len(key) + len(value)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush(true)
+}
+
+func digestOf(rdb rawDatabase) (int64, []byte, error) {
+	var count int64
+	h := sha256.New()
+	var lb [binary.MaxVarintLen64]byte
+	err := rdb.iterateRaw(func(key, value []byte) error {
+		count++
+		h.Write(lb[:binary.PutUvarint(lb[:], uint64(len(key)))])
+		h.Write(key)
+		h.Write(lb[:binary.PutUvarint(lb[:], uint64(len(value)))])
+		h.Write(value)
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, h.Sum(nil), nil
+}
+
+// Verify checks whether dst has the same entries as src, which is used
+// to check the database copied by Convert.
+func Verify(dst, src Database) error {
+	rdst, rsrc, err := rawDatabasesOf(dst, src)
+	if err != nil {
+		return err
+	}
+	scnt, sdgst, err := digestOf(rsrc)
+	if err != nil {
+		return err
+	}
+	dcnt, ddgst, err := digestOf(rdst)
+	if err != nil {
+		return err
+	}
+	if scnt != dcnt || !bytes.Equal(sdgst, ddgst) {
+		return errors.InvalidStateError.Errorf(
+			"DatabaseMismatch(src=%d,dst=%d)", scnt, dcnt)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	src, err := NewGoLevelDB("src", dir)
+	assert.NoError(t, err)
+	defer src.Close()
+	dst, err := NewPebbleDB("dst", dir)
+	assert.NoError(t, err)
+	defer dst.Close()
+
+	buckets := []BucketID{MerkleTrie, BytesByHash, ChainProperty}
+	for _, id := range buckets {
+		bk, err := src.GetBucket(id)
+		assert.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, bk.Set([]byte(fmt.Sprint("key", i)), []byte(fmt.Sprint(id, i))))
+		}
+	}
+
+	var copied int64
+	err = Convert(dst, src, func(count int64) error {
+		copied = count
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(buckets)*10, copied)
+
+	for _, id := range buckets {
+		bk, err := dst.GetBucket(id)
+		assert.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			value, err := bk.Get([]byte(fmt.Sprint("key", i)))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte(fmt.Sprint(id, i)), value)
+		}
+	}
+
+	assert.NoError(t, Verify(dst, src))
+	bk, err := dst.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("key0"), []byte("changed")))
+	assert.Error(t, Verify(dst, src))
+	assert.NoError(t, bk.Set([]byte("key0"), []byte(fmt.Sprint(ChainProperty, 0))))
+	assert.NoError(t, bk.Set([]byte("extra"), []byte("value")))
+	assert.Error(t, Verify(dst, src))
+
+	err = Convert(NewMapDB(), src, nil)
+	assert.Error(t, err)
+	assert.Error(t, Verify(NewMapDB(), src))
+}
@@ -5,7 +5,9 @@ import (
 	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const GoLevelDBBackend BackendType = "goleveldb"
@@ -79,6 +81,76 @@ func (db *GoLevelDB) Close() error {
 	return nil
 }
 
+func (db *GoLevelDB) NewBatch() Batch {
+	return &goLevelBatch{db: db}
+}
+
+func (db *GoLevelDB) iterateRaw(cb func(key, value []byte) error) error {
+	db.lock.Lock()
+	ldb := db.db
+	db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	iter := ldb.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if err := cb(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (db *GoLevelDB) writeRaw(keys, values [][]byte, sync bool) error {
+	db.lock.Lock()
+	ldb := db.db
+	db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	batch := new(leveldb.Batch)
+	for i, key := range keys {
+		batch.Put(key, values[i])
+	}
+	return ldb.Write(batch, &opt.WriteOptions{Sync: sync})
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*goLevelBatch)(nil)
+
+type goLevelBatch struct {
+	batchOps
+	db *GoLevelDB
+}
+
+func (b *goLevelBatch) Write() error {
+	b.db.lock.Lock()
+	ldb := b.db.db
+	b.db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	batch := new(leveldb.Batch)
+	for _, op := range b.ops {
+		if op.deleted {
+			batch.Delete(internalKey(op.id, op.key))
+		} else {
+			batch.Put(internalKey(op.id, op.key), op.value)
+		}
+	}
+	if err := ldb.Write(batch, nil); err != nil {
+		return err
+	}
+	b.ops = nil
+	return nil
+}
+
 //----------------------------------------
 // GetBucket
 
@@ -109,3 +181,23 @@ func (bucket *goLevelBucket) Set(key []byte, value []byte) error {
 func (bucket *goLevelBucket) Delete(key []byte) error {
 	return bucket.db.Delete(internalKey(bucket.id, key), nil)
 }
+
+func (bucket *goLevelBucket) NewIterator(r *Range) Iterator {
+	ir := internalRangeOf(bucket.id, r)
+	return &goLevelIterator{
+		Iterator: bucket.db.NewIterator(&util.Range{Start: ir.Start, Limit: ir.Limit}, nil),
+		prefix:   len(bucket.id),
+	}
+}
+
+type goLevelIterator struct {
+	iterator.Iterator
+	prefix int
+}
+
+func (i *goLevelIterator) Key() []byte {
+	if key := i.Iterator.Key(); key != nil {
+		return key[i.prefix:]
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Iterator iterates entries of a bucket in ascending order of keys.
+// It's positioned before the first entry, so Next should be called before
+// accessing the entry. Returned key and value may be changed by Next, so
+// they need to be copied to keep. It should be released after use.
+type Iterator interface {
+	// Next moves to the next entry. It returns false if there is no more
+	// entry or it fails.
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// Range is the range of keys [Start, Limit). Start with nil means the
+// first key, and Limit with nil means no limit.
+type Range struct {
+	Start []byte
+	Limit []byte
+}
+
+// PrefixRange returns the range of keys having the prefix.
+func PrefixRange(prefix []byte) *Range {
+	var limit []byte
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if c := prefix[i]; c < 0xff {
+			limit = make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] = c + 1
+			break
+		}
+	}
+	return &Range{Start: prefix, Limit: limit}
+}
+
+func (r *Range) contains(key []byte) bool {
+	if r == nil {
+		return true
+	}
+	if r.Start != nil && bytes.Compare(key, r.Start) < 0 {
+		return false
+	}
+	if r.Limit != nil && bytes.Compare(key, r.Limit) >= 0 {
+		return false
+	}
+	return true
+}
+
+// internalRangeOf returns the range of internal keys for the range of
+// the bucket.
+func internalRangeOf(id BucketID, r *Range) *Range {
+	ir := PrefixRange([]byte(id))
+	if r != nil {
+		if r.Start != nil {
+			ir.Start = internalKey(id, r.Start)
+		}
+		if r.Limit != nil {
+			ir.Limit = internalKey(id, r.Limit)
+		}
+	}
+	return ir
+}
+
+// IterableBucket is implemented by the buckets supporting iteration.
+type IterableBucket interface {
+	Bucket
+
+	// NewIterator returns an iterator for the keys in the range.
+	// If r is nil, then it iterates all the keys in the bucket.
+	// Note that the buckets of MerkleTrie may have entries of other
+	// buckets for the backends sharing one key space among the buckets.
+	NewIterator(r *Range) Iterator
+}
+
+// NewIterator returns an iterator of the bucket for the keys in the range.
+// It returns errors.UnsupportedError if the bucket doesn't support it.
+func NewIterator(bk Bucket, r *Range) (Iterator, error) {
+	if ib, ok := bk.(IterableBucket); ok {
+		return ib.NewIterator(r), nil
+	}
+	return nil, errors.UnsupportedError.Errorf("IterationNotSupported(bucket=%T)", bk)
+}
+
+type errorIterator struct {
+	error
+}
+
+func (i *errorIterator) Next() bool    { return false }
+func (i *errorIterator) Key() []byte   { return nil }
+func (i *errorIterator) Value() []byte { return nil }
+func (i *errorIterator) Error() error  { return i.error }
+func (i *errorIterator) Release()      {}
+
+// sliceIterator iterates over snapshot of entries sorted by key.
+type sliceIterator struct {
+	keys   []string
+	values [][]byte
+	index  int
+}
+
+func (i *sliceIterator) Next() bool {
+	if i.index < len(i.keys) {
+		i.index += 1
+	}
+	return i.index < len(i.keys)
+}
+
+func (i *sliceIterator) valid() bool {
+	return i.index >= 0 && i.index < len(i.keys)
+}
+
+func (i *sliceIterator) Key() []byte {
+	if !i.valid() {
+		return nil
+	}
+	return []byte(i.keys[i.index])
+}
+
+func (i *sliceIterator) Value() []byte {
+	if !i.valid() {
+		return nil
+	}
+	return i.values[i.index]
+}
+
+func (i *sliceIterator) Error() error { return nil }
+
+func (i *sliceIterator) Release() {
+	i.keys = nil
+	i.values = nil
+	i.index = 0
+}
+
+// newSliceIterator returns an iterator for entries of m in the range.
+// values are used as they are, so they shouldn't be changed later.
+func newSliceIterator(m map[string][]byte, r *Range) *sliceIterator {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if r.contains([]byte(k)) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	values := make([][]byte, len(keys))
+	for idx, k := range keys {
+		values[idx] = m[k]
+	}
+	return &sliceIterator{keys: keys, values: values, index: -1}
+}
+
+// mergeIterator iterates entries of upper over entries of lower. Entries
+// of upper with nil value hide the entries of lower with the same keys.
+type mergeIterator struct {
+	upper *sliceIterator
+	lower Iterator
+
+	started   bool
+	uok, lok  bool
+	fromUpper bool
+	both      bool
+	key       []byte
+	value     []byte
+	err       error
+}
+
+func (i *mergeIterator) advance() {
+	if i.fromUpper {
+		i.uok = i.upper.Next()
+		if i.both {
+			i.lok = i.lower.Next()
+		}
+	} else {
+		i.lok = i.lower.Next()
+	}
+}
+
+func (i *mergeIterator) Next() bool {
+	if !i.started {
+		i.started = true
+		i.uok, i.lok = i.upper.Next(), i.lower.Next()
+	} else {
+		i.advance()
+	}
+	for {
+		if err := i.lower.Error(); err != nil {
+			i.err = err
+			i.uok, i.lok = false, false
+		}
+		if !i.uok && !i.lok {
+			i.key, i.value = nil, nil
+			return false
+		}
+		var c int
+		switch {
+		case !i.uok:
+			c = 1
+		case !i.lok:
+			c = -1
+		default:
+			c = bytes.Compare(i.upper.Key(), i.lower.Key())
+		}
+		if c > 0 {
+			i.fromUpper, i.both = false, false
+			i.key, i.value = i.lower.Key(), i.lower.Value()
+			return true
+		}
+		i.fromUpper, i.both = true, c == 0
+		if i.upper.Value() == nil {
+			// deleted in upper
+			i.advance()
+			continue
+		}
+		i.key, i.value = i.upper.Key(), i.upper.Value()
+		return true
+	}
+}
+
+func (i *mergeIterator) Key() []byte   { return i.key }
+func (i *mergeIterator) Value() []byte { return i.value }
+func (i *mergeIterator) Error() error  { return i.err }
+
+func (i *mergeIterator) Release() {
+	i.upper.Release()
+	i.lower.Release()
+	i.key, i.value = nil, nil
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectEntries(t *testing.T, bk Bucket, r *Range) []string {
+	iter, err := NewIterator(bk, r)
+	assert.NoError(t, err)
+	defer iter.Release()
+
+	var entries []string
+	for iter.Next() {
+		entries = append(entries, string(iter.Key())+"="+string(iter.Value()))
+	}
+	assert.NoError(t, iter.Error())
+	return entries
+}
+
+func testDatabase_Iterator(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bk, err := testDB.GetBucket("hello")
+	assert.NoError(t, err)
+	other, err := testDB.GetBucket("hellp")
+	assert.NoError(t, err)
+
+	for _, k := range []string{"a1", "a2", "b1", "b2", "c"} {
+		assert.NoError(t, bk.Set([]byte(k), []byte("v"+k)))
+	}
+	assert.NoError(t, other.Set([]byte("a3"), []byte("x")))
+
+	assert.Equal(t,
+		[]string{"a1=va1", "a2=va2", "b1=vb1", "b2=vb2", "c=vc"},
+		collectEntries(t, bk, nil))
+	assert.Equal(t,
+		[]string{"a1=va1", "a2=va2"},
+		collectEntries(t, bk, PrefixRange([]byte("a"))))
+	assert.Equal(t,
+		[]string{"a2=va2", "b1=vb1"},
+		collectEntries(t, bk, &Range{Start: []byte("a2"), Limit: []byte("b2")}))
+	assert.Equal(t,
+		[]string{"b2=vb2", "c=vc"},
+		collectEntries(t, bk, &Range{Start: []byte("b2")}))
+	assert.Empty(t, collectEntries(t, bk, PrefixRange([]byte("d"))))
+}
+
+func TestDatabase_Iterator(t *testing.T) {
+	for name, creator := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Iterator(t, creator)
+		})
+	}
+	t.Run("layerdb", func(t *testing.T) {
+		testDatabase_Iterator(t, func(name string, dir string) (Database, error) {
+			return NewLayerDB(NewMapDB()), nil
+		})
+	})
+}
+
+func TestLayerDB_Iterator(t *testing.T) {
+	real := NewMapDB()
+	rbk, _ := real.GetBucket("hello")
+	for _, k := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, rbk.Set([]byte(k), []byte("r"+k)))
+	}
+
+	ldb := NewLayerDB(real)
+	bk, _ := ldb.GetBucket("hello")
+	assert.NoError(t, bk.Delete([]byte("a")))
+	assert.NoError(t, bk.Set([]byte("b"), []byte("lb")))
+	assert.NoError(t, bk.Set([]byte("bb"), []byte("lbb")))
+	assert.NoError(t, bk.Delete([]byte("d")))
+	assert.NoError(t, bk.Set([]byte("e"), []byte("le")))
+
+	assert.Equal(t,
+		[]string{"b=lb", "bb=lbb", "c=rc", "e=le"},
+		collectEntries(t, bk, nil))
+	assert.Equal(t,
+		[]string{"b=lb", "bb=lbb"},
+		collectEntries(t, bk, PrefixRange([]byte("b"))))
+
+	// the real database isn't changed until flush
+	assert.Equal(t,
+		[]string{"a=ra", "b=rb", "c=rc", "d=rd"},
+		collectEntries(t, rbk, nil))
+}
+
+func TestPrefixRange(t *testing.T) {
+	r := PrefixRange([]byte{0x01, 0xff})
+	assert.Equal(t, []byte{0x01, 0xff}, r.Start)
+	assert.Equal(t, []byte{0x02}, r.Limit)
+
+	r = PrefixRange([]byte{0xff, 0xff})
+	assert.Nil(t, r.Limit)
+	assert.True(t, r.contains([]byte{0xff, 0xff, 0xff}))
+	assert.False(t, r.contains([]byte{0xff}))
+}
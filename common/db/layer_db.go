@@ -1,11 +1,15 @@
 package db
 
 import (
+	"sort"
 	"sync"
+
+	"github.com/icon-project/goloop/common/errors"
 )
 
 type layerBucket struct {
 	lock sync.Mutex
+	id   BucketID
 	data map[string][]byte
 	real Bucket
 }
@@ -60,27 +64,50 @@ func (bk *layerBucket) Delete(key []byte) error {
 	}
 }
 
-func (bk *layerBucket) Flush(write bool) error {
+func (bk *layerBucket) NewIterator(r *Range) Iterator {
 	bk.lock.Lock()
 	defer bk.lock.Unlock()
 
-	if write && bk.data != nil {
-		for k, v := range bk.data {
-			if v == nil {
-				if err := bk.real.Delete([]byte(k)); err != nil {
-					return err
-				}
-			} else {
-				if err := bk.real.Set([]byte(k), v); err != nil {
-					return err
-				}
-			}
+	ib, ok := bk.real.(IterableBucket)
+	if !ok {
+		return &errorIterator{errors.UnsupportedError.Errorf(
+			"IterationNotSupported(bucket=%T)", bk.real)}
+	}
+	if bk.data == nil {
+		return ib.NewIterator(r)
+	}
+	return &mergeIterator{
+		upper: newSliceIterator(bk.data, r),
+		lower: ib.NewIterator(r),
+	}
+}
+
+// collect adds the changes in the layer to the batch.
+func (bk *layerBucket) collect(batch Batch) error {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
+	for k, v := range bk.data {
+		var err error
+		if v == nil {
+			err = batch.Delete(bk.id, []byte(k))
+		} else {
+			err = batch.Set(bk.id, []byte(k), v)
+		}
+		if err != nil {
+			return err
 		}
 	}
-	bk.data = nil
 	return nil
 }
 
+func (bk *layerBucket) reset() {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
+	bk.data = nil
+}
+
 type layerDB struct {
 	lock sync.Mutex
 
@@ -96,15 +123,22 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 	if bk, ok := ldb.buckets[string(id)]; ok {
 		return bk, nil
 	}
+	if ldb.flushed {
+		return ldb.real.GetBucket(id)
+	}
+	return ldb.layerBucketInLock(id)
+}
 
+func (ldb *layerDB) layerBucketInLock(id BucketID) (*layerBucket, error) {
+	if bk, ok := ldb.buckets[string(id)]; ok {
+		return bk, nil
+	}
 	realbk, err := ldb.real.GetBucket(id)
 	if err != nil {
 		return nil, err
 	}
-	if ldb.flushed {
-		return realbk, nil
-	}
 	bk := &layerBucket{
+		id:   id,
 		data: make(map[string][]byte),
 		real: realbk,
 	}
@@ -112,19 +146,38 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 	return bk, nil
 }
 
+// Flush writes the changes in the layer to the real database if write is
+// true, or discards them. After flush, the changes are made directly on
+// the real database. The changes are written with a batch, so they're
+// written all at once if the real database supports it.
 func (ldb *layerDB) Flush(write bool) error {
 	ldb.lock.Lock()
 	defer ldb.lock.Unlock()
 
-	for _, bk := range ldb.buckets {
-		if err := bk.Flush(write); err != nil {
+	if write && !ldb.flushed {
+		batch := NewBatch(ldb.real)
+		for _, bk := range ldb.buckets {
+			if err := bk.collect(batch); err != nil {
+				return err
+			}
+		}
+		if err := batch.Write(); err != nil {
 			return err
 		}
 	}
+	for _, bk := range ldb.buckets {
+		bk.reset()
+	}
 	ldb.flushed = true
 	return nil
 }
 
+// NewBatch returns a batch applying the changes to the layer. If the layer
+// is flushed already, then it applies them to the real database.
+func (ldb *layerDB) NewBatch() Batch {
+	return &layerBatch{ldb: ldb}
+}
+
 func (ldb *layerDB) Close() error {
 	return nil
 }
@@ -177,3 +230,62 @@ func Unwrap(database Database) Database {
 		return database
 	}
 }
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*layerBatch)(nil)
+
+type layerBatch struct {
+	batchOps
+	ldb *layerDB
+}
+
+func (b *layerBatch) Write() error {
+	ldb := b.ldb
+	ldb.lock.Lock()
+	defer ldb.lock.Unlock()
+
+	if ldb.flushed {
+		batch := NewBatch(ldb.real)
+		if err := b.applyTo(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		b.ops = nil
+		return nil
+	}
+
+	// lock all the buckets to apply changes at once
+	bks := make(map[BucketID]*layerBucket)
+	var ids []string
+	for _, op := range b.ops {
+		if _, ok := bks[op.id]; ok {
+			continue
+		}
+		bk, err := ldb.layerBucketInLock(op.id)
+		if err != nil {
+			return err
+		}
+		bks[op.id] = bk
+		ids = append(ids, string(op.id))
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		bk := bks[BucketID(id)]
+		bk.lock.Lock()
+		defer bk.lock.Unlock()
+	}
+	for _, op := range b.ops {
+		bk := bks[op.id]
+		if op.deleted {
+			bk.data[string(op.key)] = nil
+		} else {
+			bk.data[string(op.key)] = op.value
+		}
+	}
+	b.ops = nil
+	return nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/icon-project/goloop/common/log"
@@ -43,21 +44,71 @@ func (t *mapDatabase) GetBucket(id BucketID) (Bucket, error) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	return t.bucketInLock(id), nil
+}
+
+func (t *mapDatabase) bucketInLock(id BucketID) *mapBucket {
 	if bk, ok := t.bks[id]; ok {
-		return bk, nil
+		return bk
 	}
 	bk := &mapBucket{
 		id:   fmt.Sprintf("%s:%s", t.name, id),
 		real: make(map[string]string),
 	}
 	t.bks[id] = bk
-	return bk, nil
+	return bk
 }
 
 func (t *mapDatabase) Close() error {
 	return nil
 }
 
+func (t *mapDatabase) NewBatch() Batch {
+	return &mapBatch{db: t}
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*mapBatch)(nil)
+
+type mapBatch struct {
+	batchOps
+	db *mapDatabase
+}
+
+func (b *mapBatch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	// lock all the buckets to apply changes at once
+	bks := make(map[BucketID]*mapBucket)
+	var ids []string
+	for _, op := range b.ops {
+		if _, ok := bks[op.id]; ok {
+			continue
+		}
+		bks[op.id] = b.db.bucketInLock(op.id)
+		ids = append(ids, string(op.id))
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		bk := bks[BucketID(id)]
+		bk.mutex.Lock()
+		defer bk.mutex.Unlock()
+	}
+	for _, op := range b.ops {
+		bk := bks[op.id]
+		if op.deleted {
+			delete(bk.real, string(op.key))
+		} else {
+			bk.real[string(op.key)] = string(op.value)
+		}
+	}
+	b.ops = nil
+	return nil
+}
+
 //----------------------------------------
 // Bucket
 
@@ -115,3 +166,15 @@ func (t *mapBucket) Delete(k []byte) error {
 	delete(t.real, string(k))
 	return nil
 }
+
+func (t *mapBucket) NewIterator(r *Range) Iterator {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	m := make(map[string][]byte)
+	for k, v := range t.real {
+		if r.contains([]byte(k)) {
+			m[k] = []byte(v)
+		}
+	}
+	return newSliceIterator(m, nil)
+}
@@ -0,0 +1,313 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+const PebbleDBBackend BackendType = "pebble"
+
+func init() {
+	dbCreator := func(name string, dir string) (Database, error) {
+		return NewPebbleDB(name, dir)
+	}
+	registerDBCreator(PebbleDBBackend, dbCreator, false)
+}
+
+func NewPebbleDB(name string, dir string) (*PebbleDB, error) {
+	return NewPebbleDBWithOpts(name, dir, nil)
+}
+
+func NewPebbleDBWithOpts(name string, dir string, o *pebble.Options) (*PebbleDB, error) {
+	dbPath := filepath.Join(dir, name)
+	db, err := pebble.Open(dbPath, o)
+	if err != nil {
+		return nil, err
+	}
+	database := &PebbleDB{
+		db:      db,
+		buckets: make(map[BucketID]Bucket),
+	}
+	return database, nil
+}
+
+//----------------------------------------
+// Database
+
+var _ Database = (*PebbleDB)(nil)
+
+// PebbleDB is a database backed by Pebble. Keys are prefixed with
+// the bucket ID in the same way as GoLevelDB, so entries can be copied
+// between them as they are.
+type PebbleDB struct {
+	// lock protects db from being closed while it's used, because
+	// pebble panics on accessing closed database.
+	lock    sync.RWMutex
+	db      *pebble.DB
+	buckets map[BucketID]Bucket
+}
+
+func (db *PebbleDB) GetBucket(id BucketID) (Bucket, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return nil, pebble.ErrClosed
+	}
+
+	if bk, ok := db.buckets[id]; ok {
+		return bk, nil
+	} else {
+		bk = &pebbleBucket{
+			id:    id,
+			dbase: db,
+		}
+		db.buckets[id] = bk
+		return bk, nil
+	}
+}
+
+func (db *PebbleDB) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	if err := db.db.Close(); err != nil {
+		return err
+	}
+	db.db = nil
+	return nil
+}
+
+func (db *PebbleDB) NewBatch() Batch {
+	return &pebbleBatch{db: db}
+}
+
+func (db *PebbleDB) iterateRaw(cb func(key, value []byte) error) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	iter := db.db.NewIter(nil)
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := cb(iter.Key(), iter.Value()); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	return iter.Close()
+}
+
+func (db *PebbleDB) writeRaw(keys, values [][]byte, sync bool) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	batch := db.db.NewBatch()
+	defer batch.Close()
+	for i, key := range keys {
+		if err := batch.Set(key, values[i], nil); err != nil {
+			return err
+		}
+	}
+	if sync {
+		return batch.Commit(pebble.Sync)
+	}
+	return batch.Commit(pebble.NoSync)
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*pebbleBatch)(nil)
+
+type pebbleBatch struct {
+	batchOps
+	db *PebbleDB
+}
+
+func (b *pebbleBatch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return pebble.ErrClosed
+	}
+	batch := b.db.db.NewBatch()
+	defer batch.Close()
+	for _, op := range b.ops {
+		var err error
+		if op.deleted {
+			err = batch.Delete(internalKey(op.id, op.key), nil)
+		} else {
+			err = batch.Set(internalKey(op.id, op.key), op.value, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := batch.Commit(pebble.NoSync); err != nil {
+		return err
+	}
+	b.ops = nil
+	return nil
+}
+
+//----------------------------------------
+// Bucket
+
+var _ Bucket = (*pebbleBucket)(nil)
+
+type pebbleBucket struct {
+	id    BucketID
+	dbase *PebbleDB
+}
+
+func (bucket *pebbleBucket) Get(key []byte) ([]byte, error) {
+	bucket.dbase.lock.RLock()
+	defer bucket.dbase.lock.RUnlock()
+
+	db := bucket.dbase.db
+	if db == nil {
+		return nil, pebble.ErrClosed
+	}
+	value, closer, err := db.Get(internalKey(bucket.id, key))
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	// the value is valid only until closer is closed.
+	bs := make([]byte, len(value))
+	copy(bs, value)
+	return bs, nil
+}
+
+func (bucket *pebbleBucket) Has(key []byte) (bool, error) {
+	bucket.dbase.lock.RLock()
+	defer bucket.dbase.lock.RUnlock()
+
+	db := bucket.dbase.db
+	if db == nil {
+		return false, pebble.ErrClosed
+	}
+	_, closer, err := db.Get(internalKey(bucket.id, key))
+	if err == pebble.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+func (bucket *pebbleBucket) Set(key []byte, value []byte) error {
+	bucket.dbase.lock.RLock()
+	defer bucket.dbase.lock.RUnlock()
+
+	db := bucket.dbase.db
+	if db == nil {
+		return pebble.ErrClosed
+	}
+	return db.Set(internalKey(bucket.id, key), value, pebble.NoSync)
+}
+
+func (bucket *pebbleBucket) Delete(key []byte) error {
+	bucket.dbase.lock.RLock()
+	defer bucket.dbase.lock.RUnlock()
+
+	db := bucket.dbase.db
+	if db == nil {
+		return pebble.ErrClosed
+	}
+	return db.Delete(internalKey(bucket.id, key), pebble.NoSync)
+}
+
+func (bucket *pebbleBucket) NewIterator(r *Range) Iterator {
+	bucket.dbase.lock.RLock()
+	defer bucket.dbase.lock.RUnlock()
+
+	db := bucket.dbase.db
+	if db == nil {
+		return &errorIterator{pebble.ErrClosed}
+	}
+	ir := internalRangeOf(bucket.id, r)
+	return &pebbleIterator{
+		iter: db.NewIter(&pebble.IterOptions{
+			LowerBound: ir.Start,
+			UpperBound: ir.Limit,
+		}),
+		prefix: len(bucket.id),
+	}
+}
+
+type pebbleIterator struct {
+	iter    *pebble.Iterator
+	prefix  int
+	started bool
+	err     error
+}
+
+func (i *pebbleIterator) Next() bool {
+	if i.iter == nil {
+		return false
+	}
+	if !i.started {
+		i.started = true
+		return i.iter.First()
+	}
+	return i.iter.Next()
+}
+
+func (i *pebbleIterator) Key() []byte {
+	if i.iter == nil || !i.iter.Valid() {
+		return nil
+	}
+	return i.iter.Key()[i.prefix:]
+}
+
+func (i *pebbleIterator) Value() []byte {
+	if i.iter == nil || !i.iter.Valid() {
+		return nil
+	}
+	return i.iter.Value()
+}
+
+func (i *pebbleIterator) Error() error {
+	if i.iter == nil {
+		return i.err
+	}
+	return i.iter.Error()
+}
+
+func (i *pebbleIterator) Release() {
+	if i.iter != nil {
+		i.err = i.iter.Close()
+		i.iter = nil
+	}
+}
@@ -20,6 +20,7 @@
 package db
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"path"
@@ -288,6 +289,61 @@ func (db *RocksDB) deleteValue(cf *C.rocksdb_column_family_handle_t, k []byte) e
 	return nil
 }
 
+func (db *RocksDB) NewBatch() Batch {
+	return &rocksBatch{db: db}
+}
+
+//----------------------------------------
+// Batch
+
+var _ Batch = (*rocksBatch)(nil)
+
+type rocksBatch struct {
+	batchOps
+	db *RocksDB
+}
+
+func (b *rocksBatch) Write() error {
+	cfs := make(map[BucketID]*C.rocksdb_column_family_handle_t)
+	for _, op := range b.ops {
+		if _, ok := cfs[op.id]; ok {
+			continue
+		}
+		bk, err := b.db.GetBucket(op.id)
+		if err != nil {
+			return err
+		}
+		cfs[op.id] = bk.(*RocksBucket).cf
+	}
+
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return ErrAlreadyClosed
+	}
+	wb := C.rocksdb_writebatch_create()
+	defer C.rocksdb_writebatch_destroy(wb)
+	for _, op := range b.ops {
+		cKey := (*C.char)(unsafePointerOf(op.key))
+		if op.deleted {
+			C.rocksdb_writebatch_delete_cf(wb, cfs[op.id], cKey, C.size_t(len(op.key)))
+		} else {
+			cValue := (*C.char)(unsafePointerOf(op.value))
+			C.rocksdb_writebatch_put_cf(wb, cfs[op.id], cKey, C.size_t(len(op.key)),
+				cValue, C.size_t(len(op.value)))
+		}
+	}
+	var cErr *C.char
+	C.rocksdb_write(b.db.db, b.db.wo, wb, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	b.ops = nil
+	return nil
+}
+
 type RocksBucket struct {
 	cf *C.rocksdb_column_family_handle_t
 	db *RocksDB
@@ -308,3 +364,85 @@ func (b *RocksBucket) Set(key []byte, value []byte) error {
 func (b *RocksBucket) Delete(key []byte) error {
 	return b.db.deleteValue(b.cf, key)
 }
+
+func (b *RocksBucket) NewIterator(r *Range) Iterator {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return &errorIterator{ErrAlreadyClosed}
+	}
+	iter := &rocksIterator{
+		iter: C.rocksdb_create_iterator_cf(b.db.db, b.db.ro, b.cf),
+	}
+	if r != nil {
+		iter.start = r.Start
+		iter.limit = r.Limit
+	}
+	return iter
+}
+
+// rocksIterator iterates over a column family. It should be released
+// before the database is closed.
+type rocksIterator struct {
+	iter    *C.rocksdb_iterator_t
+	start   []byte
+	limit   []byte
+	started bool
+	key     []byte
+	value   []byte
+	err     error
+}
+
+func bytesOf(p *C.char, l C.size_t) []byte {
+	if p == nil {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(p), C.int(l))
+}
+
+func (i *rocksIterator) Next() bool {
+	if i.iter == nil {
+		return false
+	}
+	if !i.started {
+		i.started = true
+		if i.start != nil {
+			C.rocksdb_iter_seek(i.iter, (*C.char)(unsafePointerOf(i.start)), C.size_t(len(i.start)))
+		} else {
+			C.rocksdb_iter_seek_to_first(i.iter)
+		}
+	} else {
+		C.rocksdb_iter_next(i.iter)
+	}
+	i.key, i.value = nil, nil
+	if C.rocksdb_iter_valid(i.iter) == 0 {
+		var cErr *C.char
+		C.rocksdb_iter_get_error(i.iter, &cErr)
+		if cErr != nil {
+			defer C.rocksdb_free(unsafe.Pointer(cErr))
+			i.err = errors.New(C.GoString(cErr))
+		}
+		return false
+	}
+	var kLen, vLen C.size_t
+	key := bytesOf(C.rocksdb_iter_key(i.iter, &kLen), kLen)
+	if i.limit != nil && bytes.Compare(key, i.limit) >= 0 {
+		return false
+	}
+	i.key = key
+	i.value = bytesOf(C.rocksdb_iter_value(i.iter, &vLen), vLen)
+	return true
+}
+
+func (i *rocksIterator) Key() []byte   { return i.key }
+func (i *rocksIterator) Value() []byte { return i.value }
+func (i *rocksIterator) Error() error  { return i.err }
+
+func (i *rocksIterator) Release() {
+	if i.iter != nil {
+		C.rocksdb_iter_destroy(i.iter)
+		i.iter = nil
+	}
+	i.key, i.value = nil, nil
+}
@@ -143,6 +143,12 @@ func (c *connection) Close() error {
 	return c.conn.Close()
 }
 
+// NewConnection returns a connection over the conn, which is established
+// by the caller, for example with TLS.
+func NewConnection(conn net.Conn) Connection {
+	return connectionFromConn(conn)
+}
+
 func Dial(network, address string) (Connection, error) {
 	if conn, err := net.Dial(network, address); err != nil {
 		return nil, err
@@ -13,6 +13,10 @@ type Server interface {
 	// Listen specified port to watch.
 	Listen(net, addr string) error
 
+	// Attach uses the listener instead of listening by Listen. It's useful
+	// for the listeners wrapping others such as TLS.
+	Attach(listener net.Listener)
+
 	// SetHandler set handler for connection. The handler can add message
 	// handler for the connection, and clean-up resource on close.
 	SetHandler(handler ConnectionHandler)
@@ -58,6 +62,10 @@ func (s *server) Listen(network, address string) error {
 	return nil
 }
 
+func (s *server) Attach(listener net.Listener) {
+	s.listener = listener
+}
+
 func (s *server) SetHandler(handler ConnectionHandler) {
 	s.handler = handler
 }
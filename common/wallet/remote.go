@@ -0,0 +1,233 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// Messages of the remote signer protocol. Each request is answered with
+// a signerResponse of the same message.
+const (
+	signerMsgPublicKey uint = iota + 1
+	signerMsgSignPayload
+	signerMsgSignVote
+)
+
+const (
+	SignerDSA = "ecdsa/secp256k1"
+)
+
+type signerRequest struct {
+	DSA    string
+	Type   module.SignType
+	Data   []byte
+	Height int64
+	Round  int32
+	Step   module.SignStep
+}
+
+type signerResponse struct {
+	Result []byte
+	Error  string
+}
+
+// ParseSignerAddress parses the address of the remote signer, which is
+// either unix:///path/to/socket or tcp://host:port.
+func ParseSignerAddress(addr string) (network, address string, err error) {
+	idx := strings.Index(addr, "://")
+	if idx < 0 {
+		return "", "", errors.IllegalArgumentError.Errorf(
+			"InvalidSignerAddress(addr=%s)", addr)
+	}
+	network, address = addr[:idx], addr[idx+3:]
+	switch network {
+	case "unix", "tcp":
+		if address == "" {
+			return "", "", errors.IllegalArgumentError.Errorf(
+				"InvalidSignerAddress(addr=%s)", addr)
+		}
+		return network, address, nil
+	default:
+		return "", "", errors.IllegalArgumentError.Errorf(
+			"UnsupportedSignerNetwork(addr=%s)", addr)
+	}
+}
+
+// NewSignerTLSConfig returns the configuration for mutual TLS between
+// the node and the remote signer. Both sides present the certificate
+// signed by the CA in caFile and verify the certificate of the peer.
+func NewSignerTLSConfig(certFile, keyFile, caFile string, server bool) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load certificate cert=%s key=%s", certFile, keyFile)
+	}
+	ca, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to read CA certificate ca=%s", caFile)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidCACertificate(ca=%s)", caFile)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if server {
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+func dialSigner(network, address string, tlsConfig *tls.Config) (ipc.Connection, error) {
+	if tlsConfig == nil {
+		return ipc.Dial(network, address)
+	}
+	cfg := tlsConfig.Clone()
+	if cfg.ServerName == "" {
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			cfg.ServerName = host
+		}
+	}
+	conn, err := tls.Dial(network, address, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return ipc.NewConnection(conn), nil
+}
+
+// remoteWallet forwards signing requests to the remote signer. Requests
+// failed by I/O errors are sent once again with a new connection.
+type remoteWallet struct {
+	lock      sync.Mutex
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	conn      ipc.Connection
+
+	publicKey []byte
+	addr      module.Address
+}
+
+func (w *remoteWallet) request(msg uint, req *signerRequest) ([]byte, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var resp signerResponse
+	for retry := 0; ; retry++ {
+		if w.conn == nil {
+			conn, err := dialSigner(w.network, w.address, w.tlsConfig)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to connect signer addr=%s", w.address)
+			}
+			w.conn = conn
+		}
+		err := w.conn.SendAndReceive(msg, req, &resp)
+		if err == nil {
+			break
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+		if retry > 0 {
+			return nil, errors.Wrapf(err, "fail to request signer addr=%s", w.address)
+		}
+		log.Warnf("Retry signer request by err=%+v", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.InvalidStateError.Errorf("SignerRefused(err=%s)", resp.Error)
+	}
+	return resp.Result, nil
+}
+
+// Sign refuses to sign the hash, because the signer can't check what it
+// signs. Use SignPayload or SignVote instead.
+func (w *remoteWallet) Sign(data []byte) ([]byte, error) {
+	return nil, errors.UnsupportedError.New("PlainSignNotAllowed")
+}
+
+func (w *remoteWallet) SignPayload(typ module.SignType, payload []byte) ([]byte, error) {
+	return w.request(signerMsgSignPayload, &signerRequest{
+		DSA:  SignerDSA,
+		Type: typ,
+		Data: payload,
+	})
+}
+
+func (w *remoteWallet) SignVote(data []byte, height int64, round int32, step module.SignStep) ([]byte, error) {
+	return w.request(signerMsgSignVote, &signerRequest{
+		DSA:    SignerDSA,
+		Data:   data,
+		Height: height,
+		Round:  round,
+		Step:   step,
+	})
+}
+
+func (w *remoteWallet) PublicKey() []byte {
+	return w.publicKey
+}
+
+func (w *remoteWallet) Address() module.Address {
+	return w.addr
+}
+
+// OpenRemote returns the wallet signing with the remote signer at addr.
+// TLS configuration is required for tcp, and it's optional for unix.
+// The returned wallet implements module.VoteSigner and module.PayloadSigner,
+// and it refuses to sign plain hashes.
+func OpenRemote(addr string, tlsConfig *tls.Config) (module.Wallet, error) {
+	network, address, err := ParseSignerAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if network == "tcp" && tlsConfig == nil {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"TLSRequired(addr=%s)", addr)
+	}
+	w := &remoteWallet{
+		network:   network,
+		address:   address,
+		tlsConfig: tlsConfig,
+	}
+	pkBytes, err := w.request(signerMsgPublicKey, &signerRequest{DSA: SignerDSA})
+	if err != nil {
+		return nil, err
+	}
+	pk, err := crypto.ParsePublicKey(pkBytes)
+	if err != nil {
+		return nil, err
+	}
+	w.publicKey = pkBytes
+	w.addr = common.NewAccountAddressFromPublicKey(pk)
+	return w, nil
+}
@@ -0,0 +1,384 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// signState is the last vote signed by the signer.
+type signState struct {
+	Height    int64           `json:"height"`
+	Round     int32           `json:"round"`
+	Step      module.SignStep `json:"step"`
+	Data      common.HexBytes `json:"data"`
+	Signature common.HexBytes `json:"signature"`
+}
+
+func (s *signState) compare(height int64, round int32, step module.SignStep) int {
+	switch {
+	case s.Height != height:
+		return compareInt64(s.Height, height)
+	case s.Round != round:
+		return compareInt64(int64(s.Round), int64(round))
+	default:
+		return compareInt64(int64(s.Step), int64(step))
+	}
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Signer serves the remote signer protocol with the wallet. It keeps the
+// last signed height, round and step of the votes in the state file, and
+// refuses to sign the vote for lower ones or different vote for the same
+// one even after restart. Other payloads are signed only if they are of the
+// known types, and plain hashes are never signed.
+type Signer struct {
+	lock   sync.Mutex
+	wallet module.Wallet
+	path   string
+	state  signState
+	server ipc.Server
+}
+
+// NewSigner returns a signer with the wallet and the state file at path.
+func NewSigner(w module.Wallet, path string) (*Signer, error) {
+	s := &Signer{
+		wallet: w,
+		path:   path,
+	}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "fail to read sign state file=%s", path)
+		}
+	} else if err := json.Unmarshal(bs, &s.state); err != nil {
+		return nil, errors.Wrapf(err, "fail to parse sign state file=%s", path)
+	}
+	return s, nil
+}
+
+func (s *Signer) Address() module.Address {
+	return s.wallet.Address()
+}
+
+// saveState writes the state to the file before the signature is returned,
+// so the vote signed is never forgotten.
+func (s *Signer) saveState(st *signState) error {
+	bs, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(bs); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if d, err := os.Open(filepath.Dir(s.path)); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+const (
+	// authNoisePrefix is the prefix of the static key of noise handshake
+	// signed for the peer authentication.
+	authNoisePrefix = "GOLOOP_NOISE_STATIC_KEY:"
+	authKeyLen      = 32
+
+	// txPatchPrefix is the prefix of the serialized patch transaction.
+	// Special characters in the values are escaped on serialization, so
+	// the fields can't be forged with the data.
+	txPatchPrefix  = "icx_sendTransaction.data."
+	txPatchMarker  = ".dataType.patch.from."
+	txPatchVersion = ".version.0x3"
+)
+
+var decisionHasher func(uid string, bs []byte) ([]byte, error)
+
+// SetDecisionHasher sets the function returning the hash of BTP decision
+// bytes for the network type. BTP decisions are refused without it.
+func SetDecisionHasher(f func(uid string, bs []byte) ([]byte, error)) {
+	decisionHasher = f
+}
+
+// payloadHash returns the hash to sign for the payload if it's of the type.
+func payloadHash(typ module.SignType, payload []byte) ([]byte, error) {
+	switch typ {
+	case module.SignTypeAuth:
+		// secret derived for the secure connection or static key of noise
+		if len(payload) == 16 || len(payload) == 32 ||
+			(len(payload) == len(authNoisePrefix)+authKeyLen &&
+				bytes.HasPrefix(payload, []byte(authNoisePrefix))) {
+			return crypto.SHA3Sum256(payload), nil
+		}
+	case module.SignTypeTransaction:
+		// only patch transactions are sent by the node
+		if bytes.HasPrefix(payload, []byte(txPatchPrefix)) &&
+			bytes.Count(payload, []byte(txPatchMarker)) == 1 &&
+			bytes.HasSuffix(payload, []byte(txPatchVersion)) {
+			return crypto.SHA3Sum256(payload), nil
+		}
+	default:
+		prefix := string(module.SignTypeBTPDecision) + "/"
+		if strings.HasPrefix(string(typ), prefix) && decisionHasher != nil {
+			return decisionHasher(string(typ)[len(prefix):], payload)
+		}
+		return nil, errors.IllegalArgumentError.Errorf("UnknownSignType(type=%s)", typ)
+	}
+	return nil, errors.IllegalArgumentError.Errorf("InvalidPayload(type=%s)", typ)
+}
+
+// SignPayload signs the hash of the payload if it's of the type.
+func (s *Signer) SignPayload(typ module.SignType, payload []byte) ([]byte, error) {
+	h, err := payloadHash(typ, payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.wallet.Sign(h)
+}
+
+// voteItem is an encoded field of the proposal or the vote.
+type voteItem []byte
+
+func (i *voteItem) UnmarshalRLP(bs []byte) error {
+	*i = append((*i)[:0], bs...)
+	return nil
+}
+
+// voteDecision returns the fields of the proposal or the vote after checking
+// height, round and type of it. Timestamp of the vote is excluded, so votes
+// differing only in the timestamp have the same decision.
+func voteDecision(data []byte, height int64, round int32, step module.SignStep) ([]voteItem, error) {
+	var items []voteItem
+	if _, err := codec.BC.UnmarshalFromBytes(data, &items); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidVote")
+	}
+	// proposal: Height, Round, BlockPartSetID, POLRound
+	// vote: Height, Round, Type, BlockID, BlockPartSetID, Timestamp
+	n := 4
+	if step != module.SignStepProposal {
+		n = 6
+	}
+	if len(items) != n {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidVoteFields(n=%d)", len(items))
+	}
+	var h int64
+	var r int32
+	if _, err := codec.BC.UnmarshalFromBytes(items[0], &h); err != nil || h != height {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidHeight(height=%d)", height)
+	}
+	if _, err := codec.BC.UnmarshalFromBytes(items[1], &r); err != nil || r != round {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidRound(round=%d)", round)
+	}
+	if step != module.SignStepProposal {
+		var vt int
+		if _, err := codec.BC.UnmarshalFromBytes(items[2], &vt); err != nil ||
+			module.SignStep(vt+int(module.SignStepPrevote)) != step {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidVoteType(step=%d)", step)
+		}
+		items = items[:n-1]
+	}
+	return items, nil
+}
+
+func sameDecision(a, b []voteItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SignVote signs the proposal or the vote in data. The same one as the last
+// gets the signature of the last, and the vote differing from the last only
+// in the timestamp is signed as it doesn't conflict with the last.
+func (s *Signer) SignVote(data []byte, height int64, round int32, step module.SignStep) ([]byte, error) {
+	if step < module.SignStepProposal || step > module.SignStepPrecommit {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidStep(step=%d)", step)
+	}
+	decision, err := voteDecision(data, height, round, step)
+	if err != nil {
+		return nil, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch s.state.compare(height, round, step) {
+	case 1:
+		return nil, errors.InvalidStateError.Errorf(
+			"AlreadySignedHigher(height=%d,round=%d,step=%d,last=%d/%d/%d)",
+			height, round, step, s.state.Height, s.state.Round, s.state.Step)
+	case 0:
+		if bytes.Equal(s.state.Data, data) {
+			return s.state.Signature, nil
+		}
+		last, err := voteDecision(s.state.Data, height, round, step)
+		if err != nil || !sameDecision(last, decision) {
+			return nil, errors.InvalidStateError.Errorf(
+				"ConflictingVote(height=%d,round=%d,step=%d)", height, round, step)
+		}
+	}
+	sig, err := s.wallet.Sign(crypto.SHA3Sum256(data))
+	if err != nil {
+		return nil, err
+	}
+	st := signState{
+		Height:    height,
+		Round:     round,
+		Step:      step,
+		Data:      data,
+		Signature: sig,
+	}
+	if err := s.saveState(&st); err != nil {
+		return nil, errors.Wrapf(err, "fail to save sign state file=%s", s.path)
+	}
+	s.state = st
+	return sig, nil
+}
+
+func (s *Signer) handle(msg uint, req *signerRequest) ([]byte, error) {
+	if req.DSA != SignerDSA {
+		return nil, errors.UnsupportedError.Errorf("UnsupportedDSA(dsa=%s)", req.DSA)
+	}
+	switch msg {
+	case signerMsgPublicKey:
+		return s.wallet.PublicKey(), nil
+	case signerMsgSignPayload:
+		return s.SignPayload(req.Type, req.Data)
+	case signerMsgSignVote:
+		return s.SignVote(req.Data, req.Height, req.Round, req.Step)
+	default:
+		return nil, errors.UnsupportedError.Errorf("UnknownMessage(msg=%d)", msg)
+	}
+}
+
+func (s *Signer) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
+	var req signerRequest
+	if _, err := codec.MP.UnmarshalFromBytes(data, &req); err != nil {
+		return err
+	}
+	var resp signerResponse
+	if result, err := s.handle(msg, &req); err != nil {
+		log.Warnf("Signer: refuse request msg=%d err=%v", msg, err)
+		resp.Error = err.Error()
+	} else {
+		resp.Result = result
+	}
+	return c.Send(msg, &resp)
+}
+
+func (s *Signer) OnConnect(c ipc.Connection) error {
+	c.SetHandler(signerMsgPublicKey, s)
+	c.SetHandler(signerMsgSignPayload, s)
+	c.SetHandler(signerMsgSignVote, s)
+	return nil
+}
+
+func (s *Signer) OnClose(c ipc.Connection) {
+	// do nothing
+}
+
+// Listen listens at addr, which is either unix:///path/to/socket or
+// tcp://host:port. TLS configuration is required for tcp.
+func (s *Signer) Listen(addr string, tlsConfig *tls.Config) error {
+	network, address, err := ParseSignerAddress(addr)
+	if err != nil {
+		return err
+	}
+	if network == "tcp" && tlsConfig == nil {
+		return errors.IllegalArgumentError.Errorf("TLSRequired(addr=%s)", addr)
+	}
+	server := ipc.NewServer()
+	if tlsConfig == nil {
+		if err := server.Listen(network, address); err != nil {
+			return err
+		}
+	} else {
+		if network == "unix" {
+			_ = os.Remove(address)
+		}
+		listener, err := net.Listen(network, address)
+		if err != nil {
+			return err
+		}
+		if ul, ok := listener.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(true)
+		}
+		server.Attach(tls.NewListener(listener, tlsConfig))
+	}
+	if network == "unix" {
+		if err := os.Chmod(address, 0600); err != nil {
+			_ = server.Close()
+			return err
+		}
+	}
+	server.SetHandler(s)
+	s.server = server
+	return nil
+}
+
+// Loop serves the connections until it's closed.
+func (s *Signer) Loop() error {
+	return s.server.Loop()
+}
+
+func (s *Signer) Close() error {
+	return s.server.Close()
+}
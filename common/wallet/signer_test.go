@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/module"
+)
+
+type testVote struct {
+	Height    int64
+	Round     int32
+	Type      int
+	BlockID   []byte
+	PartSetID []byte
+	Timestamp int64
+}
+
+func newTestVote(height int64, round int32, step module.SignStep, bid string, ts int64) []byte {
+	return codec.BC.MustMarshalToBytes(&testVote{
+		Height:    height,
+		Round:     round,
+		Type:      int(step - module.SignStepPrevote),
+		BlockID:   []byte(bid),
+		Timestamp: ts,
+	})
+}
+
+type testProposal struct {
+	Height    int64
+	Round     int32
+	PartSetID []byte
+	POLRound  int32
+}
+
+func newTestProposal(height int64, round int32, psid string) []byte {
+	return codec.BC.MustMarshalToBytes(&testProposal{
+		Height:    height,
+		Round:     round,
+		PartSetID: []byte(psid),
+		POLRound:  -1,
+	})
+}
+
+func TestSigner_SignVote(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	w := New()
+
+	s, err := NewSigner(w, path)
+	assert.NoError(t, err)
+
+	d1 := newTestVote(10, 0, module.SignStepPrevote, "block1", 1)
+	d2 := newTestVote(10, 0, module.SignStepPrevote, "block2", 1)
+
+	sig, err := s.SignVote(d1, 10, 0, module.SignStepPrevote)
+	assert.NoError(t, err)
+	csig, err := crypto.ParseSignature(sig)
+	assert.NoError(t, err)
+	pk, err := csig.RecoverPublicKey(crypto.SHA3Sum256(d1))
+	assert.NoError(t, err)
+	assert.Equal(t, w.PublicKey(), pk.SerializeCompressed())
+
+	// same vote is signed again with the same signature
+	sig2, err := s.SignVote(d1, 10, 0, module.SignStepPrevote)
+	assert.NoError(t, err)
+	assert.Equal(t, sig, sig2)
+
+	// conflicting vote
+	_, err = s.SignVote(d2, 10, 0, module.SignStepPrevote)
+	assert.Error(t, err)
+
+	// vote differing only in timestamp doesn't conflict
+	d3 := newTestVote(10, 0, module.SignStepPrevote, "block1", 2)
+	sig3, err := s.SignVote(d3, 10, 0, module.SignStepPrevote)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sig, sig3)
+
+	// data mismatching height, round or step
+	_, err = s.SignVote(newTestVote(11, 0, module.SignStepPrecommit, "block1", 1), 10, 0, module.SignStepPrecommit)
+	assert.Error(t, err)
+	_, err = s.SignVote(newTestVote(10, 1, module.SignStepPrecommit, "block1", 1), 10, 0, module.SignStepPrecommit)
+	assert.Error(t, err)
+	_, err = s.SignVote(newTestVote(10, 0, module.SignStepPrevote, "block1", 1), 10, 0, module.SignStepPrecommit)
+	assert.Error(t, err)
+	_, err = s.SignVote(crypto.SHA3Sum256(d1), 10, 0, module.SignStepPrecommit)
+	assert.Error(t, err)
+
+	_, err = s.SignVote(newTestVote(10, 0, module.SignStepPrecommit, "block2", 1), 10, 0, module.SignStepPrecommit)
+	assert.NoError(t, err)
+
+	// refused after restart
+	s, err = NewSigner(w, path)
+	assert.NoError(t, err)
+	_, err = s.SignVote(newTestVote(10, 0, module.SignStepPrecommit, "block1", 1), 10, 0, module.SignStepPrecommit)
+	assert.Error(t, err)
+	_, err = s.SignVote(newTestVote(9, 5, module.SignStepPrecommit, "block1", 1), 9, 5, module.SignStepPrecommit)
+	assert.Error(t, err)
+	_, err = s.SignVote(newTestProposal(10, 1, "parts"), 10, 1, module.SignStepProposal)
+	assert.NoError(t, err)
+	_, err = s.SignVote(newTestProposal(10, 1, "others"), 10, 1, module.SignStepProposal)
+	assert.Error(t, err)
+}
+
+func TestSigner_SignPayload(t *testing.T) {
+	w := New()
+	s, err := NewSigner(w, filepath.Join(t.TempDir(), "state.json"))
+	assert.NoError(t, err)
+
+	// plain hash is signed only as the secret for authentication
+	_, err = s.SignPayload(module.SignTypeAuth, crypto.SHA3Sum256([]byte("secret")))
+	assert.NoError(t, err)
+	_, err = s.SignPayload(module.SignTypeAuth, append([]byte(authNoisePrefix), make([]byte, authKeyLen)...))
+	assert.NoError(t, err)
+	_, err = s.SignPayload(module.SignTypeAuth, newTestVote(10, 0, module.SignStepPrevote, "block1", 1))
+	assert.Error(t, err)
+
+	patch := "icx_sendTransaction.data.{data.0x00.type.double_sign}.dataType.patch.from.hx00" +
+		".nid.0x1.stepLimit.0x0.timestamp.0x1.to.cx0000000000000000000000000000000000000000.version.0x3"
+	_, err = s.SignPayload(module.SignTypeTransaction, []byte(patch))
+	assert.NoError(t, err)
+	transfer := "icx_sendTransaction.from.hx00.nid.0x1.stepLimit.0x0.timestamp.0x1" +
+		".to.hx01.value.0x100.version.0x3"
+	_, err = s.SignPayload(module.SignTypeTransaction, []byte(transfer))
+	assert.Error(t, err)
+
+	_, err = s.SignPayload(module.SignType("hash"), crypto.SHA3Sum256([]byte("data")))
+	assert.Error(t, err)
+	_, err = s.SignPayload(module.BTPDecisionSignType("unknown"), []byte("data"))
+	assert.Error(t, err)
+}
+
+func TestSigner_Remote(t *testing.T) {
+	dir := t.TempDir()
+	w := New()
+	s, err := NewSigner(w, filepath.Join(dir, "state.json"))
+	assert.NoError(t, err)
+
+	addr := "unix://" + filepath.Join(dir, "signer.sock")
+	assert.NoError(t, s.Listen(addr, nil))
+	go s.Loop()
+	defer s.Close()
+
+	rw, err := OpenRemote(addr, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, w.PublicKey(), rw.PublicKey())
+	assert.True(t, w.Address().Equal(rw.Address()))
+
+	// plain hash is never signed
+	data := crypto.SHA3Sum256([]byte("data"))
+	_, err = rw.Sign(data)
+	assert.Error(t, err)
+
+	ps, ok := rw.(module.PayloadSigner)
+	assert.True(t, ok)
+	sig, err := ps.SignPayload(module.SignTypeAuth, data)
+	assert.NoError(t, err)
+	csig, err := crypto.ParseSignature(sig)
+	assert.NoError(t, err)
+	pk, err := csig.RecoverPublicKey(crypto.SHA3Sum256(data))
+	assert.NoError(t, err)
+	assert.Equal(t, w.PublicKey(), pk.SerializeCompressed())
+
+	vs, ok := rw.(module.VoteSigner)
+	assert.True(t, ok)
+	_, err = vs.SignVote(newTestVote(1, 0, module.SignStepPrecommit, "block1", 1), 1, 0, module.SignStepPrecommit)
+	assert.NoError(t, err)
+	_, err = vs.SignVote(newTestVote(1, 0, module.SignStepPrecommit, "block2", 1), 1, 0, module.SignStepPrecommit)
+	assert.Error(t, err)
+
+	_, err = OpenRemote("tcp://127.0.0.1:0", nil)
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/btp"
+	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
@@ -36,26 +37,25 @@ type LastVoteData struct {
 	VotesBytes []byte
 }
 
-const (
-	timeoutPropose   = time.Second * 1
-	timeoutPrevote   = time.Second * 1
-	timeoutPrecommit = time.Second * 1
-	timeoutNewRound  = time.Second * 1
-)
-
 const (
 	ConfigEnginePriority = 2
 	ConfigSyncerPriority = 3
 
-	ConfigBlockPartSize               = 1024 * 100
-	configCommitCacheCap              = 60
-	configRoundWALID                  = "round"
-	configRoundWALDataSize            = 1024 * 500
-	configLockWALID                   = "lock"
-	configLockWALDataSize             = 1024 * 1024 * 5
-	configCommitWALID                 = "commit"
-	configCommitWALDataSize           = 1024 * 500
-	configRoundTimeoutThresholdFactor = 2
+	ConfigBlockPartSize     = 1024 * 100
+	configCommitCacheCap    = 60
+	configRoundWALID        = "round"
+	configRoundWALDataSize  = 1024 * 500
+	configLockWALID         = "lock"
+	configLockWALDataSize   = 1024 * 1024 * 5
+	configCommitWALID       = "commit"
+	configCommitWALDataSize = 1024 * 500
+
+	configEvidenceWALID       = "evidence"
+	configEvidenceWALDataSize = 1024 * 100
+
+	// evidences for recent heights are sent again on start since they
+	// may not be included in a block yet.
+	configEvidenceResendHeights = 10
 )
 
 type hrs struct {
@@ -81,6 +81,7 @@ type consensus struct {
 	roundWAL    *WalMessageWriter
 	lockWAL     *WalMessageWriter
 	commitWAL   *WalMessageWriter
+	evidenceWAL *WalMessageWriter
 	timestamper module.Timestamper
 	nid         []byte
 	bpp         fastsync.BlockProofProvider
@@ -93,6 +94,9 @@ type consensus struct {
 	minimizeBlockGen   bool
 	roundLimit         int32
 	sentPatch          bool
+	doubleSigners      *bitArray
+	blockHeaders       map[string][]byte
+	pendingEvidences   []*doubleSignPatch
 	lastVotes          VoteSet
 	hvs                heightVoteSet
 	nextProposeTime    time.Time
@@ -117,7 +121,8 @@ type consensus struct {
 	prefetchItems []fastsync.BlockResult
 
 	// monitor
-	metric *metric.ConsensusMetric
+	metric   *metric.ConsensusMetric
+	watchers []*eventWatcher
 
 	lastVoteData *LastVoteData
 }
@@ -195,6 +200,9 @@ func (cs *consensus) _resetForNewHeight(prevBlock module.Block, votes *voteSet)
 	cs.minimizeBlockGen = cs.c.ServiceManager().GetMinimizeBlockGen(cs.lastBlock.Result())
 	cs.roundLimit = int32(cs.c.ServiceManager().GetRoundLimit(cs.lastBlock.Result(), cs.validators.Len()))
 	cs.sentPatch = false
+	cs.resolveEvidences(true)
+	cs.doubleSigners = newBitArray(cs.validators.Len())
+	cs.blockHeaders = make(map[string][]byte)
 	cs.lastVotes = votes
 	cs.hvs.reset(cs.validators.Len())
 	cs.lockedRound = -1
@@ -218,6 +226,8 @@ func (cs *consensus) resetForNewHeight(prevBlock module.Block, votes *voteSet) {
 
 func (cs *consensus) _resetForNewRound(round int32) {
 	cs.proposalPOLRound = -1
+	cs.addBlockHeader(cs.currentBlockParts.block)
+	cs.resolveEvidences(false)
 	cs.currentBlockParts.Zerofy()
 	cs.round = round
 	cs.hvs.removeLowerRoundExcept(cs.round-1, cs.lockedRound)
@@ -273,6 +283,7 @@ func (cs *consensus) beginStep(step step) {
 	}
 	cs.step = step
 	cs.log.Debugf("enterStep %v\n", cs.hrs)
+	cs.notifyStep()
 }
 
 func (cs *consensus) OnReceive(
@@ -290,11 +301,13 @@ func (cs *consensus) OnReceive(
 	msg, err := UnmarshalMessage(sp.Uint16(), bs)
 	if err != nil {
 		cs.log.Warnf("malformed consensus message: OnReceive(subprotocol:%v, from:%v): %+v\n", sp, common.HexPre(id.Bytes()), err)
+		cs.ph.ReportPeer(id, module.OffenceMalformedMessage)
 		return false, err
 	}
 	cs.log.Debugf("OnReceive(msg:%v, from:%v)\n", msg, common.HexPre(id.Bytes()))
 	if err = msg.Verify(); err != nil {
 		cs.log.Warnf("consensus message verify failed: OnReceive(msg:%v, from:%v): %+v\n", msg, common.HexPre(id.Bytes()), err)
+		cs.ph.ReportPeer(id, module.OffenceInvalidMessage)
 		return false, err
 	}
 	switch m := msg.(type) {
@@ -311,11 +324,44 @@ func (cs *consensus) OnReceive(
 	}
 	if err != nil {
 		cs.log.Warnf("OnReceive(msg:%v, from:%v): %+v\n", msg, common.HexPre(id.Bytes()), err)
+		reportOffence(cs.ph, id, err)
 		return false, err
 	}
 	return true, nil
 }
 
+// offenceError is the error for the message which is an offence of the
+// peer. If the signer is given, only the peer of the signer is reported
+// because others may relay the message without fault.
+type offenceError struct {
+	error
+	offence module.Offence
+	signer  module.Address
+}
+
+func newOffenceError(offence module.Offence, signer module.Address, err error) error {
+	return &offenceError{err, offence, signer}
+}
+
+func (e *offenceError) Unwrap() error {
+	return e.error
+}
+
+// reportOffence reports the peer if err is caused by an offence.
+func reportOffence(ph module.ProtocolHandler, id module.PeerID, err error) {
+	cause := errors.FindCause(err, func(err error) bool {
+		_, ok := err.(*offenceError)
+		return ok
+	})
+	oe, ok := cause.(*offenceError)
+	if !ok {
+		return
+	}
+	if oe.signer == nil || network.NewPeerIDFromAddress(oe.signer).Equal(id) {
+		ph.ReportPeer(id, oe.offence)
+	}
+}
+
 func (cs *consensus) OnJoin(id module.PeerID) {
 	cs.log.Debugf("OnJoin(peer:%v)\n", common.HexPre(id.Bytes()))
 }
@@ -343,6 +389,7 @@ func (cs *consensus) ReceiveProposalMessage(msg *ProposalMessage, unicast bool)
 	}
 	cs.proposalPOLRound = msg.proposal.POLRound
 	cs.currentBlockParts.SetByPartSetID(msg.proposal.BlockPartSetID)
+	cs.notifyProposal(msg)
 
 	if (cs.step == stepTransactionWait || cs.step == stepPropose) && cs.isProposalAndPOLPrevotesComplete() {
 		cs.enterPrevote()
@@ -360,13 +407,18 @@ func (cs *consensus) ReceiveBlockPartMessage(msg *BlockPartMessage, unicast bool
 
 	bp, err := NewPart(msg.BlockPart)
 	if err != nil {
-		return -1, err
+		return -1, newOffenceError(module.OffenceMalformedMessage, nil, err)
 	}
 	if cs.currentBlockParts.GetPart(bp.Index()) != nil {
 		return -1, nil
 	}
 	added, err := cs.currentBlockParts.AddPart(bp, cs.c.BlockManager())
 	if !added && err != nil {
+		// broadcast parts may be for the proposal of another round, but
+		// unicast parts are for the block of the precommits sent before
+		if unicast {
+			return -1, newOffenceError(module.OffenceInvalidMessage, nil, err)
+		}
 		return -1, err
 	}
 	if added && err != nil {
@@ -412,23 +464,31 @@ func (cs *consensus) ReceiveVoteMessage(msg *VoteMessage, unicast bool) (int, er
 	if err != nil {
 		return -1, err
 	}
+	// the vote is handled, but the error is returned not to relay it
+	var offence error
+	if omsg := cs.hvs.conflictingVote(index, msg); omsg != nil {
+		cs.handleDoubleSign(index, omsg, msg)
+		offence = newOffenceError(module.OffenceDuplicateMessage, msg.address(),
+			errors.Errorf("conflicting vote %v", msg))
+	}
 	added, votes := cs.hvs.add(index, msg)
 	if !added {
-		return -1, nil
+		return -1, offence
 	}
+	cs.notifyVote(msg, votes)
 	if !unicast {
 		cs.consumedNonunicast = true
 	}
 
 	if !votes.hasOverTwoThirds() {
-		return index, nil
+		return index, offence
 	}
 	if msg.Type == VoteTypePrevote {
 		cs.handlePrevoteMessage(msg, votes)
 	} else {
 		cs.handlePrecommitMessage(msg, votes)
 	}
-	return index, nil
+	return index, offence
 }
 
 func (cs *consensus) ReceiveVoteListMessage(msg *VoteListMessage, unicast bool) error {
@@ -437,12 +497,116 @@ func (cs *consensus) ReceiveVoteListMessage(msg *VoteListMessage, unicast bool)
 		vmsg := msg.VoteList.Get(i)
 		if _, e := cs.ReceiveVoteMessage(vmsg, unicast); e != nil {
 			cs.log.Warnf("bad vote in vote list. VoteMessage:%v Error:%+v\n", vmsg, e)
-			err = errors.Errorf("bad vote in VoteList. LastError: %+v", e)
+			err = errors.Wrap(e, "bad vote in VoteList")
 		}
 	}
 	return err
 }
 
+// handleDoubleSign persists the evidence of double signing by the validator
+// at index, and sends it as a patch to be included in a block.
+func (cs *consensus) handleDoubleSign(index int, v1, v2 *VoteMessage) {
+	if cs.doubleSigners.Get(index) {
+		return
+	}
+	cs.doubleSigners.Set(index)
+	cs.log.Warnf("double sign detected. vote1:%v vote2:%v\n", v1, v2)
+
+	patch := newDoubleSignPatch(v1, v2)
+	if cs.evidenceWAL != nil {
+		msg := newVoteListMessage()
+		msg.VoteList = &patch.VoteList
+		if err := cs.evidenceWAL.WriteMessage(msg); err != nil {
+			cs.log.Errorf("fail to write WAL: handleDoubleSign: %+v\n", err)
+		}
+		if err := cs.evidenceWAL.Sync(); err != nil {
+			cs.log.Errorf("fail to sync WAL: handleDoubleSign: %+v\n", err)
+		}
+	}
+	cs.pendingEvidences = append(cs.pendingEvidences, patch)
+	cs.resolveEvidences(false)
+}
+
+// addBlockHeader keeps the header of the block for the evidences of double
+// signing in the current height.
+func (cs *consensus) addBlockHeader(blk module.BlockData) {
+	if blk == nil || cs.blockHeaders == nil {
+		return
+	}
+	if _, ok := cs.blockHeaders[string(blk.ID())]; ok {
+		return
+	}
+	var buf bytes.Buffer
+	if err := blk.MarshalHeader(&buf); err != nil {
+		cs.log.Warnf("fail to marshal header: %+v\n", err)
+		return
+	}
+	cs.blockHeaders[string(blk.ID())] = buf.Bytes()
+}
+
+func (cs *consensus) headerOf(id []byte) []byte {
+	for _, bps := range []*blockPartSet{&cs.currentBlockParts, &cs.lockedBlockParts} {
+		if bps.block != nil && bytes.Equal(bps.block.ID(), id) {
+			cs.addBlockHeader(bps.block)
+		}
+	}
+	if h, ok := cs.blockHeaders[string(id)]; ok {
+		return h
+	}
+	blk, err := cs.c.BlockManager().GetBlock(id)
+	if err != nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := blk.MarshalHeader(&buf); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// resolveHeaders fills the headers of the blocks for the votes in the
+// evidence. It returns false if some of them are not known yet.
+func (cs *consensus) resolveHeaders(patch *doubleSignPatch) bool {
+	if len(patch.Headers) != patch.VoteList.Len() {
+		patch.Headers = make([][]byte, patch.VoteList.Len())
+	}
+	for i := 0; i < patch.VoteList.Len(); i++ {
+		v := patch.VoteList.Get(i)
+		if v.BlockPartSetIDAndNTSVoteCount == nil || patch.Headers[i] != nil {
+			continue
+		}
+		patch.Headers[i] = cs.headerOf(v.BlockID)
+	}
+	return patch.isResolved()
+}
+
+// resolveEvidences sends the pending evidences whose blocks are known. If
+// drop is true, it discards the evidences which can't be resolved because
+// the blocks for the votes are not known in the height.
+func (cs *consensus) resolveEvidences(drop bool) {
+	var pending []*doubleSignPatch
+	for _, patch := range cs.pendingEvidences {
+		if cs.resolveHeaders(patch) {
+			cs.sendDoubleSignPatch(patch)
+		} else if drop {
+			cs.log.Warnf("drop double sign evidence without block. votes:%v\n", patch.VoteList)
+		} else {
+			pending = append(pending, patch)
+		}
+	}
+	cs.pendingEvidences = pending
+}
+
+func (cs *consensus) sendDoubleSignPatch(patch *doubleSignPatch) {
+	// only validators can send patches
+	if cs.validators.IndexOf(cs.c.Wallet().Address()) < 0 {
+		return
+	}
+	if err := cs.c.ServiceManager().SendPatch(patch); err != nil {
+		cs.log.Warnf("fail to send double sign patch: %+v\n", err)
+	}
+}
+
 func (cs *consensus) handlePrevoteMessage(msg *VoteMessage, prevotes *voteSet) {
 	if cs.step >= stepCommit {
 		return
@@ -529,21 +693,23 @@ func (cs *consensus) enterPropose() {
 	cs.resetForNewStep(stepPropose)
 
 	now := time.Now()
-	if int(cs.round) > cs.validators.Len()*configRoundTimeoutThresholdFactor {
-		cs.nextProposeTime = now.Add(timeoutNewRound)
+	rt := cs.c.Regulator().RoundTimeouts()
+	if int(cs.round) > cs.validators.Len()*rt.ThresholdFactor {
+		cs.nextProposeTime = now.Add(rt.NewRound)
 	} else {
 		cs.nextProposeTime = now
 	}
 	cs.c.Regulator().OnPropose(now)
 
 	hrs := cs.hrs
-	cs.timer = time.AfterFunc(timeoutPropose, func() {
+	cs.timer = time.AfterFunc(rt.ProposeTimeout(cs.round), func() {
 		cs.mutex.Lock()
 		defer cs.mutex.Unlock()
 
 		if cs.hrs != hrs || !cs.started {
 			return
 		}
+		cs.notifyTimeout()
 		cs.enterPrevote()
 	})
 
@@ -723,13 +889,15 @@ func (cs *consensus) enterPrevoteWait() {
 		cs.enterPrecommit()
 	} else {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrevote, func() {
+		rt := cs.c.Regulator().RoundTimeouts()
+		cs.timer = time.AfterFunc(rt.PrevoteTimeout(cs.round), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.notifyTimeout()
 			cs.enterPrecommit()
 		})
 	}
@@ -823,13 +991,15 @@ func (cs *consensus) enterPrecommitWait() {
 	} else {
 		cs.log.Traceln("enterPrecommitWait: start timer")
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrecommit, func() {
+		rt := cs.c.Regulator().RoundTimeouts()
+		cs.timer = time.AfterFunc(rt.PrecommitTimeout(cs.round), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.notifyTimeout()
 			cs.enterNewRound()
 		})
 	}
@@ -1000,8 +1170,10 @@ func (cs *consensus) doSendProposal(blockParts PartSet, polRound int32) error {
 	msg.Round = cs.round
 	msg.BlockPartSetID = blockParts.ID()
 	msg.POLRound = polRound
-	err := msg.Sign(cs.c.Wallet())
+	err := msg.signFor(cs.c.Wallet(), msg.Height, msg.Round, module.SignStepProposal)
 	if err != nil {
+		cs.log.Warnf("fail to sign proposal height=%d round=%d err=%+v\n",
+			msg.Height, msg.Round, err)
 		return err
 	}
 	msgBS, err := msgCodec.MarshalToBytes(msg)
@@ -1017,6 +1189,7 @@ func (cs *consensus) doSendProposal(blockParts PartSet, polRound int32) error {
 		return err
 	}
 	cs.log.Debugf("sendProposal %v\n", msg)
+	cs.notifyProposal(msg)
 	err = cs.ph.Broadcast(ProtoProposal, msgBS, module.BroadcastAll)
 	if err != nil {
 		cs.log.Warnf("sendProposal: %+v\n", err)
@@ -1110,8 +1283,10 @@ func (cs *consensus) ntsVoteBaseAndDecisionProofParts(
 			cs.round,
 			ntsHashEntry.NetworkTypeSectionHash,
 		)
-		pp, err := pc.NewProofPart(ntsd.Hash(), cs.c)
+		pp, err := pc.NewProofPart(ntsd.Hash(), ntm.DecisionWalletProvider(cs.c, ntsd))
 		if err != nil {
+			cs.log.Warnf("fail to sign decision ntid=%d height=%d round=%d err=%+v\n",
+				ntsHashEntry.NetworkTypeID, cs.height, cs.round, err)
 			return nil, nil, err
 		}
 		ntsdProofParts = append(ntsdProofParts, pp.Bytes())
@@ -1149,8 +1324,14 @@ func (cs *consensus) doSendVote(vt VoteType, blockParts *blockPartSet) error {
 	}
 	msg.Timestamp = cs.voteTimestamp()
 
-	err := msg.Sign(cs.c.Wallet())
+	step := module.SignStepPrevote
+	if vt == VoteTypePrecommit {
+		step = module.SignStepPrecommit
+	}
+	err := msg.signFor(cs.c.Wallet(), msg.Height, msg.Round, step)
 	if err != nil {
+		cs.log.Warnf("fail to sign vote type=%v height=%d round=%d err=%+v\n",
+			vt, msg.Height, msg.Round, err)
 		return err
 	}
 	msgBS, err := msgCodec.MarshalToBytes(msg)
@@ -1524,6 +1705,54 @@ func (cs *consensus) applyCommitWAL(prevValidators addressIndexer) error {
 	return nil
 }
 
+// applyEvidenceWAL sends the evidences of double signing for recent heights
+// again.
+func (cs *consensus) applyEvidenceWAL() error {
+	wr, err := cs.wm.OpenForRead(path.Join(cs.walDir, configEvidenceWALID))
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		cs.log.Must(wr.Close())
+	}()
+	for {
+		bs, err := wr.ReadBytes()
+		if IsEOF(err) {
+			break
+		} else if IsCorruptedWAL(err) || IsUnexpectedEOF(err) {
+			cs.log.Warnf("applyEvidenceWAL: %+v\n", err)
+			err := wr.CloseAndRepair()
+			if err != nil {
+				return err
+			}
+			break
+		} else if err != nil {
+			return err
+		}
+		if len(bs) < 2 {
+			return errors.Errorf("too short wal message len=%v", len(bs))
+		}
+		sp := binary.BigEndian.Uint16(bs[0:2])
+		msg, err := UnmarshalMessage(sp, bs[2:])
+		if err != nil {
+			return err
+		}
+		if err = msg.Verify(); err != nil {
+			return err
+		}
+		if m, ok := msg.(*VoteListMessage); ok {
+			patch := &doubleSignPatch{VoteList: *m.VoteList}
+			if patch.Height() > cs.height-configEvidenceResendHeights {
+				cs.log.Tracef("WAL: evidence %v\n", m.VoteList)
+				if cs.resolveHeaders(patch) {
+					cs.sendDoubleSignPatch(patch)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (cs *consensus) applyWAL(prevValidators addressIndexer) error {
 	if err := cs.applyRoundWAL(); err != nil && !IsNotExist(err) {
 		return err
@@ -1705,6 +1934,18 @@ func (cs *consensus) Start() error {
 	}
 	cs.commitWAL = &WalMessageWriter{ww}
 
+	if err := cs.applyEvidenceWAL(); err != nil && !IsNotExist(err) {
+		return err
+	}
+	ww, err = cs.wm.OpenForWrite(path.Join(cs.walDir, configEvidenceWALID), &WALConfig{
+		FileLimit:  configEvidenceWALDataSize,
+		TotalLimit: configEvidenceWALDataSize * 3,
+	})
+	if err != nil {
+		return err
+	}
+	cs.evidenceWAL = &WalMessageWriter{ww}
+
 	cs.started = true
 	cs.log.Infof("Start consensus wallet:%v", common.HexPre(cs.c.Wallet().Address().ID()))
 	cs.syncer, err = newSyncer(cs, cs.log, cs.c.NetworkManager(), cs.c.BlockManager(), &cs.mutex, cs.c.Wallet().Address())
@@ -1740,6 +1981,7 @@ func (cs *consensus) Term() {
 	defer cs.mutex.Unlock()
 
 	cs.started = false
+	cs.removeAllWatchers()
 
 	err := cs.c.NetworkManager().UnregisterReactor(cs)
 	if err != nil {
@@ -1765,6 +2007,9 @@ func (cs *consensus) Term() {
 	if cs.commitWAL != nil {
 		cs.log.Must(cs.commitWAL.Close())
 	}
+	if cs.evidenceWAL != nil {
+		cs.log.Must(cs.evidenceWAL.Close())
+	}
 
 	if cs.log != nil {
 		cs.log.Infof("Term consensus.\n")
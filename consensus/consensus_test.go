@@ -31,6 +31,7 @@ import (
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/consensus/fastsync"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/service/platform/basic"
 	"github.com/icon-project/goloop/test"
 )
@@ -853,6 +854,59 @@ func TestConsensus_RoundWALMyVote(t *testing.T) {
 	assert.EqualValues(3, status.Round)
 }
 
+func TestConsensus_ReportPeer(t *testing.T) {
+	assert := assert.New(t)
+	f := test.NewFixture(t, test.AddDefaultNode(false), test.AddValidatorNodes(4))
+	defer f.Close()
+
+	cs, ok := f.CS.(ConsensusInternal)
+	assert.True(ok)
+	assert.NoError(cs.Start())
+
+	relay := peerID(make([]byte, 4))
+	signer := network.NewPeerIDFromAddress(f.Nodes[1].Chain.Wallet().Address())
+
+	// proposal of round 0 by Nodes[1]
+	blk := f.Nodes[1].ProposeBlock(consensus.NewEmptyCommitVoteList())
+	pmBS, _, bps := f.Nodes[1].ProposalBytesFor(blk, 0)
+	_, err := cs.OnReceive(consensus.ProtoProposal, pmBS, signer)
+	assert.NoError(err)
+
+	// conflicting votes are reported only for the peer of the signer
+	pv := f.Nodes[1].VoteFor(consensus.VoteTypePrevote, blk, bps.ID(), 0)
+	_, err = cs.OnReceive(consensus.ProtoVote, codec.MustMarshalToBytes(pv), relay)
+	assert.NoError(err)
+	npv := f.Nodes[1].NilVoteFor(consensus.VoteTypePrevote, blk, 0)
+	_, err = cs.OnReceive(consensus.ProtoVote, codec.MustMarshalToBytes(npv), relay)
+	assert.Error(err)
+	assert.Empty(f.NM.Reports(relay))
+	_, err = cs.OnReceive(consensus.ProtoVote, codec.MustMarshalToBytes(pv), signer)
+	assert.Error(err)
+	assert.Equal([]module.Offence{module.OffenceDuplicateMessage}, f.NM.Reports(signer))
+
+	// malformed block part
+	bpm := consensus.BlockPartMessage{Height: blk.Height(), BlockPart: []byte{0x01}}
+	_, err = cs.OnReceive(consensus.ProtoBlockPart, codec.MustMarshalToBytes(&bpm), relay)
+	assert.Error(err)
+	assert.Equal([]module.Offence{module.OffenceMalformedMessage}, f.NM.Reports(relay))
+
+	// invalid block part is reported only if it's sent by the syncer
+	_, badBPMBS, _ := f.Nodes[1].InvalidProposalBytesFor(blk)
+	_, err = cs.OnReceive(consensus.ProtoBlockPart, badBPMBS, relay)
+	assert.Error(err)
+	assert.Len(f.NM.Reports(relay), 1)
+
+	_, err = codec.UnmarshalFromBytes(badBPMBS, &bpm)
+	assert.NoError(err)
+	p, h := f.NM.NewPeerFor(module.ProtoConsensusSync)
+	errCh := make(chan error, 1)
+	h.Unicast(consensus.ProtoBlockPart, &bpm, func(rb bool, err error) {
+		errCh <- err
+	})
+	assert.Error(<-errCh)
+	assert.Equal([]module.Offence{module.OffenceInvalidMessage}, f.NM.Reports(p.ID()))
+}
+
 type ConsensusInternal interface {
 	module.Consensus
 	OnReceive(sp module.ProtocolInfo, bs []byte, id module.PeerID) (bool, error)
@@ -1285,3 +1339,41 @@ func TestConsensus_InvalidProposal(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(blk.ID(), cBlk.ID())
 }
+
+func TestConsensus_MemoryNetwork(t *testing.T) {
+	assert := assert.New(t)
+	mn := network.NewMemoryNetwork(nil, 1)
+	mn.SetLinkConfig(network.MemoryLinkConfig{
+		Latency: 5 * time.Millisecond,
+	})
+	f := test.NewFixture(t,
+		test.AddDefaultNode(false), test.AddValidatorNodes(4),
+		test.UseMemoryNetwork(mn),
+	)
+	defer f.Close()
+
+	validators := f.Validators
+	for _, v := range validators {
+		v.Chain.Regulator().SetRoundTimeouts(module.RoundTimeouts{
+			Propose:   200 * time.Millisecond,
+			Prevote:   200 * time.Millisecond,
+			Precommit: 200 * time.Millisecond,
+			NewRound:  200 * time.Millisecond,
+		})
+		assert.NoError(v.CS.Start())
+	}
+	test.NodeInterconnectOnMemory(validators)
+	blk := test.NodeWaitForBlock(validators, 2)
+	assert.EqualValues(2, blk.Height())
+
+	// three of four validators keep making blocks without the isolated one
+	isolated := validators[3]
+	mn.Partition([]string{isolated.NetAddress})
+	h := validators[0].GetLastBlock().Height() + 3
+	blk = test.NodeWaitForBlock(validators[:3], h)
+	assert.EqualValues(h, blk.Height())
+
+	// the isolated one catches up after the partition is healed
+	mn.Heal()
+	assert.Equal(blk.ID(), isolated.WaitForBlock(h).ID())
+}
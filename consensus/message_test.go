@@ -17,6 +17,7 @@
 package consensus
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -174,3 +175,68 @@ func TestVoteMessage_VerifyMismatchBetweenNTSVoteBasesAndNTSDProofParts(t *testi
 	_ = msg.Sign(w)
 	assert.Error(msg.Verify())
 }
+
+func TestSignedBase_signForRemoteSigner(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	w := wallet.New()
+	s, err := wallet.NewSigner(w, filepath.Join(dir, "state.json"))
+	assert.NoError(err)
+	addr := "unix://" + filepath.Join(dir, "signer.sock")
+	assert.NoError(s.Listen(addr, nil))
+	go s.Loop()
+	defer s.Close()
+	rw, err := wallet.OpenRemote(addr, nil)
+	assert.NoError(err)
+
+	psb := NewPartSetBuffer(10)
+	_, _ = psb.Write(make([]byte, 10))
+	ps := psb.PartSet()
+
+	pm := NewProposalMessage()
+	pm.Height = 1
+	pm.Round = 1
+	pm.BlockPartSetID = ps.ID()
+	pm.POLRound = -1
+	assert.NoError(pm.signFor(rw, pm.Height, pm.Round, module.SignStepProposal))
+	assert.NoError(pm.Verify())
+	assert.True(w.Address().Equal(pm.address()))
+
+	newVote := func(bid string, ts int64) *VoteMessage {
+		msg := newVoteMessage()
+		msg.Height = 1
+		msg.Round = 1
+		msg.Type = VoteTypePrecommit
+		msg.BlockID = []byte(bid)
+		msg.BlockPartSetIDAndNTSVoteCount = ps.ID().WithAppData(0)
+		msg.Timestamp = ts
+		return msg
+	}
+	vm := newVote("abc", 10)
+	assert.NoError(vm.signFor(rw, vm.Height, vm.Round, module.SignStepPrecommit))
+	assert.NoError(vm.Verify())
+	assert.True(w.Address().Equal(vm.address()))
+
+	// same decision with other timestamp is signed
+	vm = newVote("abc", 11)
+	assert.NoError(vm.signFor(rw, vm.Height, vm.Round, module.SignStepPrecommit))
+	assert.NoError(vm.Verify())
+	assert.True(w.Address().Equal(vm.address()))
+
+	// conflicting vote is refused
+	vm = newVote("def", 12)
+	assert.Error(vm.signFor(rw, vm.Height, vm.Round, module.SignStepPrecommit))
+
+	// decision of BTP is signed as payload
+	wp := &walletProvider{rw}
+	pc, err := ntm.ForUID("eth").NewProofContext([][]byte{w.PublicKey()})
+	assert.NoError(err)
+	ntsd := pc.NewDecision([]byte("src"), 1, 1, 1, []byte("nts"))
+	_, err = pc.NewProofPart(ntsd.Hash(), wp)
+	assert.Error(err)
+	pp, err := pc.NewProofPart(ntsd.Hash(), ntm.DecisionWalletProvider(wp, ntsd))
+	assert.NoError(err)
+	idx, err := pc.VerifyPart(ntsd.Hash(), pp)
+	assert.NoError(err)
+	assert.Equal(0, idx)
+}
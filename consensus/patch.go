@@ -4,6 +4,7 @@ import (
 	"bytes"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 )
@@ -69,6 +70,120 @@ func newSkipPatch(vl *VoteList) *skipPatch {
 	return &skipPatch{VoteList: *vl}
 }
 
+// doubleSignPatch is the evidence of double signing which has two
+// conflicting votes of a validator. Headers has the header of the block for
+// each vote, or nil for a nil vote. Nil votes are bound to the chain by NID
+// in BlockID, and votes for blocks are bound by NextValidatorsHash of the
+// headers.
+type doubleSignPatch struct {
+	VoteList VoteList
+	Headers  [][]byte
+}
+
+// blockHeaderPrefix is the leading fields of the header of the block.
+type blockHeaderPrefix struct {
+	Version            int
+	Height             int64
+	Timestamp          int64
+	Proposer           []byte
+	PrevID             []byte
+	VotesHash          []byte
+	NextValidatorsHash []byte
+}
+
+func (s *doubleSignPatch) Type() string {
+	return module.PatchTypeDoubleSign
+}
+
+func (s *doubleSignPatch) Data() []byte {
+	return codec.MustMarshalToBytes(s)
+}
+
+func (s *doubleSignPatch) Height() int64 {
+	if s.VoteList.Len() == 0 {
+		return -1
+	}
+	return s.VoteList.Get(0).Height
+}
+
+func (s *doubleSignPatch) Signer() module.Address {
+	if s.VoteList.Len() == 0 {
+		return nil
+	}
+	if addr := s.VoteList.Get(0).address(); addr != nil {
+		return addr
+	}
+	return nil
+}
+
+// isResolved returns true if it has the headers for votes for blocks.
+func (s *doubleSignPatch) isResolved() bool {
+	if len(s.Headers) != s.VoteList.Len() {
+		return false
+	}
+	for i, h := range s.Headers {
+		if h == nil && s.VoteList.Get(i).BlockPartSetIDAndNTSVoteCount != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *doubleSignPatch) verifyHeader(msg *VoteMessage, header []byte, nvHash []byte) error {
+	if !bytes.Equal(crypto.SHA3Sum256(header), msg.BlockID) {
+		return errors.Errorf("header mismatch for block %x", msg.BlockID)
+	}
+	var hp blockHeaderPrefix
+	if _, err := codec.UnmarshalFromBytes(header, &hp); err != nil {
+		return errors.Wrapf(err, "invalid header for block %x", msg.BlockID)
+	}
+	if hp.Height != msg.Height {
+		return errors.Errorf("bad height %d of block %x", hp.Height, msg.BlockID)
+	}
+	if nvHash != nil && !bytes.Equal(hp.NextValidatorsHash, nvHash) {
+		return errors.Errorf("block %x is not on the chain", msg.BlockID)
+	}
+	return nil
+}
+
+func (s *doubleSignPatch) Verify(nid int, vl module.ValidatorList, nvHash []byte) error {
+	if s.VoteList.Len() != 2 || len(s.Headers) != 2 {
+		return errors.Errorf("bad number of votes %d headers %d",
+			s.VoteList.Len(), len(s.Headers))
+	}
+	nidBytes := codec.MustMarshalToBytes(nid)
+	v1, v2 := s.VoteList.Get(0), s.VoteList.Get(1)
+	for i, msg := range []*VoteMessage{v1, v2} {
+		if err := msg.Verify(); err != nil {
+			return err
+		}
+		if msg.BlockPartSetIDAndNTSVoteCount == nil {
+			if !bytes.Equal(msg.BlockID, nidBytes) {
+				return errors.Errorf("bad nid %x for nil vote", msg.BlockID)
+			}
+		} else if err := s.verifyHeader(msg, s.Headers[i], nvHash); err != nil {
+			return err
+		}
+	}
+	if !v1.address().Equal(v2.address()) {
+		return errors.Errorf("different signers %v %v", v1.address(), v2.address())
+	}
+	if vl == nil || vl.IndexOf(v1.address()) < 0 {
+		return errors.Errorf("signer %v is not a validator at %d", v1.address(), v1.Height)
+	}
+	if !v1.conflictsWith(&v2.blockVoteBase) {
+		return errors.Errorf("not conflicting votes %v %v", v1, v2)
+	}
+	return nil
+}
+
+func newDoubleSignPatch(v1, v2 *VoteMessage) *doubleSignPatch {
+	vl := NewVoteList()
+	vl.AddVote(v1)
+	vl.AddVote(v2)
+	return &doubleSignPatch{VoteList: *vl}
+}
+
 func DecodePatch(t string, bs []byte) (module.Patch, error) {
 	var err error
 	var patch module.Patch
@@ -76,6 +191,9 @@ func DecodePatch(t string, bs []byte) (module.Patch, error) {
 	case module.PatchTypeSkipTransaction:
 		patch = &skipPatch{}
 		_, err = codec.UnmarshalFromBytes(bs, patch)
+	case module.PatchTypeDoubleSign:
+		patch = &doubleSignPatch{}
+		_, err = codec.UnmarshalFromBytes(bs, patch)
 	default:
 		err = errors.ErrUnsupported
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/module"
 )
@@ -209,3 +210,115 @@ func TestSkipPatch_Basics(t_ *testing.T) {
 	t.Assert.Equal(module.PatchTypeSkipTransaction, sp2.Type())
 	t.Assert.Equal(bs, sp2.Data())
 }
+
+func (t *skipPatchTest) newSignedVote3(w module.Wallet, vt VoteType, bid []byte, psid *PartSetIDAndAppData) *VoteMessage {
+	vm := newVoteMessage()
+	vm.Type = vt
+	vm.BlockID = bid
+	vm.BlockPartSetIDAndNTSVoteCount = psid
+	vm.Height = 5
+	vm.Round = 1
+	err := vm.Sign(w)
+	t.Assert.NoError(err)
+	return vm
+}
+
+type testBlockHeader struct {
+	Version            int
+	Height             int64
+	Timestamp          int64
+	Proposer           []byte
+	PrevID             []byte
+	VotesHash          []byte
+	NextValidatorsHash []byte
+}
+
+func newTestBlockHeader(height int64, ts int64, nvHash []byte) ([]byte, []byte) {
+	header := codec.MustMarshalToBytes(&testBlockHeader{
+		Version:            module.BlockVersion2,
+		Height:             height,
+		Timestamp:          ts,
+		NextValidatorsHash: nvHash,
+	})
+	return header, crypto.SHA3Sum256(header)
+}
+
+func TestDoubleSignPatch_Verify(t_ *testing.T) {
+	const nid = 7
+	t := newSkipPatchTest(t_)
+	w := []module.Wallet{wallet.New(), wallet.New()}
+	valList := validatorList{w[0].Address(), w[1].Address()}
+	nvHash := crypto.SHA3Sum256([]byte("validators6"))
+	h1, id1 := newTestBlockHeader(5, 1, nvHash)
+	h2, id2 := newTestBlockHeader(5, 2, nvHash)
+	nidBytes := codec.MustMarshalToBytes(int32(nid))
+	psid := &PartSetIDAndAppData{CountWord: 1, Hash: make([]byte, 32)}
+	v1 := t.newSignedVote3(w[0], VoteTypePrevote, id1, psid)
+
+	// nil vote and a vote for block
+	dp := newDoubleSignPatch(v1, t.newSignedVote3(w[0], VoteTypePrevote, nidBytes, nil))
+	t.Assert.Error(dp.Verify(nid, valList, nvHash))
+	dp.Headers = [][]byte{h1, nil}
+	t.Assert.NoError(dp.Verify(nid, valList, nvHash))
+	t.Assert.EqualValues(5, dp.Height())
+	t.Assert.True(w[0].Address().Equal(dp.Signer()))
+
+	// not a validator
+	t.Assert.Error(dp.Verify(nid, validatorList{w[1].Address()}, nvHash))
+
+	// votes for different blocks
+	dp = newDoubleSignPatch(v1, t.newSignedVote3(w[0], VoteTypePrevote, id2, psid))
+	dp.Headers = [][]byte{h1, h2}
+	t.Assert.NoError(dp.Verify(nid, valList, nvHash))
+
+	// blocks of other chain
+	t.Assert.Error(dp.Verify(nid, valList, crypto.SHA3Sum256([]byte("other"))))
+
+	// binding to the chain is not checked without the hash
+	t.Assert.NoError(dp.Verify(nid, valList, nil))
+
+	// header of other block
+	dp.Headers = [][]byte{h1, h1}
+	t.Assert.Error(dp.Verify(nid, valList, nvHash))
+
+	// header of other height
+	h3, id3 := newTestBlockHeader(6, 2, nvHash)
+	dp = newDoubleSignPatch(v1, t.newSignedVote3(w[0], VoteTypePrevote, id3, psid))
+	dp.Headers = [][]byte{h1, h3}
+	t.Assert.Error(dp.Verify(nid, valList, nvHash))
+
+	// different signers
+	dp = newDoubleSignPatch(v1, t.newSignedVote3(w[1], VoteTypePrevote, id2, psid))
+	dp.Headers = [][]byte{h1, h2}
+	t.Assert.Error(dp.Verify(nid, valList, nvHash))
+
+	// different vote types
+	dp = newDoubleSignPatch(v1, t.newSignedVote3(w[0], VoteTypePrecommit, id2, psid))
+	dp.Headers = [][]byte{h1, h2}
+	t.Assert.Error(dp.Verify(nid, valList, nvHash))
+
+	// same decision with different timestamp
+	v2 := t.newSignedVote3(w[0], VoteTypePrevote, id1, psid)
+	v2.Timestamp = 1
+	t.Assert.NoError(v2.Sign(w[0]))
+	dp = newDoubleSignPatch(v1, v2)
+	dp.Headers = [][]byte{h1, h1}
+	t.Assert.Error(dp.Verify(nid, valList, nvHash))
+
+	// nil vote for other network
+	dp = newDoubleSignPatch(v1, t.newSignedVote3(w[0], VoteTypePrevote, codec.MustMarshalToBytes(int32(nid+1)), nil))
+	dp.Headers = [][]byte{h1, nil}
+	t.Assert.Error(dp.Verify(nid, valList, nvHash))
+
+	// decode
+	dp = newDoubleSignPatch(v1, t.newSignedVote3(w[0], VoteTypePrevote, id2, psid))
+	dp.Headers = [][]byte{h1, h2}
+	p, err := DecodePatch(module.PatchTypeDoubleSign, dp.Data())
+	t.Assert.NoError(err)
+	dp2, ok := p.(module.DoubleSignPatch)
+	t.Assert.True(ok)
+	t.Assert.NoError(dp2.Verify(nid, valList, nvHash))
+	t.Assert.Equal(module.PatchTypeDoubleSign, dp2.Type())
+	t.Assert.True(w[0].Address().Equal(dp2.Signer()))
+	t.Assert.Equal(dp.Data(), dp2.Data())
+}
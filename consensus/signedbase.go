@@ -55,9 +55,25 @@ func (s *signedBase) verify() error {
 }
 
 func (s *signedBase) Sign(wallet module.Wallet) error {
+	return s.sign(wallet.Sign)
+}
+
+// signFor signs with VoteSigner for the height, round and step if the
+// wallet implements it.
+func (s *signedBase) signFor(wallet module.Wallet, height int64, round int32, step module.SignStep) error {
+	if vs, ok := wallet.(module.VoteSigner); ok {
+		bs := s._byteser.bytes()
+		return s.sign(func(data []byte) ([]byte, error) {
+			return vs.SignVote(bs, height, round, step)
+		})
+	}
+	return s.Sign(wallet)
+}
+
+func (s *signedBase) sign(signer func(data []byte) ([]byte, error)) error {
 	s._hash = nil
 	s._publicKey = nil
-	sigBS, err := wallet.Sign(s.hash())
+	sigBS, err := signer(s.hash())
 	if err != nil {
 		return errors.Errorf("sendVote : %v", err)
 	}
@@ -323,6 +323,7 @@ func (s *syncer) OnReceive(sp module.ProtocolInfo, bs []byte,
 	case *BlockPartMessage:
 		idx, err = s.engine.ReceiveBlockPartMessage(m, true)
 		if idx < 0 && err != nil {
+			reportOffence(s.ph, id, err)
 			return false, err
 		}
 		for _, p := range s.peers {
@@ -341,6 +342,7 @@ func (s *syncer) OnReceive(sp module.ProtocolInfo, bs []byte,
 	case *VoteListMessage:
 		err = s.engine.ReceiveVoteListMessage(m, true)
 		if err != nil {
+			reportOffence(s.ph, id, err)
 			return false, err
 		}
 		rs := s.engine.GetRoundState()
@@ -30,9 +30,10 @@ type record struct {
 }
 
 type testWAL struct {
-	round  []*record
-	lock   []*record
-	commit []*record
+	round    []*record
+	lock     []*record
+	commit   []*record
+	evidence []*record
 }
 
 func NewTestWAL() *testWAL {
@@ -48,6 +49,8 @@ func (w *testWAL) data(id string) *[]*record {
 		return &w.lock
 	case "commit":
 		return &w.commit
+	case "evidence":
+		return &w.evidence
 	default:
 		log.Panicf("invalid wal id %s", id)
 		return nil
@@ -109,6 +109,15 @@ func (v *voteBase) Equal(v2 *voteBase) bool {
 		bytes.Equal(v.RoundDecisionDigest(), v2.RoundDecisionDigest())
 }
 
+// conflictsWith returns true if v and v2 are for the same height, round and
+// type, but signed for different decisions.
+func (v *blockVoteBase) conflictsWith(v2 *blockVoteBase) bool {
+	return v.Height == v2.Height &&
+		v.Round == v2.Round &&
+		v.Type == v2.Type &&
+		!bytes.Equal(msgCodec.MustMarshalToBytes(v), msgCodec.MustMarshalToBytes(v2))
+}
+
 func (v voteBase) String() string {
 	if len(v.NTSVoteBases) == 0 {
 		return fmt.Sprintf(
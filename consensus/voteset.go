@@ -235,6 +235,19 @@ func (hvs *heightVoteSet) votesFor(round int32, voteType VoteType) *voteSet {
 	return vs
 }
 
+// conflictingVote returns the vote of the validator at index which conflicts
+// with v. It returns nil if there is no such vote.
+func (hvs *heightVoteSet) conflictingVote(index int, v *VoteMessage) *VoteMessage {
+	vs := hvs._votes[v.Round][v.Type]
+	if vs == nil {
+		return nil
+	}
+	if omsg := vs.msgs[index]; omsg != nil && omsg.conflictsWith(&v.blockVoteBase) {
+		return omsg
+	}
+	return nil
+}
+
 func (hvs *heightVoteSet) reset(nValidators int) {
 	hvs._nValidators = nValidators
 	hvs._votes = make(map[int32][numberOfVoteTypes]*voteSet)
@@ -62,6 +62,22 @@ func TestVoteSet_Add_ConflictingVoteBeforeConsensus(t *testing.T) {
 	assert.True(ok)
 }
 
+func TestHeightVoteSet_conflictingVote(t *testing.T) {
+	assert := assert.New(t)
+	var hvs heightVoteSet
+	hvs.reset(4)
+	vm := newVoteMsgWithHashPrefix(0)
+	assert.Nil(hvs.conflictingVote(0, vm))
+	hvs.add(0, vm)
+	assert.Nil(hvs.conflictingVote(0, newVoteMsgWithHashPrefix(0)))
+	assert.Nil(hvs.conflictingVote(1, newVoteMsgWithHashPrefix(1)))
+	assert.Equal(vm, hvs.conflictingVote(0, newVoteMsgWithHashPrefix(1)))
+
+	vm2 := newVoteMsgWithHashPrefix(1)
+	vm2.Type = VoteTypePrecommit
+	assert.Nil(hvs.conflictingVote(0, vm2))
+}
+
 func TestVoteSet_voteListForOverTwoThirds(t *testing.T) {
 	assert := assert.New(t)
 	vs := newVoteSet(4)
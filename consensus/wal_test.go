@@ -74,3 +74,52 @@ func TestWAL(t *testing.T) {
 	err = wr.Close()
 	assert.NoError(t, err)
 }
+
+func TestTruncateWAL(t *testing.T) {
+	base := t.TempDir()
+	id := base + "/testwal"
+	ww, err := consensus.OpenWALForWrite(id, &consensus.WALConfig{})
+	assert.NoError(t, err)
+	for i := 0; i < 30; i++ {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(i))
+		_, err = ww.WriteBytes(buf[:])
+		assert.NoError(t, err)
+		if i%10 == 9 {
+			assert.NoError(t, ww.(interface{ Shift() error }).Shift())
+		}
+	}
+	assert.NoError(t, ww.Close())
+
+	recs, err := consensus.ReadWALRecords(id)
+	assert.NoError(t, err)
+	assert.Len(t, recs, 30)
+	assert.EqualValues(t, 12*15, recs[15].Offset)
+	assert.NotEmpty(t, recs[15].Error)
+
+	assert.Error(t, consensus.TruncateWAL(id, 31))
+	assert.NoError(t, consensus.TruncateWAL(id, 15))
+
+	recs, err = consensus.ReadWALRecords(id)
+	assert.NoError(t, err)
+	assert.Len(t, recs, 15)
+
+	// new records are appended after the truncated one
+	ww, err = consensus.OpenWALForWrite(id, &consensus.WALConfig{})
+	assert.NoError(t, err)
+	_, err = ww.WriteBytes([]byte{0, 0, 0, 99})
+	assert.NoError(t, err)
+	assert.NoError(t, ww.Close())
+
+	wr, err := consensus.OpenWALForRead(id)
+	assert.NoError(t, err)
+	for i := 0; i < 15; i++ {
+		bs, err := wr.ReadBytes()
+		assert.NoError(t, err)
+		assert.EqualValues(t, i, binary.BigEndian.Uint32(bs))
+	}
+	bs, err := wr.ReadBytes()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 99, binary.BigEndian.Uint32(bs))
+	assert.NoError(t, wr.Close())
+}
@@ -0,0 +1,262 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"path"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+// WALIDs returns the ids of WALs used by consensus in a WAL directory.
+func WALIDs() []string {
+	return []string{
+		configRoundWALID,
+		configLockWALID,
+		configCommitWALID,
+		configEvidenceWALID,
+	}
+}
+
+// WALRecord is a record of WAL decoded for inspection.
+type WALRecord struct {
+	Index   int         `json:"index"`
+	Offset  int64       `json:"offset"`
+	Size    int         `json:"size"`
+	Type    string      `json:"type"`
+	Message interface{} `json:"message,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+type walProposalJSON struct {
+	Height         int64           `json:"height"`
+	Round          int32           `json:"round"`
+	BlockPartSetID string          `json:"blockPartSetID"`
+	POLRound       int32           `json:"polRound"`
+	Signer         *common.Address `json:"signer"`
+}
+
+type walVoteJSON struct {
+	Height         int64           `json:"height"`
+	Round          int32           `json:"round"`
+	Type           string          `json:"type"`
+	BlockID        common.HexBytes `json:"blockID"`
+	BlockPartSetID string          `json:"blockPartSetID,omitempty"`
+	NTSVoteCount   int             `json:"ntsVoteCount"`
+	Timestamp      int64           `json:"timestamp"`
+	Signer         *common.Address `json:"signer"`
+}
+
+type walBlockPartJSON struct {
+	Height int64  `json:"height"`
+	Index  uint16 `json:"index"`
+	Size   int    `json:"size"`
+}
+
+func partSetIDString(psid *PartSetID) string {
+	if psid == nil {
+		return ""
+	}
+	return psid.String()
+}
+
+func walVoteJSONOf(v *VoteMessage) *walVoteJSON {
+	return &walVoteJSON{
+		Height:         v.Height,
+		Round:          v.Round,
+		Type:           v.Type.String(),
+		BlockID:        v.BlockID,
+		BlockPartSetID: partSetIDString(v.BlockPartSetIDAndNTSVoteCount.ID()),
+		NTSVoteCount:   len(v.NTSVoteBases),
+		Timestamp:      v.Timestamp,
+		Signer:         v.address(),
+	}
+}
+
+func walJSONOf(msg Message) (string, interface{}) {
+	switch m := msg.(type) {
+	case *ProposalMessage:
+		return "proposal", &walProposalJSON{
+			Height:         m.Height,
+			Round:          m.Round,
+			BlockPartSetID: partSetIDString(m.BlockPartSetID),
+			POLRound:       m.POLRound,
+			Signer:         m.address(),
+		}
+	case *VoteMessage:
+		return "vote", walVoteJSONOf(m)
+	case *VoteListMessage:
+		votes := make([]*walVoteJSON, 0, m.VoteList.Len())
+		for i := 0; i < m.VoteList.Len(); i++ {
+			votes = append(votes, walVoteJSONOf(m.VoteList.Get(i)))
+		}
+		return "voteList", votes
+	case *BlockPartMessage:
+		return "blockPart", &walBlockPartJSON{
+			Height: m.Height,
+			Index:  m.Index,
+			Size:   len(m.BlockPart),
+		}
+	default:
+		return fmt.Sprintf("%T", msg), msg
+	}
+}
+
+func decodeWALMessage(bs []byte) (Message, error) {
+	if len(bs) < 2 {
+		return nil, errors.Errorf("too short wal message len=%v", len(bs))
+	}
+	sp := binary.BigEndian.Uint16(bs[0:2])
+	msg, err := UnmarshalMessage(sp, bs[2:])
+	if err != nil {
+		return nil, err
+	}
+	if err = msg.Verify(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ReadWALRecords reads the records of the WAL. It returns the records read
+// so far with the error if the WAL is corrupted or truncated unexpectedly.
+// Records which cannot be decoded as a message have the error instead.
+func ReadWALRecords(id string) ([]*WALRecord, error) {
+	wr, err := OpenWALForRead(id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		log.Must(wr.Close())
+	}()
+	r := wr.(*walReader)
+	var records []*WALRecord
+	for {
+		offset := r.validOffset
+		bs, err := wr.ReadBytes()
+		if IsEOF(err) {
+			return records, nil
+		} else if err != nil {
+			return records, err
+		}
+		rec := &WALRecord{
+			Index:  len(records),
+			Offset: offset,
+			Size:   len(bs),
+		}
+		if msg, err := decodeWALMessage(bs); err != nil {
+			rec.Type = "unknown"
+			rec.Error = err.Error()
+		} else {
+			rec.Type, rec.Message = walJSONOf(msg)
+		}
+		records = append(records, rec)
+	}
+}
+
+// TruncateWAL truncates the WAL keeping the first n records. It fails if
+// the WAL has less than n valid records.
+func TruncateWAL(id string, n int) error {
+	if n < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidRecordCount(n=%d)", n)
+	}
+	wr, err := OpenWALForRead(id)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if _, err := wr.ReadBytes(); err != nil {
+			log.Must(wr.Close())
+			return errors.Wrapf(err, "fail to read record index=%d", i)
+		}
+	}
+	return wr.CloseAndRepair()
+}
+
+// WALLock is the last lock recorded in the lock WAL.
+type WALLock struct {
+	Height         int64           `json:"height"`
+	Round          int32           `json:"round"`
+	BlockID        common.HexBytes `json:"blockID"`
+	BlockPartSetID string          `json:"blockPartSetID"`
+	Votes          int             `json:"votes"`
+	Parts          int             `json:"parts"`
+	Complete       bool            `json:"complete"`
+}
+
+// ReadWALLock returns the last lock in the lock WAL of the WAL directory.
+// It returns nil if there is no lock. Block of the lock can be restored
+// only if it's complete.
+func ReadWALLock(dir string) (*WALLock, error) {
+	wr, err := OpenWALForRead(path.Join(dir, configLockWALID))
+	if err != nil {
+		if IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		log.Must(wr.Close())
+	}()
+	var lock *WALLock
+	var ps PartSet
+	for {
+		bs, err := wr.ReadBytes()
+		if IsEOF(err) || IsCorruptedWAL(err) || IsUnexpectedEOF(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		msg, err := decodeWALMessage(bs)
+		if err != nil {
+			return nil, err
+		}
+		switch m := msg.(type) {
+		case *VoteListMessage:
+			if m.VoteList.Len() == 0 {
+				continue
+			}
+			// votes for the block having most prevotes
+			counts := make(map[string]int)
+			var best *VoteMessage
+			for i := 0; i < m.VoteList.Len(); i++ {
+				v := m.VoteList.Get(i)
+				psid := v.BlockPartSetIDAndNTSVoteCount.ID()
+				if psid == nil {
+					continue
+				}
+				key := psid.String()
+				counts[key]++
+				if best == nil || counts[key] > counts[best.BlockPartSetIDAndNTSVoteCount.ID().String()] {
+					best = v
+				}
+			}
+			if best == nil {
+				continue
+			}
+			psid := best.BlockPartSetIDAndNTSVoteCount.ID()
+			lock = &WALLock{
+				Height:         best.Height,
+				Round:          best.Round,
+				BlockID:        best.BlockID,
+				BlockPartSetID: psid.String(),
+				Votes:          counts[psid.String()],
+			}
+			ps = NewPartSetFromID(psid)
+		case *BlockPartMessage:
+			if lock == nil || m.Height != lock.Height {
+				continue
+			}
+			bp, err := NewPart(m.BlockPart)
+			if err != nil {
+				return nil, err
+			}
+			if err := ps.AddPart(bp); err == nil {
+				lock.Parts++
+				lock.Complete = ps.IsComplete()
+			}
+		}
+	}
+	return lock, nil
+}
@@ -0,0 +1,151 @@
+package consensus
+
+import (
+	"strings"
+
+	"github.com/icon-project/goloop/module"
+)
+
+type eventWatcher struct {
+	ch chan *module.ConsensusEvent
+}
+
+func (cs *consensus) WatchEvents(size int) (<-chan *module.ConsensusEvent, func()) {
+	ch := make(chan *module.ConsensusEvent, size)
+	return ch, cs.addWatcher(ch)
+}
+
+func (cs *consensus) addWatcher(ch chan *module.ConsensusEvent) func() {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	w := &eventWatcher{ch: ch}
+	cs.watchers = append(cs.watchers, w)
+	return func() {
+		cs.mutex.Lock()
+		defer cs.mutex.Unlock()
+
+		cs.removeWatcher(w)
+	}
+}
+
+// AddEventWatcher makes the consensus notify its events to ch, and returns
+// the function to stop it. It's for the watchers registered before the
+// consensus is created, and ch is closed in the same way as the channel
+// returned by WatchEvents. It returns false if cs doesn't notify events.
+func AddEventWatcher(cs module.Consensus, ch chan *module.ConsensusEvent) (func(), bool) {
+	if impl, ok := cs.(*consensus); ok {
+		return impl.addWatcher(ch), true
+	}
+	return nil, false
+}
+
+func (cs *consensus) removeWatcher(w *eventWatcher) {
+	for i, w2 := range cs.watchers {
+		if w2 == w {
+			last := len(cs.watchers) - 1
+			cs.watchers[i] = cs.watchers[last]
+			cs.watchers[last] = nil
+			cs.watchers = cs.watchers[:last]
+			close(w.ch)
+			return
+		}
+	}
+}
+
+func (cs *consensus) removeAllWatchers() {
+	for _, w := range cs.watchers {
+		close(w.ch)
+	}
+	cs.watchers = nil
+}
+
+// notifyEvent sends the event to the watchers without blocking. Watchers
+// too slow to receive it are removed.
+func (cs *consensus) notifyEvent(ev *module.ConsensusEvent) {
+	var slow []*eventWatcher
+	for _, w := range cs.watchers {
+		select {
+		case w.ch <- ev:
+		default:
+			slow = append(slow, w)
+		}
+	}
+	for _, w := range slow {
+		cs.log.Infof("remove slow consensus event watcher")
+		cs.removeWatcher(w)
+	}
+}
+
+func stepName(s step) string {
+	return strings.TrimPrefix(s.String(), "step")
+}
+
+func (cs *consensus) notifyStep() {
+	if len(cs.watchers) == 0 {
+		return
+	}
+	ev := &module.ConsensusEvent{
+		Type:   module.ConsensusEventStep,
+		Height: cs.height,
+		Round:  cs.round,
+		Step:   stepName(cs.step),
+	}
+	if cs.step == stepNewHeight && cs.validators != nil {
+		ev.Validators = make([]module.Address, 0, cs.validators.Len())
+		for i := 0; i < cs.validators.Len(); i++ {
+			if v, ok := cs.validators.Get(i); ok {
+				ev.Validators = append(ev.Validators, v.Address())
+			}
+		}
+	}
+	cs.notifyEvent(ev)
+}
+
+func (cs *consensus) notifyProposal(msg *ProposalMessage) {
+	if len(cs.watchers) == 0 {
+		return
+	}
+	ev := &module.ConsensusEvent{
+		Type:     module.ConsensusEventProposal,
+		Height:   msg.Height,
+		Round:    msg.Round,
+		Step:     stepName(cs.step),
+		Proposer: msg.address(),
+	}
+	if msg.BlockPartSetID != nil {
+		ev.BlockPartSetHash = msg.BlockPartSetID.Hash
+	}
+	cs.notifyEvent(ev)
+}
+
+func (cs *consensus) notifyVote(msg *VoteMessage, votes *voteSet) {
+	if len(cs.watchers) == 0 {
+		return
+	}
+	voted := make([]bool, votes.mask.Len())
+	for i := range voted {
+		voted[i] = votes.mask.Get(i)
+	}
+	cs.notifyEvent(&module.ConsensusEvent{
+		Type:     module.ConsensusEventVote,
+		Height:   msg.Height,
+		Round:    msg.Round,
+		Step:     stepName(cs.step),
+		VoteType: msg.Type.String(),
+		Votes:    votes.count,
+		Voted:    voted,
+	})
+}
+
+func (cs *consensus) notifyTimeout() {
+	if len(cs.watchers) == 0 {
+		return
+	}
+	cs.notifyEvent(&module.ConsensusEvent{
+		Type:   module.ConsensusEventTimeout,
+		Height: cs.height,
+		Round:  cs.round,
+		Step:   stepName(cs.step),
+	})
+}
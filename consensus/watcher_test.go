@@ -0,0 +1,43 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestConsensus_WatchEvents(t *testing.T) {
+	cs := &consensus{log: log.New()}
+	ch1, _ := cs.WatchEvents(1)
+	ch2, stop2 := cs.WatchEvents(3)
+
+	cs.height = 3
+	cs.step = stepPrevote
+	cs.notifyTimeout()
+	ev := <-ch1
+	assert.Equal(t, &module.ConsensusEvent{
+		Type:   module.ConsensusEventTimeout,
+		Height: 3,
+		Step:   "Prevote",
+	}, ev)
+
+	// slow watcher is removed
+	cs.notifyTimeout()
+	cs.notifyTimeout()
+	assert.Len(t, cs.watchers, 1)
+	<-ch1
+	_, ok := <-ch1
+	assert.False(t, ok)
+
+	// stop closes the channel
+	for i := 0; i < 3; i++ {
+		<-ch2
+	}
+	stop2()
+	_, ok = <-ch2
+	assert.False(t, ok)
+	assert.Len(t, cs.watchers, 0)
+}
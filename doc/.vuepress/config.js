@@ -35,6 +35,7 @@ module.exports = {
                     '/goloop_admin_api',
                     ['/goloop_cli', "Goloop CLI"],
                     ['/metric', "Metric"],
+                    ['/consensus_monitor', "Consensus Monitor"],
                 ]
             },
             //EndOfSidebar
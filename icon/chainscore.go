@@ -644,6 +644,14 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, icmodule.RevisionICON2R3, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setDoubleSignSlashingRate",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"slashingRate", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionDoubleSignPenalty, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "setUseSystemDeposit",
 		scoreapi.FlagExternal, 2,
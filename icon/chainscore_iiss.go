@@ -805,6 +805,39 @@ func (s *chainScore) Ex_setNonVoteSlashingRate(slashingRate *common.HexInt) erro
 	return nil
 }
 
+func (s *chainScore) Ex_setDoubleSignSlashingRate(slashingRate *common.HexInt) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	if !slashingRate.IsInt64() {
+		return icmodule.IllegalArgumentError.Errorf("Invalid range")
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	if err = es.State.SetDoubleSignPenaltySlashRatio(int(slashingRate.Int64())); err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return icmodule.IllegalArgumentError.Errorf("Invalid range")
+		}
+		return err
+	}
+	s.onSlashingRateChangedEvent("DoubleSignPenalty", slashingRate.Int64())
+	return nil
+}
+
+// HandleDoubleSign implements contract.DoubleSignHandler
+func (s *chainScore) HandleDoubleSign(signer module.Address, height int64) error {
+	if s.cc.Revision().Value() < icmodule.RevisionDoubleSignPenalty {
+		return nil
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.HandleDoubleSign(s.newCallContext(s.cc), signer, height)
+}
+
 func (s *chainScore) onSlashingRateChangedEvent(name string, rate int64) {
 	s.cc.OnEvent(state.SystemAddress,
 		[][]byte{[]byte("SlashingRateChanged(str,int)"), []byte(name)},
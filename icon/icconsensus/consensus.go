@@ -38,6 +38,14 @@ type wrapper struct {
 	timestamper  module.Timestamper
 	merkleHeader *hexary.MerkleHeader
 	lastVoteData *consensus.LastVoteData
+
+	// watchers registered before the consensus starts
+	watchers []*eventWatcher
+}
+
+type eventWatcher struct {
+	ch   chan *module.ConsensusEvent
+	stop func()
 }
 
 func New(
@@ -85,7 +93,11 @@ func (c *wrapper) Start() error {
 			c.c, c.walDir, c.wm, c.timestamper, bpp, c.lastVoteData,
 		)
 	}
-	return c.Consensus.Start()
+	if err := c.Consensus.Start(); err != nil {
+		return err
+	}
+	c.attachWatchers()
+	return nil
 }
 
 func (c *wrapper) GetStatus() *module.ConsensusStatus {
@@ -127,6 +139,7 @@ func (c *wrapper) Upgrade(bpp *bpp) {
 	if err != nil {
 		c.c.Logger().Panicf("fail to start consensus %+v", err)
 	}
+	c.attachWatchers()
 }
 
 func (c *wrapper) Term() {
@@ -136,4 +149,56 @@ func (c *wrapper) Term() {
 	if c.Consensus != nil {
 		c.Consensus.Term()
 	}
+	for _, w := range c.watchers {
+		close(w.ch)
+	}
+	c.watchers = nil
+}
+
+// WatchEvents returns the channel receiving the events of the consensus.
+// Watchers registered while importing or fast syncing are kept, and they
+// start receiving events when the consensus starts.
+func (c *wrapper) WatchEvents(size int) (<-chan *module.ConsensusEvent, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if w, ok := c.Consensus.(module.ConsensusEventWatcher); ok {
+		return w.WatchEvents(size)
+	}
+	w := &eventWatcher{ch: make(chan *module.ConsensusEvent, size)}
+	c.watchers = append(c.watchers, w)
+	return w.ch, func() {
+		c.stopWatcher(w)
+	}
+}
+
+func (c *wrapper) stopWatcher(w *eventWatcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if w.stop != nil {
+		w.stop()
+		return
+	}
+	for i, w2 := range c.watchers {
+		if w2 == w {
+			c.watchers = append(c.watchers[:i], c.watchers[i+1:]...)
+			close(w.ch)
+			return
+		}
+	}
+}
+
+// attachWatchers passes the watchers to the consensus if it notifies
+// events.
+func (c *wrapper) attachWatchers() {
+	var watchers []*eventWatcher
+	for _, w := range c.watchers {
+		if stop, ok := consensus.AddEventWatcher(c.Consensus, w.ch); ok {
+			w.stop = stop
+		} else {
+			watchers = append(watchers, w)
+		}
+	}
+	c.watchers = watchers
 }
@@ -18,6 +18,7 @@ package icconsensus_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -125,3 +126,43 @@ func TestConsensus_UpgradeWithAccumulator(t *testing.T) {
 	assert.EqualValues(t, 10, blk.Height())
 	assert.EqualValues(t, 11, f.CS.GetStatus().Height)
 }
+
+func TestConsensus_WatchEventsWhileFastSync(t *testing.T) {
+	gen := test.NewNode(t, ictest.UseBMForBlockV1, ictest.UseCSForBlockV1)
+	defer gen.Close()
+
+	const height = 10
+	for i := 1; i < height; i++ {
+		gen.ProposeFinalizeBlock((*blockv0.BlockVoteList)(nil))
+	}
+	header := ictest.NodeFinalizeMerkle(gen)
+
+	gen = test.NewNode(
+		t, ictest.UseBMForBlockV1, ictest.UseCSForBlockV1,
+		ictest.UseMerkle(header, nil), test.UseDB(gen.Chain.Database()),
+	)
+	defer gen.Close()
+
+	f := test.NewNode(
+		t, ictest.UseBMForBlockV1, ictest.UseCSForBlockV1,
+		ictest.UseMerkle(header, nil),
+	)
+	defer f.Close()
+
+	cw, ok := f.CS.(module.ConsensusEventWatcher)
+	assert.True(t, ok)
+	evch, stop := cw.WatchEvents(1000)
+	defer stop()
+
+	assert.NoError(t, gen.CS.Start())
+	assert.NoError(t, f.CS.Start())
+	f.NM.Connect(gen.NM)
+
+	select {
+	case ev, ok := <-evch:
+		assert.True(t, ok)
+		assert.True(t, ev.Height >= height)
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "no event after fast sync")
+	}
+}
@@ -24,4 +24,5 @@ const (
 	PenaltyLowProductivity
 	PenaltyBlockValidation
 	PenaltyNonVote
+	PenaltyDoubleSign
 )
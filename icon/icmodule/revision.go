@@ -40,6 +40,7 @@ const (
 	Revision19
 	Revision20
 	Revision21
+	Revision22
 	RevisionReserved
 )
 
@@ -105,6 +106,8 @@ const (
 	// RevisionJavaFixMapValues = Revision20
 
 	RevisionBTP2 = Revision21
+
+	RevisionDoubleSignPenalty = Revision22
 )
 
 var revisionFlags = []module.Revision{
@@ -152,6 +155,8 @@ var revisionFlags = []module.Revision{
 	module.FixMapValues,
 	// Revision21
 	module.MultipleFeePayers,
+	// Revision22
+	module.DoubleSignEvidence,
 }
 
 func init() {
@@ -51,6 +51,7 @@ const (
 	VarDelegationSlotMax                     = "delegation_slot_max"
 	DictNetworkScores                        = "network_scores"
 	VarNonVotePenaltySlashRatio              = "nonvote_penalty_slashRatio"
+	VarDoubleSignPenaltySlashRatio           = "double_sign_penalty_slashRatio"
 )
 
 const (
@@ -355,6 +356,17 @@ func (s *State) SetNonVotePenaltySlashRatio(value int) error {
 	return setValue(s.store, VarNonVotePenaltySlashRatio, value)
 }
 
+func (s *State) GetDoubleSignPenaltySlashRatio() int {
+	return int(getValue(s.store, VarDoubleSignPenaltySlashRatio).Int64())
+}
+
+func (s *State) SetDoubleSignPenaltySlashRatio(value int) error {
+	if value < 0 || value > 100 {
+		return errors.IllegalArgumentError.New("Invalid range")
+	}
+	return setValue(s.store, VarDoubleSignPenaltySlashRatio, value)
+}
+
 func (s *State) GetNetworkInfoInJSON() (map[string]interface{}, error) {
 	br := s.GetBondRequirement()
 	jso := make(map[string]interface{})
@@ -379,6 +391,7 @@ func (s *State) GetNetworkInfoInJSON() (map[string]interface{}, error) {
 	jso["unstakeSlotMax"] = s.GetUnstakeSlotMax()
 	jso["delegationSlotMax"] = s.GetDelegationSlotMax()
 	jso["proposalNonVotePenaltySlashRatio"] = s.GetNonVotePenaltySlashRatio()
+	jso["doubleSignPenaltySlashRatio"] = s.GetDoubleSignPenaltySlashRatio()
 
 	preps := s.GetPRepSet(nil, 0)
 	if preps != nil {
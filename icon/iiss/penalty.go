@@ -70,6 +70,35 @@ func (es *ExtensionStateImpl) handlePenalty(cc icmodule.CallContext, owner modul
 	return es.addEventEnable(blockHeight, owner, icstage.ESDisableTemp)
 }
 
+// HandleDoubleSign imposes the penalty for double signing at the height on
+// the P-Rep owning the node, and slashes its bonds.
+func (es *ExtensionStateImpl) HandleDoubleSign(cc icmodule.CallContext, node module.Address, height int64) error {
+	owner := es.State.GetOwnerByNode(node)
+	ps := es.State.GetPRepStatusByOwner(owner, false)
+	if ps == nil {
+		return nil
+	}
+	// The mapping between owners and nodes isn't kept for each height, so
+	// it skips the slash if the node of the owner is changed since the height.
+	if cur := es.State.GetNodeByOwner(owner); cur == nil || !cur.Equal(node) {
+		cc.FrameLogger().TSystemf(
+			"IISS double sign skipped owner=%s node=%s current=%s height=%d",
+			owner, node, cur, height)
+		return nil
+	}
+	cc.FrameLogger().TSystemf("IISS double sign owner=%s node=%s height=%d", owner, node, height)
+
+	// Record PenaltyImposed eventlog
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte("PenaltyImposed(Address,int,int)"), owner.Bytes()},
+		[][]byte{
+			intconv.Int64ToBytes(int64(ps.Status())),
+			intconv.Int64ToBytes(int64(icmodule.PenaltyDoubleSign)),
+		},
+	)
+	return es.slash(cc, owner, es.State.GetDoubleSignPenaltySlashRatio())
+}
+
 func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Address, ratio int) error {
 	if ratio < 0 || 100 < ratio {
 		return errors.Errorf("Invalid slash ratio %d", ratio)
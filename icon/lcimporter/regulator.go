@@ -49,6 +49,14 @@ func (r *regulatorImpl) SetBlockInterval(i time.Duration, d time.Duration) {
 	// do nothing
 }
 
+func (r *regulatorImpl) RoundTimeouts() module.RoundTimeouts {
+	panic("not implemented")
+}
+
+func (r *regulatorImpl) SetRoundTimeouts(t module.RoundTimeouts) {
+	// do nothing
+}
+
 func NewRegulator() module.Regulator {
 	return &regulatorImpl{}
 }
@@ -274,6 +274,10 @@ func (sm *ServiceManager) ExportResult(result []byte, vh []byte, dst db.Database
 	return errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) ExportState(result []byte, vh []byte, dst db.Database) error {
+	return errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) ImportResult(result []byte, vh []byte, src db.Database) error {
 	return errors.ErrInvalidState
 }
@@ -115,6 +115,12 @@ public class Governance {
         system.setRoundLimitFactor(factor);
     }
 
+    @External
+    public void setRoundTimeouts(int propose, int prevote, int precommit,
+                                 int newRound, int delta, int thresholdFactor) {
+        system.setRoundTimeouts(propose, prevote, precommit, newRound, delta, thresholdFactor);
+    }
+
     @External
     public void setUseSystemDeposit(Address address, boolean yn) {
         system.setUseSystemDeposit(address, yn);
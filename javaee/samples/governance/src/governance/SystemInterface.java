@@ -98,6 +98,12 @@ class SystemInterface {
         Context.call(CHAIN_SCORE, "setRoundLimitFactor", factor);
     }
 
+    void setRoundTimeouts(int propose, int prevote, int precommit,
+                          int newRound, int delta, int thresholdFactor) {
+        Context.call(CHAIN_SCORE, "setRoundTimeouts",
+                propose, prevote, precommit, newRound, delta, thresholdFactor);
+    }
+
     void setUseSystemDeposit(Address address, boolean yn) {
         Context.call(CHAIN_SCORE, "setUseSystemDeposit", address, yn);
     }
@@ -20,6 +20,51 @@ type Wallet interface {
 	Address() Address
 }
 
+// SignStep is the step of the round signed by VoteSigner. Steps in a round
+// are ordered by their values.
+type SignStep int
+
+const (
+	SignStepProposal SignStep = iota + 1
+	SignStepPrevote
+	SignStepPrecommit
+)
+
+// VoteSigner is optionally implemented by the wallet which refuses to sign
+// conflicting messages for the same height, round and step. Consensus uses
+// it for signing its own proposals and votes if it's available. data is the
+// bytes of the message, and the signature is for the SHA3-256 hash of it.
+type VoteSigner interface {
+	SignVote(data []byte, height int64, round int32, step SignStep) ([]byte, error)
+}
+
+// SignType is the type of the payload signed by PayloadSigner.
+type SignType string
+
+const (
+	// SignTypeAuth is for the secret of the peer authentication.
+	SignTypeAuth SignType = "auth"
+
+	// SignTypeTransaction is for the serialized transaction.
+	SignTypeTransaction SignType = "transaction"
+
+	// SignTypeBTPDecision is for the network type section decision. It's
+	// followed by "/" and the UID of the network type.
+	SignTypeBTPDecision SignType = "btp_decision"
+)
+
+// BTPDecisionSignType returns SignType for the decision of the network type.
+func BTPDecisionSignType(uid string) SignType {
+	return SignTypeBTPDecision + "/" + SignType(uid)
+}
+
+// PayloadSigner is optionally implemented by the wallet which refuses to
+// sign plain hashes. It checks the payload is of the type, and signs the
+// hash of the payload for the type.
+type PayloadSigner interface {
+	SignPayload(typ SignType, payload []byte) ([]byte, error)
+}
+
 type Chain interface {
 	Database() db.Database
 	DoDBTask(func(database db.Database))
@@ -37,6 +82,11 @@ type Chain interface {
 	TransactionTimeout() time.Duration
 	ChildrenLimit() int
 	NephewsLimit() int
+	PeerBanThreshold() int
+	PeerScoreHalfLife() time.Duration
+	PeerBanDuration() time.Duration
+	BandwidthLimits() string
+	PacketCapture() string
 	ValidateTxOnSend() bool
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
@@ -75,6 +125,54 @@ type Chain interface {
 	WalletFor(dsa string) BaseWallet
 }
 
+// RoundTimeouts are timeouts for the steps of the consensus round.
+// Timeouts for propose, prevote and precommit grow by Delta for each round.
+// If the round exceeds ThresholdFactor times the number of validators,
+// it waits NewRound before proposing.
+type RoundTimeouts struct {
+	Propose         time.Duration
+	Prevote         time.Duration
+	Precommit       time.Duration
+	NewRound        time.Duration
+	Delta           time.Duration
+	ThresholdFactor int
+}
+
+const (
+	// MaxRoundTimeout is the maximum of each timeout and Delta, and the
+	// timeouts grown by Delta are limited to it.
+	MaxRoundTimeout = 10 * time.Minute
+	// MaxRoundThresholdFactor is the maximum of ThresholdFactor.
+	MaxRoundThresholdFactor = 100
+)
+
+// timeoutFor returns the timeout of the round, which is base grown by
+// Delta for each round up to MaxRoundTimeout.
+func (t *RoundTimeouts) timeoutFor(base time.Duration, round int32) time.Duration {
+	if base >= MaxRoundTimeout {
+		return MaxRoundTimeout
+	}
+	if round <= 0 || t.Delta <= 0 {
+		return base
+	}
+	if int64(round) >= int64((MaxRoundTimeout-base)/t.Delta) {
+		return MaxRoundTimeout
+	}
+	return base + time.Duration(round)*t.Delta
+}
+
+func (t *RoundTimeouts) ProposeTimeout(round int32) time.Duration {
+	return t.timeoutFor(t.Propose, round)
+}
+
+func (t *RoundTimeouts) PrevoteTimeout(round int32) time.Duration {
+	return t.timeoutFor(t.Prevote, round)
+}
+
+func (t *RoundTimeouts) PrecommitTimeout(round int32) time.Duration {
+	return t.timeoutFor(t.Precommit, round)
+}
+
 type Regulator interface {
 	MaxTxCount() int
 	OnPropose(now time.Time)
@@ -82,6 +180,8 @@ type Regulator interface {
 	MinCommitTimeout() time.Duration
 	OnTxExecution(count int, ed time.Duration, fd time.Duration)
 	SetBlockInterval(i time.Duration, d time.Duration)
+	RoundTimeouts() RoundTimeouts
+	SetRoundTimeouts(t RoundTimeouts)
 }
 
 type GenesisType int
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package module
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTimeouts_Timeout(t *testing.T) {
+	assert := assert.New(t)
+	rt := RoundTimeouts{
+		Propose:   time.Second,
+		Prevote:   2 * time.Second,
+		Precommit: 3 * time.Second,
+	}
+	assert.Equal(time.Second, rt.ProposeTimeout(0))
+	assert.Equal(time.Second, rt.ProposeTimeout(10))
+
+	rt.Delta = 500 * time.Millisecond
+	assert.Equal(time.Second, rt.ProposeTimeout(0))
+	assert.Equal(3*time.Second, rt.ProposeTimeout(4))
+	assert.Equal(4*time.Second, rt.PrevoteTimeout(4))
+	assert.Equal(5*time.Second, rt.PrecommitTimeout(4))
+
+	// limited without overflow
+	assert.Equal(MaxRoundTimeout, rt.ProposeTimeout(math.MaxInt32))
+	rt.Delta = time.Duration(math.MaxInt64)
+	assert.Equal(MaxRoundTimeout, rt.PrevoteTimeout(1))
+	assert.Equal(2*time.Second, rt.PrevoteTimeout(0))
+
+	rt.Precommit = 2 * MaxRoundTimeout
+	assert.Equal(MaxRoundTimeout, rt.PrecommitTimeout(0))
+}
@@ -6,6 +6,43 @@ type ConsensusStatus struct {
 	Proposer bool
 }
 
+const (
+	ConsensusEventStep     = "step"
+	ConsensusEventProposal = "proposal"
+	ConsensusEventVote     = "vote"
+	ConsensusEventTimeout  = "timeout"
+)
+
+// ConsensusEvent is a notification of consensus progress for monitoring.
+type ConsensusEvent struct {
+	Type   string
+	Height int64
+	Round  int32
+	Step   string
+
+	// Validators is set for the step entering new height.
+	Validators []Address
+
+	// Proposer and BlockPartSetHash are set for proposal.
+	Proposer         Address
+	BlockPartSetHash []byte
+
+	// VoteType, Votes and Voted are set for vote. Voted has the flags of
+	// the validators voted in the round, in the order of the validators.
+	VoteType string
+	Votes    int
+	Voted    []bool
+}
+
+// ConsensusEventWatcher is optionally implemented by Consensus notifying
+// its events for monitoring.
+type ConsensusEventWatcher interface {
+	// WatchEvents returns the channel receiving the events and the function
+	// to stop watching. The channel is closed if it's stopped or if the
+	// receiver is too slow to receive the events.
+	WatchEvents(size int) (<-chan *ConsensusEvent, func())
+}
+
 const (
 	FlagNextProofContext = 0x1
 	FlagBTPBlockHeader   = 0x2
@@ -21,6 +21,7 @@ type NetworkManager interface {
 
 	SetTrustSeeds(seeds string)
 	SetInitialRoles(roles ...Role)
+	SetBandwidthLimits(limits string) error
 }
 
 type Reactor interface {
@@ -35,6 +36,41 @@ type ProtocolHandler interface {
 	Multicast(pi ProtocolInfo, b []byte, role Role) error
 	Unicast(pi ProtocolInfo, b []byte, id PeerID) error
 	GetPeers() []PeerID
+
+	// ReportPeer lowers the score of the peer for the offence. The peer is
+	// banned for a while if its score falls below the threshold.
+	ReportPeer(id PeerID, offence Offence)
+}
+
+type Offence byte
+
+const (
+	// OffenceMalformedMessage is for a message which can't be decoded.
+	OffenceMalformedMessage Offence = iota
+	// OffenceInvalidMessage is for a message which fails verification,
+	// like invalid signatures or block parts.
+	OffenceInvalidMessage
+	// OffenceDuplicateMessage is for a message conflicting with the one
+	// received before, like duplicate votes.
+	OffenceDuplicateMessage
+	// OffenceUnexpectedMessage is for a message which violates the protocol.
+	OffenceUnexpectedMessage
+	OffenceReserved
+)
+
+func (o Offence) String() string {
+	switch o {
+	case OffenceMalformedMessage:
+		return "MalformedMessage"
+	case OffenceInvalidMessage:
+		return "InvalidMessage"
+	case OffenceDuplicateMessage:
+		return "DuplicateMessage"
+	case OffenceUnexpectedMessage:
+		return "UnexpectedMessage"
+	default:
+		return fmt.Sprintf("Offence(%d)", byte(o))
+	}
 }
 
 type BroadcastType byte
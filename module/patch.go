@@ -2,8 +2,13 @@ package module
 
 const (
 	PatchTypeSkipTransaction = "skip_txs"
+	PatchTypeDoubleSign      = "double_sign"
 )
 
+// DoubleSignEvidenceWindow is the number of blocks after the height of the
+// conflicting votes in which the evidence of double signing is accepted.
+const DoubleSignEvidenceWindow = 1000
+
 type Patch interface {
 	Type() string
 	Data() []byte
@@ -17,4 +22,18 @@ type SkipTransactionPatch interface {
 	Verify(vl ValidatorList, roundLimit int64, nid int) error
 }
 
+// DoubleSignPatch is the evidence of conflicting votes signed by a validator
+// for the same height, round and vote type.
+type DoubleSignPatch interface {
+	Patch
+	Height() int64   // height of the conflicting votes
+	Signer() Address // address of the validator signed the votes
+
+	// Verify check the votes are conflicting and signed by the signer in
+	// the validators of the height. vl is the validators of the height, and
+	// nvHash is the hash of the validators of the next height, which binds
+	// the voted blocks to the chain. nil nvHash skips the binding check.
+	Verify(nid int, vl ValidatorList, nvHash []byte) error
+}
+
 type PatchDecoder func(t string, bs []byte) (Patch, error)
@@ -36,6 +36,8 @@ const (
 	PurgeEnumCache
 	ContractSetEvent
 	FixMapValues
+	DoubleSignEvidence
+	RoundTimeoutsInState
 	LastRevisionBit
 )
 
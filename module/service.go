@@ -393,6 +393,11 @@ type ServiceManager interface {
 	// should be exported to the database
 	ExportResult(result []byte, vh []byte, dst db.Database) error
 
+	// ExportState exports the entries of the world state and the extension
+	// related with the result to the database. Unlike ExportResult,
+	// receipts are not exported.
+	ExportState(result []byte, vh []byte, dst db.Database) error
+
 	// ImportResult imports all related entries related with the result
 	// should be imported from the database
 	ImportResult(result []byte, vh []byte, src db.Database) error
@@ -41,6 +41,8 @@ const (
 	TraceModeNone TraceMode = iota
 	TraceModeInvoke
 	TraceModeBalanceChange
+	TraceModeCall
+	TraceModeStateDiff
 )
 
 type OpType int
@@ -81,3 +83,22 @@ type TraceCallback interface {
 	OnFrameExit(success bool) error
 	OnBalanceChange(opType OpType, from, to Address, amount *big.Int) error
 }
+
+// CallTraceCallback is an optional interface of TraceCallback for
+// TraceModeCall. OnCallStart and OnCallEnd are called just after
+// OnFrameEnter and just before OnFrameExit with the details of the frame.
+type CallTraceCallback interface {
+	OnCallStart(from, to Address, value *big.Int, method string, params interface{}) error
+	OnCallEnd(stepUsed *big.Int, status error) error
+	OnEvent(addr Address, indexed, data [][]byte) error
+}
+
+// StateDiffTraceCallback is an optional interface of TraceCallback for
+// TraceModeStateDiff. It's called just before OnTransactionEnd for each
+// change made by the transaction. Values are nil if they don't exist.
+// Contracts are given in JSON form with the current and the next contract.
+type StateDiffTraceCallback interface {
+	OnBalanceDiff(addr Address, old, new *big.Int) error
+	OnStorageDiff(addr Address, key, old, new []byte) error
+	OnContractDiff(addr Address, old, new interface{}) error
+}
@@ -0,0 +1,338 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultAddressBookSize        = 256
+	DefaultAddressBookDialLimit   = 16
+	DefaultAddressBookMaxFailures = 8
+
+	keyAddressBookPrefix = "network.book."
+	keyStaticPeerPrefix  = "network.statics."
+)
+
+type addressBookEntry struct {
+	NetAddress NetAddress
+	ID         []byte
+	Role       PeerRoleFlag
+	LastSeen   int64 // unix time in milliseconds
+	RTT        int64 // average rtt in microseconds, 0 if unknown
+	Failures   int   // number of dial failures after the last connection
+}
+
+// better returns true if the entry is more preferable to dial than o.
+// Fewer failures come first, then lower RTT and recent ones.
+func (e *addressBookEntry) better(o *addressBookEntry) bool {
+	if e.Failures != o.Failures {
+		return e.Failures < o.Failures
+	}
+	if (e.RTT == 0) != (o.RTT == 0) {
+		return o.RTT == 0
+	}
+	if e.RTT != o.RTT {
+		return e.RTT < o.RTT
+	}
+	return e.LastSeen > o.LastSeen
+}
+
+func (e *addressBookEntry) toMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"addr":     string(e.NetAddress),
+		"id":       NewPeerID(e.ID).String(),
+		"role":     e.Role,
+		"lastSeen": time.UnixMilli(e.LastSeen).UTC().Format(time.RFC3339),
+		"failures": e.Failures,
+	}
+	if e.RTT > 0 {
+		m["rtt"] = (time.Duration(e.RTT) * time.Microsecond).String()
+	}
+	return m
+}
+
+// addressBook keeps peers which the node connected to, so that it can
+// reconnect them without help of seeds after restart. It also keeps static
+// peers which are added by the operator and always connected.
+type addressBook struct {
+	mtx     sync.Mutex
+	entries map[NetAddress]*addressBookEntry
+	dirty   bool
+	statics map[NetAddress]bool
+
+	bucket    *db.CodedBucket
+	key       []byte
+	staticKey []byte
+	logger    log.Logger
+	now       func() time.Time
+}
+
+func newAddressBook(dbase db.Database, channel string, l log.Logger) *addressBook {
+	b := &addressBook{
+		entries:   make(map[NetAddress]*addressBookEntry),
+		statics:   make(map[NetAddress]bool),
+		key:       []byte(keyAddressBookPrefix + channel),
+		staticKey: []byte(keyStaticPeerPrefix + channel),
+		logger:    l,
+		now:       time.Now,
+	}
+	if dbase != nil {
+		if bk, err := db.NewCodedBucket(dbase, db.ChainProperty, nil); err != nil {
+			l.Warnf("fail to get bucket for address book err=%+v", err)
+		} else {
+			b.bucket = bk
+			b.load()
+		}
+	}
+	return b
+}
+
+func (b *addressBook) load() {
+	var entries []*addressBookEntry
+	if err := b.bucket.Get(db.Raw(b.key), &entries); err != nil {
+		if !errors.NotFoundError.Equals(err) {
+			b.logger.Warnf("fail to load address book err=%+v", err)
+		}
+	}
+	for _, e := range entries {
+		b.entries[e.NetAddress] = e
+	}
+
+	var statics []NetAddress
+	if err := b.bucket.Get(db.Raw(b.staticKey), &statics); err != nil {
+		if !errors.NotFoundError.Equals(err) {
+			b.logger.Warnf("fail to load static peers err=%+v", err)
+		}
+	}
+	for _, na := range statics {
+		b.statics[na] = true
+	}
+}
+
+// flush stores entries if there are changes.
+func (b *addressBook) flush() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if !b.dirty || b.bucket == nil {
+		return
+	}
+	if err := b.bucket.Set(db.Raw(b.key), b._array()); err != nil {
+		b.logger.Warnf("fail to save address book err=%+v", err)
+		return
+	}
+	b.dirty = false
+}
+
+// _array returns entries sorted by preference.
+func (b *addressBook) _array() []*addressBookEntry {
+	entries := make([]*addressBookEntry, 0, len(b.entries))
+	for _, e := range b.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].better(entries[j])
+	})
+	return entries
+}
+
+func (b *addressBook) _update(e *addressBookEntry, p *Peer) {
+	e.ID = p.ID().Bytes()
+	e.Role = p.Role()
+	e.LastSeen = b.now().UnixMilli()
+	if avg := p.rtt.Avg(time.Microsecond); avg > 0 {
+		e.RTT = int64(avg)
+	}
+	b.dirty = true
+}
+
+// onConnect records the peer. Only the address of outgoing connection is
+// added since the address of incoming connection may not be reachable.
+func (b *addressBook) onConnect(p *Peer) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	na := p.NetAddress()
+	e, ok := b.entries[na]
+	if !ok {
+		if p.In() {
+			return
+		}
+		e = &addressBookEntry{NetAddress: na}
+		b.entries[na] = e
+	}
+	if !p.In() {
+		e.Failures = 0
+	}
+	b._update(e, p)
+	b._evict()
+}
+
+// onClose updates role and RTT of the peer on disconnection.
+func (b *addressBook) onClose(p *Peer) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if e, ok := b.entries[p.NetAddress()]; ok {
+		b._update(e, p)
+	}
+}
+
+// onFailure counts dial failure for the address, and removes the entry if
+// it fails too many times.
+func (b *addressBook) onFailure(na NetAddress) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if e, ok := b.entries[na]; ok {
+		e.Failures++
+		if e.Failures >= DefaultAddressBookMaxFailures {
+			delete(b.entries, na)
+		}
+		b.dirty = true
+	}
+}
+
+func (b *addressBook) _evict() {
+	if len(b.entries) <= DefaultAddressBookSize {
+		return
+	}
+	entries := b._array()
+	for _, e := range entries[DefaultAddressBookSize:] {
+		delete(b.entries, e.NetAddress)
+	}
+}
+
+// candidates returns up to n entries in order of preference which
+// satisfy f.
+func (b *addressBook) candidates(n int, f func(e *addressBookEntry) bool) []*addressBookEntry {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	var l []*addressBookEntry
+	for _, e := range b._array() {
+		if len(l) >= n {
+			break
+		}
+		if f(e) {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+func (b *addressBook) mapArray() []map[string]interface{} {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	entries := b._array()
+	rarr := make([]map[string]interface{}, len(entries))
+	for i, e := range entries {
+		rarr[i] = e.toMap()
+	}
+	return rarr
+}
+
+func (b *addressBook) clear() {
+	b.mtx.Lock()
+	b.entries = make(map[NetAddress]*addressBookEntry)
+	b.dirty = true
+	b.mtx.Unlock()
+
+	b.flush()
+}
+
+func (b *addressBook) _saveStatics() {
+	if b.bucket == nil {
+		return
+	}
+	if err := b.bucket.Set(db.Raw(b.staticKey), b._staticArray()); err != nil {
+		b.logger.Warnf("fail to save static peers err=%+v", err)
+	}
+}
+
+func (b *addressBook) _staticArray() []NetAddress {
+	statics := make([]NetAddress, 0, len(b.statics))
+	for na := range b.statics {
+		statics = append(statics, na)
+	}
+	sort.Slice(statics, func(i, j int) bool {
+		return statics[i] < statics[j]
+	})
+	return statics
+}
+
+func (b *addressBook) addStatic(na NetAddress) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.statics[na] {
+		return false
+	}
+	b.statics[na] = true
+	b._saveStatics()
+	return true
+}
+
+func (b *addressBook) removeStatic(na NetAddress) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if !b.statics[na] {
+		return false
+	}
+	delete(b.statics, na)
+	b._saveStatics()
+	return true
+}
+
+func (b *addressBook) isStatic(na NetAddress) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.statics[na]
+}
+
+// staticArray returns static peers sorted by address.
+func (b *addressBook) staticArray() []NetAddress {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b._staticArray()
+}
+
+func managerOf(c module.Chain) (*manager, error) {
+	if nm := c.NetworkManager(); nm != nil {
+		if mgr, ok := nm.(*manager); ok {
+			return mgr, nil
+		}
+	}
+	return nil, errors.InvalidStateError.New("NetworkNotAvailable")
+}
+
+// AddressBook returns entries of the address book of the chain in order of
+// preference.
+func AddressBook(c module.Chain) ([]map[string]interface{}, error) {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.p2p.book.mapArray(), nil
+}
+
+// ClearAddressBook removes all entries of the address book of the chain.
+func ClearAddressBook(c module.Chain) error {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return err
+	}
+	mgr.p2p.book.clear()
+	return nil
+}
@@ -0,0 +1,88 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+)
+
+func Test_addressBook(t *testing.T) {
+	dbase := db.NewMapDB()
+	b := newAddressBook(dbase, "1", log.New())
+	now := time.Unix(1000, 0)
+	b.now = func() time.Time { return now }
+
+	p1 := generatePeer()
+	p1.setRole(p2pRoleRoot)
+	p2 := generatePeer()
+	p3 := generatePeer()
+	p3.in = true
+
+	b.onConnect(p1)
+	now = now.Add(time.Second)
+	b.onConnect(p2)
+	b.onConnect(p3)
+	assert.Len(t, b.mapArray(), 2, "incoming peer shall not be added")
+
+	// recent one first
+	entries := b.candidates(10, func(e *addressBookEntry) bool { return true })
+	assert.Len(t, entries, 2)
+	assert.Equal(t, p2.NetAddress(), entries[0].NetAddress)
+	assert.Equal(t, p1.NetAddress(), entries[1].NetAddress)
+	assert.Equal(t, p2pRoleRoot, entries[1].Role)
+	assert.Equal(t, p1.ID().Bytes(), entries[1].ID)
+
+	// fewer failures first
+	b.onFailure(p2.NetAddress())
+	entries = b.candidates(1, func(e *addressBookEntry) bool { return true })
+	assert.Len(t, entries, 1)
+	assert.Equal(t, p1.NetAddress(), entries[0].NetAddress)
+	entries = b.candidates(10, func(e *addressBookEntry) bool {
+		return e.NetAddress != p1.NetAddress()
+	})
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 1, entries[0].Failures)
+
+	// entries survive restarts
+	b.flush()
+	b2 := newAddressBook(dbase, "1", log.New())
+	assert.Equal(t, b.mapArray(), b2.mapArray())
+	assert.Empty(t, newAddressBook(dbase, "2", log.New()).mapArray())
+
+	// connection resets failures, and too many failures remove the entry
+	b.onConnect(p2)
+	assert.Equal(t, 0, b.entries[p2.NetAddress()].Failures)
+	for i := 0; i < DefaultAddressBookMaxFailures; i++ {
+		b.onFailure(p1.NetAddress())
+	}
+	assert.Len(t, b.mapArray(), 1)
+
+	b.clear()
+	assert.Empty(t, b.mapArray())
+	assert.Empty(t, newAddressBook(dbase, "1", log.New()).mapArray())
+}
+
+func Test_addressBook_statics(t *testing.T) {
+	dbase := db.NewMapDB()
+	b := newAddressBook(dbase, "1", log.New())
+
+	assert.True(t, b.addStatic("127.0.0.1:8081"))
+	assert.True(t, b.addStatic("127.0.0.1:8080"))
+	assert.False(t, b.addStatic("127.0.0.1:8080"))
+	assert.True(t, b.isStatic("127.0.0.1:8080"))
+	assert.Equal(t, []NetAddress{"127.0.0.1:8080", "127.0.0.1:8081"}, b.staticArray())
+
+	// statics survive restarts, and they are not affected by clear
+	b.clear()
+	b2 := newAddressBook(dbase, "1", log.New())
+	assert.Equal(t, b.staticArray(), b2.staticArray())
+	assert.Empty(t, newAddressBook(dbase, "2", log.New()).staticArray())
+
+	assert.True(t, b2.removeStatic("127.0.0.1:8081"))
+	assert.False(t, b2.removeStatic("127.0.0.1:8081"))
+	assert.Equal(t, []NetAddress{"127.0.0.1:8080"}, newAddressBook(dbase, "1", log.New()).staticArray())
+}
@@ -20,6 +20,7 @@ var (
 	p2pProtoAuthSecureResponse    = module.ProtocolInfo(0x0200)
 	p2pProtoAuthSignatureRequest  = module.ProtocolInfo(0x0300)
 	p2pProtoAuthSignatureResponse = module.ProtocolInfo(0x0400)
+	p2pProtoAuthNoiseMessage      = module.ProtocolInfo(0x0500)
 
 	DefaultSecureEllipticCurve = elliptic.P256()
 	DefaultSecureSuites        = []SecureSuite{
@@ -86,6 +87,8 @@ func (a *Authenticator) onPacket(pkt *Packet, p *Peer) {
 			a.handleSignatureRequest(pkt, p)
 		case p2pProtoAuthSignatureResponse:
 			a.handleSignatureResponse(pkt, p)
+		case p2pProtoAuthNoiseMessage:
+			a.handleNoiseMessage(pkt, p)
 		default:
 			p.CloseByError(ErrNotRegisteredProtocol)
 		}
@@ -97,6 +100,10 @@ func (a *Authenticator) onPacket(pkt *Packet, p *Peer) {
 func (a *Authenticator) Signature(content []byte) []byte {
 	defer a.mtx.Unlock()
 	a.mtx.Lock()
+	if ps, ok := a.wallet.(module.PayloadSigner); ok {
+		sb, _ := ps.SignPayload(module.SignTypeAuth, content)
+		return sb
+	}
 	h := crypto.SHA3Sum256(content)
 	sb, _ := a.wallet.Sign(h)
 	return sb
@@ -261,6 +268,7 @@ type SecureRequest struct {
 	SecureSuites     []SecureSuite
 	SecureAeadSuites []SecureAeadSuite
 	SecureParam      []byte
+	NoiseParam       []byte
 }
 type SecureResponse struct {
 	Channel         string
@@ -297,6 +305,11 @@ func (a *Authenticator) sendSecureRequest(p *Peer) {
 		SecureAeadSuites: sas,
 		SecureParam:      p.secureKey.marshalPublicKey(),
 	}
+	if msg, err := a.noiseRequest(p, sms, sas); err != nil {
+		a.logger.Infoln("sendSecureRequest", p.ConnString(), "fail to start noise", err)
+	} else {
+		m.NoiseParam = msg
+	}
 
 	p.rtt.Start()
 	a.sendMessage(p2pProtoAuth, p2pProtoAuthSecureRequest, m, p)
@@ -314,9 +327,13 @@ func (a *Authenticator) handleSecureRequest(pkt *Packet, p *Peer) {
 	}
 	a.logger.Traceln("handleSecureRequest", rm, p)
 	p.setChannel(rm.Channel)
+	sss := rm.SecureSuites
+	if !a.isAvailableNoise(p.Channel(), rm) {
+		sss = removeSecureSuite(sss, SecureSuiteNoise)
+	}
 	m := &SecureResponse{
 		Channel:         p.Channel(),
-		SecureSuite:     a.resolveSecureSuite(p.Channel(), rm.SecureSuites),
+		SecureSuite:     a.resolveSecureSuite(p.Channel(), sss),
 		SecureAeadSuite: SecureAeadSuiteNone,
 		SecureError:     SecureErrorNone,
 	}
@@ -324,6 +341,10 @@ func (a *Authenticator) handleSecureRequest(pkt *Packet, p *Peer) {
 	a.logger.Traceln("handleSecureRequest", p.ConnString(), "SecureSuite", m.SecureSuite)
 	if m.SecureSuite == SecureSuiteUnknown {
 		m.SecureError = SecureErrorInvalid
+	} else if m.SecureSuite == SecureSuiteNoise {
+		//SecureAeadSuite is decided by the requester for the first handshake message
+		m.SecureAeadSuite = noiseAeadSuite(rm.SecureAeadSuites)
+		a.logger.Traceln("handleSecureRequest", p.ConnString(), "SecureAeadSuite", m.SecureAeadSuite)
 	} else if m.SecureSuite != SecureSuiteNone {
 		m.SecureAeadSuite = a.resolveSecureAeadSuite(p.Channel(), rm.SecureAeadSuites)
 		a.logger.Traceln("handleSecureRequest", p.ConnString(), "SecureAeadSuite", m.SecureAeadSuite)
@@ -338,6 +359,14 @@ func (a *Authenticator) handleSecureRequest(pkt *Packet, p *Peer) {
 
 	p.secureKey = newSecureKey(DefaultSecureEllipticCurve, DefaultSecureKeyLogWriter)
 	m.SecureParam = p.secureKey.marshalPublicKey()
+	if m.SecureSuite == SecureSuiteNoise {
+		if msg, err := a.noiseResponse(p, m.SecureAeadSuite, rm.NoiseParam); err != nil {
+			a.logger.Infoln("handleSecureRequest", p.ConnString(), "failed noise", err)
+			m.SecureError = SecureErrorInvalid
+		} else {
+			m.SecureParam = msg
+		}
+	}
 
 	if m.SecureError != SecureErrorNone {
 		a.sendMessage(p2pProtoAuth, p2pProtoAuthSecureResponse, m, p)
@@ -348,6 +377,11 @@ func (a *Authenticator) handleSecureRequest(pkt *Packet, p *Peer) {
 	}
 
 	p.rtt.Start()
+	if m.SecureSuite == SecureSuiteNoise {
+		a.setWaitInfo(p2pProtoAuthNoiseMessage, p)
+		a.sendMessage(p2pProtoAuth, p2pProtoAuthSecureResponse, m, p)
+		return
+	}
 	a.setWaitInfo(p2pProtoAuthSignatureRequest, p)
 	a.sendMessage(p2pProtoAuth, p2pProtoAuthSecureResponse, m, p)
 
@@ -377,6 +411,16 @@ func (a *Authenticator) handleSecureResponse(pkt *Packet, p *Peer) {
 		return
 	}
 
+	if rm.SecureSuite == SecureSuiteNoise {
+		if err := a.finishNoise(p, rm.SecureAeadSuite, rm.SecureParam); err != nil {
+			a.logger.Infoln("handleSecureResponse", p.ConnString(), "failed noise", err)
+			p.CloseByError(err)
+			return
+		}
+		a.nextOnPeer(p)
+		return
+	}
+
 	if err := a.applySecureConn(p, rm.SecureSuite, rm.SecureAeadSuite, rm.SecureParam, false); err != nil {
 		a.logger.Infoln("handleSecureResponse", p.ConnString(), "failed SecureConn", err)
 		p.CloseByError(err)
@@ -470,3 +514,151 @@ func (a *Authenticator) handleSignatureResponse(pkt *Packet, p *Peer) {
 	}
 	a.nextOnPeer(p)
 }
+
+func removeSecureSuite(sss []SecureSuite, ss SecureSuite) []SecureSuite {
+	r := make([]SecureSuite, 0, len(sss))
+	for _, s := range sss {
+		if s != ss {
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+func (a *Authenticator) isAvailableNoise(channel string, rm *SecureRequest) bool {
+	if len(rm.NoiseParam) == 0 {
+		return false
+	}
+	sa := noiseAeadSuite(rm.SecureAeadSuites)
+	return sa != SecureAeadSuiteNone && a.isSupportedSecureAeadSuite(channel, sa)
+}
+
+func (a *Authenticator) noiseIdentity(static []byte) []byte {
+	return a.encode(&NoiseIdentity{
+		PublicKey: a.wallet.PublicKey(),
+		Signature: a.Signature(noiseSignContent(static)),
+	})
+}
+
+func (a *Authenticator) verifyNoiseIdentity(payload []byte, static []byte) (module.PeerID, error) {
+	ni := &NoiseIdentity{}
+	if err := a.decode(payload, ni); err != nil {
+		return nil, err
+	}
+	id, err := a.VerifySignature(ni.PublicKey, ni.Signature, noiseSignContent(static))
+	if err != nil {
+		return nil, err
+	}
+	if id.Equal(a.self) {
+		return nil, errors.New("selfAddress")
+	}
+	return id, nil
+}
+
+//noiseRequest returns the first handshake message of Noise to be sent with
+//SecureRequest, nil if Noise is not requested.
+func (a *Authenticator) noiseRequest(p *Peer, sss []SecureSuite, sas []SecureAeadSuite) ([]byte, error) {
+	sa := noiseAeadSuite(sas)
+	if sa == SecureAeadSuiteNone {
+		return nil, nil
+	}
+	requested := false
+	for _, ss := range sss {
+		if ss == SecureSuiteNoise {
+			requested = true
+		}
+	}
+	if !requested {
+		return nil, nil
+	}
+	hs, err := newNoiseHandshake(p.Channel(), sa, true)
+	if err != nil {
+		return nil, err
+	}
+	msg, _, _, err := hs.WriteMessage(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.secureKey.noise = hs
+	return msg, nil
+}
+
+//noiseResponse reads the first handshake message of Noise, and returns
+//the second one to be sent with SecureResponse.
+func (a *Authenticator) noiseResponse(p *Peer, sa SecureAeadSuite, msg []byte) ([]byte, error) {
+	hs, err := newNoiseHandshake(p.Channel(), sa, false)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, err = hs.ReadMessage(nil, msg); err != nil {
+		return nil, err
+	}
+	if msg, _, _, err = hs.WriteMessage(nil, a.noiseIdentity(hs.static)); err != nil {
+		return nil, err
+	}
+	p.secureKey.sa = sa
+	p.secureKey.noise = hs
+	return msg, nil
+}
+
+//finishNoise reads the second handshake message of Noise, and sends the last
+//one. The connection is encrypted right after sending it, so that the peer
+//is authenticated without the signature round trip.
+func (a *Authenticator) finishNoise(p *Peer, sa SecureAeadSuite, msg []byte) error {
+	hs := p.secureKey.noise
+	if hs == nil || hs.sa != sa || !a.isSupportedSecureSuite(p.Channel(), SecureSuiteNoise) {
+		return errors.Wrapf(ErrIllegalArgument, "invalid noise SecureAeadSuite %d", sa)
+	}
+	payload, _, _, err := hs.ReadMessage(nil, msg)
+	if err != nil {
+		return err
+	}
+	id, err := a.verifyNoiseIdentity(payload, hs.PeerStatic())
+	if err != nil {
+		return err
+	}
+	msg, enc, dec, err := hs.WriteMessage(nil, a.noiseIdentity(hs.static))
+	if err != nil {
+		return err
+	}
+	a.sendMessage(p2pProtoAuth, p2pProtoAuthNoiseMessage, &NoiseMessage{Message: msg}, p)
+	if p.IsClosed() {
+		return ErrAlreadyClosed
+	}
+	p.secureKey.sa = sa
+	p.setID(id)
+	p.ResetConn(NewNoiseConn(p.conn, enc, dec, nil))
+	return nil
+}
+
+func (a *Authenticator) handleNoiseMessage(pkt *Packet, p *Peer) {
+	if !a.checkWaitInfo(pkt, p) {
+		return
+	}
+
+	rm := &NoiseMessage{}
+	if !a.decodePeerPacket(p, rm, pkt) {
+		return
+	}
+	a.logger.Traceln("handleNoiseMessage", rm, p)
+	p.rtt.Stop()
+
+	hs := p.secureKey.noise
+	payload, dec, enc, err := hs.ReadMessage(nil, rm.Message)
+	if err == nil {
+		var id module.PeerID
+		if id, err = a.verifyNoiseIdentity(payload, hs.PeerStatic()); err == nil {
+			p.setID(id)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("handleNoiseMessage error[%v]", err)
+		a.logger.Infoln("handleNoiseMessage", p.ConnString(), "Error", err)
+		p.CloseByError(err)
+		return
+	}
+	//packets following the handshake message could be buffered already
+	buffered, _ := p.reader.Peek(p.reader.Buffered())
+	p.ResetConn(NewNoiseConn(p.conn, enc, dec, append([]byte(nil), buffered...)))
+	a.nextOnPeer(p)
+}
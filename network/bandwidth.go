@@ -0,0 +1,240 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	DefaultBandwidthBurst     = time.Second
+	DefaultBandwidthHoldLimit = DefaultSendQueueSize
+)
+
+// ParseBandwidthLimits parses outbound bandwidth budgets in the form of
+// "<priority>:<bytes per second>,...", for example "3:1048576,4:524288".
+// Priority must be in range of 1 to DefaultSendQueueMaxPriority.
+func ParseBandwidthLimits(s string) (map[uint8]int64, error) {
+	limits := make(map[uint8]int64)
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return limits, nil
+	}
+	for _, token := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(token), ":", 2)
+		if len(kv) != 2 {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidBandwidthLimit(%s)", token)
+		}
+		pr, err := strconv.ParseUint(strings.TrimSpace(kv[0]), 0, 8)
+		if err != nil || pr < 1 || pr > DefaultSendQueueMaxPriority {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidPriority(%s)", token)
+		}
+		rate, err := strconv.ParseInt(strings.TrimSpace(kv[1]), 0, 64)
+		if err != nil || rate < 1 {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidRate(%s)", token)
+		}
+		limits[uint8(pr)] = rate
+	}
+	return limits, nil
+}
+
+type tokenBucket struct {
+	rate   int64 // bytes per second
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(b.rate) * elapsed.Seconds()
+		if burst := float64(b.rate) * DefaultBandwidthBurst.Seconds(); b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+}
+
+// bandwidthLimiter limits outbound bytes of packets for each priority with
+// token buckets. A bucket may go into debt by a large packet, then packets of
+// the priority are held until the debt is paid back. Packets of priorities
+// without budgets, including p2p control packets, are never held.
+type bandwidthLimiter struct {
+	mtx     sync.Mutex
+	buckets [DefaultSendQueueMaxPriority + 1]*tokenBucket
+	now     func() time.Time
+}
+
+func newBandwidthLimiter() *bandwidthLimiter {
+	return &bandwidthLimiter{now: time.Now}
+}
+
+// setLimits replaces budgets. Priorities not in limits become unlimited.
+func (l *bandwidthLimiter) setLimits(limits map[uint8]int64) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	for i := range l.buckets {
+		rate, ok := limits[uint8(i)]
+		if !ok || i == 0 || rate < 1 {
+			l.buckets[i] = nil
+			continue
+		}
+		if b := l.buckets[i]; b != nil {
+			b.refill(now)
+			b.rate = rate
+		} else {
+			l.buckets[i] = &tokenBucket{
+				rate:   rate,
+				tokens: float64(rate) * DefaultBandwidthBurst.Seconds(),
+				last:   now,
+			}
+		}
+	}
+}
+
+// delay returns how long packets of the priority must be held. It returns
+// zero if they can be sent now.
+func (l *bandwidthLimiter) delay(priority uint8) time.Duration {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if int(priority) >= len(l.buckets) {
+		return 0
+	}
+	b := l.buckets[priority]
+	if b == nil {
+		return 0
+	}
+	b.refill(l.now())
+	if b.tokens >= 0 {
+		return 0
+	}
+	d := time.Duration(-b.tokens / float64(b.rate) * float64(time.Second))
+	if d < time.Millisecond {
+		d = time.Millisecond
+	}
+	return d
+}
+
+// consume takes n bytes from the budget of the priority.
+func (l *bandwidthLimiter) consume(priority uint8, n int64) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if int(priority) >= len(l.buckets) || n < 1 {
+		return
+	}
+	if b := l.buckets[priority]; b != nil {
+		b.refill(l.now())
+		b.tokens -= float64(n)
+	}
+}
+
+func (l *bandwidthLimiter) String() string {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	sarr := make([]string, 0, len(l.buckets))
+	for i, b := range l.buckets {
+		if b != nil {
+			sarr = append(sarr, fmt.Sprintf("%d:%d", i, b.rate))
+		}
+	}
+	sort.Strings(sarr)
+	return strings.Join(sarr, ",")
+}
+
+// consumePacket takes bytes of the packet sent to n peers.
+func (l *bandwidthLimiter) consumePacket(pkt *Packet, n int) {
+	size := int64(packetHeaderSize+packetFooterSize) + int64(pkt.lengthOfPayload)
+	l.consume(pkt.priority, size*int64(n))
+}
+
+// sendHolder keeps packets exceeding budgets for each priority in order,
+// and notifies through C when some of them can be released.
+type sendHolder struct {
+	limiter *bandwidthLimiter
+	queues  [DefaultSendQueueMaxPriority + 1][]context.Context
+	timer   *time.Timer
+}
+
+func newSendHolder(l *bandwidthLimiter) *sendHolder {
+	h := &sendHolder{
+		limiter: l,
+		timer:   time.NewTimer(time.Hour),
+	}
+	h.timer.Stop()
+	return h
+}
+
+func (h *sendHolder) C() <-chan time.Time {
+	return h.timer.C
+}
+
+// hold keeps the packet if the budget of its priority is exhausted or there
+// are packets held already for the priority. It returns false with
+// ErrQueueOverflow if it's full.
+func (h *sendHolder) hold(ctx context.Context, pkt *Packet) (bool, error) {
+	pr := int(pkt.priority)
+	if pr >= len(h.queues) {
+		return false, nil
+	}
+	if len(h.queues[pr]) == 0 && h.limiter.delay(pkt.priority) == 0 {
+		return false, nil
+	}
+	if len(h.queues[pr]) >= DefaultBandwidthHoldLimit {
+		return false, ErrQueueOverflow
+	}
+	h.queues[pr] = append(h.queues[pr], ctx)
+	return true, nil
+}
+
+// release calls f for held packets which can be sent now, in order of
+// priority.
+func (h *sendHolder) release(f func(ctx context.Context)) {
+	for i := range h.queues {
+		for len(h.queues[i]) > 0 && h.limiter.delay(uint8(i)) == 0 {
+			ctx := h.queues[i][0]
+			h.queues[i][0] = nil
+			h.queues[i] = h.queues[i][1:]
+			f(ctx)
+		}
+		if len(h.queues[i]) == 0 {
+			h.queues[i] = nil
+		}
+	}
+}
+
+// schedule resets the timer to fire when the earliest held packet can be
+// released.
+func (h *sendHolder) schedule() {
+	next := time.Duration(-1)
+	for i := range h.queues {
+		if len(h.queues[i]) == 0 {
+			continue
+		}
+		d := h.limiter.delay(uint8(i))
+		if d < time.Millisecond {
+			d = time.Millisecond
+		}
+		if next < 0 || d < next {
+			next = d
+		}
+	}
+	if !h.timer.Stop() {
+		select {
+		case <-h.timer.C:
+		default:
+		}
+	}
+	if next > 0 {
+		h.timer.Reset(next)
+	}
+}
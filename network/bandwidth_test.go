@@ -0,0 +1,96 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseBandwidthLimits(t *testing.T) {
+	limits, err := ParseBandwidthLimits("")
+	assert.NoError(t, err)
+	assert.Empty(t, limits)
+
+	limits, err = ParseBandwidthLimits("3:1048576, 4:0x400")
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint8]int64{3: 1048576, 4: 1024}, limits)
+
+	for _, s := range []string{"3", "0:1024", "8:1024", "3:0", "3:-1", "a:1024", "3:abc"} {
+		_, err = ParseBandwidthLimits(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func Test_bandwidthLimiter(t *testing.T) {
+	l := newBandwidthLimiter()
+	now := time.Unix(1000, 0)
+	l.now = func() time.Time { return now }
+	l.setLimits(map[uint8]int64{4: 1000})
+	assert.Equal(t, "4:1000", l.String())
+
+	// unlimited priorities
+	l.consume(2, 1000000)
+	assert.Zero(t, l.delay(2))
+	assert.Zero(t, l.delay(0))
+
+	// a burst is allowed, then it goes into debt
+	assert.Zero(t, l.delay(4))
+	l.consume(4, 1500)
+	assert.Equal(t, 500*time.Millisecond, l.delay(4))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Zero(t, l.delay(4))
+
+	// tokens don't exceed the burst
+	now = now.Add(time.Hour)
+	l.consume(4, 1001)
+	assert.Equal(t, time.Millisecond, l.delay(4))
+
+	l.setLimits(nil)
+	assert.Zero(t, l.delay(4))
+	assert.Equal(t, "", l.String())
+}
+
+func Test_sendHolder(t *testing.T) {
+	l := newBandwidthLimiter()
+	now := time.Unix(1000, 0)
+	l.now = func() time.Time { return now }
+	l.setLimits(map[uint8]int64{4: 1000})
+	h := newSendHolder(l)
+
+	newCtx := func(priority uint8) (context.Context, *Packet) {
+		pkt := newPacket(p2pProtoControl, p2pProtoRttReq, make([]byte, 100), nil)
+		pkt.priority = priority
+		return context.WithValue(context.Background(), p2pContextKeyPacket, pkt), pkt
+	}
+
+	ctx1, pkt1 := newCtx(4)
+	held, err := h.hold(ctx1, pkt1)
+	assert.NoError(t, err)
+	assert.False(t, held)
+	l.consumePacket(pkt1, 10)
+
+	// lower priority is held, but higher one is not
+	ctx2, pkt2 := newCtx(4)
+	held, err = h.hold(ctx2, pkt2)
+	assert.NoError(t, err)
+	assert.True(t, held)
+	ctx3, pkt3 := newCtx(2)
+	held, err = h.hold(ctx3, pkt3)
+	assert.NoError(t, err)
+	assert.False(t, held)
+
+	var released []context.Context
+	h.release(func(ctx context.Context) {
+		released = append(released, ctx)
+	})
+	assert.Empty(t, released)
+
+	now = now.Add(time.Second)
+	h.release(func(ctx context.Context) {
+		released = append(released, ctx)
+	})
+	assert.Equal(t, []context.Context{ctx2}, released)
+}
@@ -0,0 +1,213 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultCaptureMaxSize    = 100 // megabytes
+	DefaultCaptureMaxBackups = 10
+)
+
+type CaptureDirection byte
+
+const (
+	CaptureIn CaptureDirection = iota + 1
+	CaptureOut
+)
+
+func (d CaptureDirection) String() string {
+	switch d {
+	case CaptureIn:
+		return "in"
+	case CaptureOut:
+		return "out"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(d))
+	}
+}
+
+// CaptureRecord is a packet exchanged between reactors and the network.
+// Peer is the sender of an inbound packet, or the receiver of an outbound
+// unicast packet. It's empty for outbound multicast and broadcast.
+type CaptureRecord struct {
+	Timestamp   int64
+	Direction   CaptureDirection
+	Peer        []byte
+	Protocol    module.ProtocolInfo
+	SubProtocol module.ProtocolInfo
+	Dest        byte
+	TTL         byte
+	Payload     []byte
+}
+
+func (r *CaptureRecord) Time() time.Time {
+	return time.Unix(0, r.Timestamp)
+}
+
+func (r *CaptureRecord) PeerID() module.PeerID {
+	if len(r.Peer) == 0 {
+		return nil
+	}
+	return NewPeerID(r.Peer)
+}
+
+func (r *CaptureRecord) String() string {
+	return fmt.Sprintf("{time:%s,dir:%s,peer:%v,pi:%#04x,subPi:%#04x,dest:%#x,ttl:%d,len:%d}",
+		r.Time().Format(time.RFC3339Nano),
+		r.Direction,
+		r.PeerID(),
+		r.Protocol.Uint16(),
+		r.SubProtocol.Uint16(),
+		r.Dest,
+		r.TTL,
+		len(r.Payload))
+}
+
+// packetCapture writes packets to the file which is rotated by its size.
+type packetCapture struct {
+	mtx sync.Mutex
+	w   io.WriteCloser
+	now func() time.Time
+}
+
+func newPacketCapture(filename string) (*packetCapture, error) {
+	w, err := log.NewWriter(&log.WriterConfig{
+		Filename:   filename,
+		MaxSize:    DefaultCaptureMaxSize,
+		MaxBackups: DefaultCaptureMaxBackups,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &packetCapture{w: w.(io.WriteCloser), now: time.Now}, nil
+}
+
+func (c *packetCapture) capture(d CaptureDirection, pkt *Packet, id module.PeerID) {
+	if c == nil {
+		return
+	}
+	r := &CaptureRecord{
+		Direction:   d,
+		Protocol:    pkt.protocol,
+		SubProtocol: pkt.subProtocol,
+		Dest:        pkt.dest,
+		TTL:         pkt.ttl,
+		Payload:     pkt.payload[:pkt.lengthOfPayload],
+	}
+	if id != nil {
+		r.Peer = id.Bytes()
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.w == nil {
+		return
+	}
+	r.Timestamp = c.now().UnixNano()
+	b, err := codec.MP.MarshalToBytes(r)
+	if err != nil {
+		return
+	}
+	// a record is written at once, so it's never split by rotation.
+	_, _ = c.w.Write(b)
+}
+
+func (c *packetCapture) close() error {
+	if c == nil {
+		return nil
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.w == nil {
+		return nil
+	}
+	err := c.w.Close()
+	c.w = nil
+	return err
+}
+
+// CaptureReader reads records written by packet capture in order.
+type CaptureReader struct {
+	d codec.DecodeAndCloser
+}
+
+func NewCaptureReader(r io.Reader) *CaptureReader {
+	return &CaptureReader{d: codec.MP.NewDecoder(bufio.NewReader(r))}
+}
+
+// Next returns the next record. It returns io.EOF at the end.
+func (cr *CaptureReader) Next() (*CaptureRecord, error) {
+	r := new(CaptureRecord)
+	if err := cr.d.Decode(r); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// ReplayCapture delivers inbound packets in the capture to the reactors
+// registered to the network manager, as if they are received from the
+// original peers. If speed is positive, intervals between packets are kept
+// with the speed, otherwise packets are delivered without delay.
+// It returns the number of delivered packets.
+func ReplayCapture(nm module.NetworkManager, cr *CaptureReader, speed float64, stop <-chan struct{}) (int, error) {
+	m, ok := nm.(*manager)
+	if !ok {
+		return 0, errors.IllegalArgumentError.Errorf("UnsupportedNetworkManager(%T)", nm)
+	}
+	var last int64
+	cnt := 0
+	for {
+		r, err := cr.Next()
+		if err == io.EOF {
+			return cnt, nil
+		} else if err != nil {
+			return cnt, err
+		}
+		if r.Direction != CaptureIn {
+			continue
+		}
+		if speed > 0 && last != 0 && r.Timestamp > last {
+			d := time.Duration(float64(r.Timestamp-last) / speed)
+			select {
+			case <-stop:
+				return cnt, errors.ErrInterrupted
+			case <-time.After(d):
+			}
+		} else {
+			select {
+			case <-stop:
+				return cnt, errors.ErrInterrupted
+			default:
+			}
+		}
+		last = r.Timestamp
+
+		ph, ok := m.getProtocolHandler(r.Protocol)
+		if !ok {
+			m.logger.Debugln("ReplayCapture", "not registered protocol", r)
+			continue
+		}
+		if _, ok = ph.getSubProtocol(r.SubProtocol); !ok {
+			m.logger.Debugln("ReplayCapture", "not registered sub protocol", r)
+			continue
+		}
+		m.logger.Traceln("ReplayCapture", r)
+		if _, err = ph.getReactor().OnReceive(r.SubProtocol, r.Payload, r.PeerID()); err != nil {
+			m.logger.Debugf("ReplayCapture fail to handle record=%s err=%+v", r, err)
+		}
+		cnt++
+	}
+}
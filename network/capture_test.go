@@ -0,0 +1,120 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func Test_capture_packetCapture(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "capture.bin")
+	pc, err := newPacketCapture(file)
+	assert.NoError(t, err)
+	now := time.Unix(1000, 0)
+	pc.now = func() time.Time { return now }
+
+	id := generatePeerID()
+	pkt1 := NewPacket(ProtoTestNetwork, ProtoTestNetworkBroadcast, []byte("in"))
+	pkt1.dest = p2pDestAny
+	pc.capture(CaptureIn, pkt1, id)
+	pkt2 := NewPacket(ProtoTestNetwork, ProtoTestNetworkMulticast, []byte("out"))
+	pkt2.dest = p2pDestRoot
+	pc.capture(CaptureOut, pkt2, nil)
+	assert.NoError(t, pc.close())
+
+	//ignored after close
+	pc.capture(CaptureIn, pkt1, id)
+
+	f, err := os.Open(file)
+	assert.NoError(t, err)
+	defer f.Close()
+	cr := NewCaptureReader(f)
+
+	r, err := cr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, now, r.Time())
+	assert.Equal(t, CaptureIn, r.Direction)
+	assert.True(t, id.Equal(r.PeerID()))
+	assert.Equal(t, ProtoTestNetwork, r.Protocol)
+	assert.Equal(t, ProtoTestNetworkBroadcast, r.SubProtocol)
+	assert.Equal(t, byte(p2pDestAny), r.Dest)
+	assert.Equal(t, []byte("in"), r.Payload)
+
+	r, err = cr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, CaptureOut, r.Direction)
+	assert.Nil(t, r.PeerID())
+	assert.Equal(t, byte(p2pDestRoot), r.Dest)
+	assert.Equal(t, []byte("out"), r.Payload)
+
+	_, err = cr.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+type replayReactor struct {
+	records []*CaptureRecord
+}
+
+func (r *replayReactor) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	r.records = append(r.records, &CaptureRecord{SubProtocol: pi, Payload: b, Peer: id.Bytes()})
+	return false, nil
+}
+
+func (r *replayReactor) OnJoin(id module.PeerID)  {}
+func (r *replayReactor) OnLeave(id module.PeerID) {}
+
+func Test_capture_ReplayCapture(t *testing.T) {
+	w := walletFromGeneratedPrivateKey()
+	logger := testLogger()
+	nt := NewTransport(getAvailableLocalhostAddress(t), w, logger)
+	chainLogger := logger.WithFields(log.Fields{log.FieldKeyCID: "1"})
+	c := &dummyChain{nid: 1, metricCtx: context.Background(), logger: chainLogger}
+	nm := NewManager(c, nt, "", module.RoleValidator)
+	defer nm.Term()
+
+	reactor := &replayReactor{}
+	_, err := nm.RegisterReactor("replay", ProtoTestNetwork, reactor,
+		testSubProtocols, testProtoPriority, module.NotRegisteredProtocolPolicyClose)
+	assert.NoError(t, err)
+
+	id := generatePeerID()
+	records := []*CaptureRecord{
+		{Direction: CaptureIn, Peer: id.Bytes(), Protocol: ProtoTestNetwork, SubProtocol: ProtoTestNetworkBroadcast, Payload: []byte("1")},
+		{Direction: CaptureOut, Protocol: ProtoTestNetwork, SubProtocol: ProtoTestNetworkBroadcast, Payload: []byte("2")},
+		{Direction: CaptureIn, Peer: id.Bytes(), Protocol: module.ProtoConsensus, SubProtocol: ProtoTestNetworkBroadcast, Payload: []byte("3")},
+		{Direction: CaptureIn, Peer: id.Bytes(), Protocol: ProtoTestNetwork, SubProtocol: module.ProtocolInfo(0xFF00), Payload: []byte("4")},
+		{Direction: CaptureIn, Peer: id.Bytes(), Protocol: ProtoTestNetwork, SubProtocol: ProtoTestNetworkRequest, Payload: []byte("5")},
+	}
+	b := bytes.NewBuffer(nil)
+	for i, r := range records {
+		r.Timestamp = int64(i+1) * int64(time.Millisecond)
+		b.Write(codec.MP.MustMarshalToBytes(r))
+	}
+
+	cnt, err := ReplayCapture(nm, NewCaptureReader(b), 1, make(chan struct{}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cnt)
+	assert.Len(t, reactor.records, 2)
+	assert.Equal(t, ProtoTestNetworkBroadcast, reactor.records[0].SubProtocol)
+	assert.Equal(t, []byte("1"), reactor.records[0].Payload)
+	assert.True(t, id.Equal(reactor.records[0].PeerID()))
+	assert.Equal(t, ProtoTestNetworkRequest, reactor.records[1].SubProtocol)
+	assert.Equal(t, []byte("5"), reactor.records[1].Payload)
+
+	//interrupted
+	stop := make(chan struct{})
+	close(stop)
+	b.Write(codec.MP.MustMarshalToBytes(records[0]))
+	_, err = ReplayCapture(nm, NewCaptureReader(b), 0, stop)
+	assert.Error(t, err)
+}
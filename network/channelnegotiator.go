@@ -58,15 +58,17 @@ func (cn *ChannelNegotiator) onPacket(pkt *Packet, p *Peer) {
 }
 
 type JoinRequest struct {
-	Channel   string
-	Addr      NetAddress
-	Protocols []module.ProtocolInfo
+	Channel      string
+	Addr         NetAddress
+	Protocols    []module.ProtocolInfo
+	Capabilities uint32
 }
 
 type JoinResponse struct {
-	Channel   string
-	Addr      NetAddress
-	Protocols []module.ProtocolInfo
+	Channel      string
+	Addr         NetAddress
+	Protocols    []module.ProtocolInfo
+	Capabilities uint32
 }
 
 var defaultProtocols = []module.ProtocolInfo{
@@ -109,6 +111,14 @@ func (cn *ChannelNegotiator) ProtocolInfos(channel string) *ProtocolInfos {
 	return cn.m[channel]
 }
 
+//resolveCapabilities enables capabilities which are supported by both sides.
+//Legacy peers don't send capabilities, so none of them is enabled.
+func (cn *ChannelNegotiator) resolveCapabilities(p *Peer, caps uint32) {
+	caps &= p2pCapDefault
+	p.PutAttr(AttrSupportCompression, caps&p2pCapCompression != 0)
+	cn.logger.Debugln("resolveCapabilities", caps, p)
+}
+
 func (cn *ChannelNegotiator) resolveProtocols(p *Peer, channel string, protocols []module.ProtocolInfo) error {
 	if p.Channel() != channel {
 		return errors.Errorf("invalid channel")
@@ -146,7 +156,12 @@ func (cn *ChannelNegotiator) sendJoinRequest(p *Peer) {
 		p.CloseByError(err)
 		return
 	}
-	m := &JoinRequest{Channel: p.Channel(), Addr: cn.netAddress, Protocols: pis.Array()}
+	m := &JoinRequest{
+		Channel:      p.Channel(),
+		Addr:         cn.netAddress,
+		Protocols:    pis.Array(),
+		Capabilities: p2pCapDefault,
+	}
 	cn.sendMessage(p2pProtoChan, p2pProtoChanJoinReq, m, p)
 	cn.logger.Traceln("sendJoinRequest", m, p)
 }
@@ -169,8 +184,14 @@ func (cn *ChannelNegotiator) handleJoinRequest(pkt *Packet, p *Peer) {
 		return
 	}
 	p.setNetAddress(rm.Addr)
+	cn.resolveCapabilities(p, rm.Capabilities)
 
-	m := &JoinResponse{Channel: p.Channel(), Addr: cn.netAddress, Protocols: p.ProtocolInfos().Array()}
+	m := &JoinResponse{
+		Channel:      p.Channel(),
+		Addr:         cn.netAddress,
+		Protocols:    p.ProtocolInfos().Array(),
+		Capabilities: p2pCapDefault,
+	}
 	cn.sendMessage(p2pProtoChan, p2pProtoChanJoinResp, m, p)
 
 	cn.nextOnPeer(p)
@@ -194,6 +215,7 @@ func (cn *ChannelNegotiator) handleJoinResponse(pkt *Packet, p *Peer) {
 		return
 	}
 	p.setNetAddress(rm.Addr)
+	cn.resolveCapabilities(p, rm.Capabilities)
 
 	cn.nextOnPeer(p)
 }
@@ -109,6 +109,7 @@ func Test_ChannelNegotiator_Request(t *testing.T) {
 	scens := []struct {
 		givenJoinRequest   *JoinRequest
 		expectJoinResponse *JoinResponse
+		expectCompression  bool
 		expectClose        bool
 	}{
 		{ //legacy support
@@ -117,10 +118,26 @@ func Test_ChannelNegotiator_Request(t *testing.T) {
 				Addr:    testNetAddress,
 			},
 			expectJoinResponse: &JoinResponse{
-				Channel:   testChannel,
-				Addr:      testNetAddress,
-				Protocols: defaultProtocols,
+				Channel:      testChannel,
+				Addr:         testNetAddress,
+				Protocols:    defaultProtocols,
+				Capabilities: p2pCapDefault,
+			},
+		},
+		{ //compression
+			givenJoinRequest: &JoinRequest{
+				Channel:      testChannel,
+				Addr:         testNetAddress,
+				Protocols:    defaultProtocols,
+				Capabilities: p2pCapCompression,
+			},
+			expectJoinResponse: &JoinResponse{
+				Channel:      testChannel,
+				Addr:         testNetAddress,
+				Protocols:    defaultProtocols,
+				Capabilities: p2pCapDefault,
 			},
+			expectCompression: true,
 		},
 		{ //invalid channel
 			givenJoinRequest: &JoinRequest{
@@ -149,6 +166,7 @@ func Test_ChannelNegotiator_Request(t *testing.T) {
 			assert.Equal(t, scen.givenJoinRequest.Addr, p.NetAddress())
 			sortProtocols(actualJoinResponse.Protocols)
 			assert.Equal(t, *scen.expectJoinResponse, *actualJoinResponse)
+			assert.Equal(t, scen.expectCompression, p.EqualsAttr(AttrSupportCompression, true))
 		}
 
 		assert.Equal(t, scen.expectClose, p.IsClosed())
@@ -162,9 +180,10 @@ func Test_ChannelNegotiator_Response(t *testing.T) {
 	}
 
 	expectJoinRequest := &JoinRequest{
-		Channel:   testChannel,
-		Addr:      testNetAddress,
-		Protocols: defaultProtocols,
+		Channel:      testChannel,
+		Addr:         testNetAddress,
+		Protocols:    defaultProtocols,
+		Capabilities: p2pCapDefault,
 	}
 	scens := []struct {
 		givenPeerChannel  string
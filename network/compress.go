@@ -0,0 +1,88 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	DefaultPacketCompressThreshold = 4 * 1024
+)
+
+// capabilities of the connection exchanged by JoinRequest and JoinResponse.
+const (
+	p2pCapCompression uint32 = 1 << iota
+)
+
+const p2pCapDefault = p2pCapCompression
+
+// compressPacket wraps the packet with compressed payload for a peer which
+// supports p2pCapCompression. The wrapper has header, footer and extension
+// of the packet, followed by payload compressed by snappy. It returns nil
+// if the payload is not large enough or compression doesn't reduce size.
+func compressPacket(pkt *Packet) *Packet {
+	if pkt.lengthOfPayload < DefaultPacketCompressThreshold {
+		return nil
+	}
+	if err := pkt.updateHash(false); err != nil {
+		return nil
+	}
+	enc := snappy.Encode(nil, pkt.payload[:pkt.lengthOfPayload])
+	if len(enc) >= int(pkt.lengthOfPayload) {
+		return nil
+	}
+	header := pkt.headerToBytes(false)
+	footer := pkt.footerToBytes(false)
+	ext := pkt.ext[:pkt.extendInfo.len()]
+	b := make([]byte, 0, len(header)+len(footer)+len(ext)+len(enc))
+	b = append(b, header...)
+	b = append(b, footer...)
+	b = append(b, ext...)
+	b = append(b, enc...)
+	if len(b) > DefaultPacketPayloadMax {
+		return nil
+	}
+
+	wpkt := newPacket(p2pProtoControl, p2pProtoCompressed, b, pkt.src)
+	wpkt.priority = pkt.priority
+	return wpkt
+}
+
+// decompressPacket restores the packet wrapped by compressPacket, and
+// verifies hash of it.
+func decompressPacket(wpkt *Packet) (*Packet, error) {
+	pkt := &Packet{}
+	b := append([]byte(nil), wpkt.payload[:wpkt.lengthOfPayload]...)
+	b, err := pkt.setHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = pkt.setFooter(b); err != nil {
+		return nil, err
+	}
+	extLen := pkt.extendInfo.len()
+	if len(b) < extLen {
+		return nil, fmt.Errorf("short buffer")
+	}
+	if extLen > 0 {
+		pkt.ext = b[:extLen]
+	}
+	b = b[extLen:]
+	if n, err := snappy.DecodedLen(b); err != nil {
+		return nil, err
+	} else if n != int(pkt.lengthOfPayload) {
+		return nil, fmt.Errorf("invalid decoded length %d expected:%d", n, pkt.lengthOfPayload)
+	}
+	if pkt.payload, err = snappy.Decode(nil, b); err != nil {
+		return nil, err
+	}
+	h, err := pkt._hash(false)
+	if err != nil {
+		return nil, err
+	}
+	if h.Sum64() != pkt.hashOfPacket {
+		return nil, fmt.Errorf("invalid hashOfPacket %v expected:%#x", pkt, h.Sum64())
+	}
+	return pkt, nil
+}
@@ -0,0 +1,91 @@
+package network
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+func Test_compress_compressPacket(t *testing.T) {
+	src := generatePeerID()
+
+	//small payload
+	pkt := newPacket(module.ProtoConsensus, module.ProtocolInfo(0x0100), []byte("test"), src)
+	assert.Nil(t, compressPacket(pkt))
+
+	//incompressible payload
+	random := make([]byte, DefaultPacketCompressThreshold)
+	_, _ = rand.Read(random)
+	pkt = newPacket(module.ProtoFastSync, module.ProtocolInfo(0x0100), random, src)
+	assert.Nil(t, compressPacket(pkt))
+
+	payload := bytes.Repeat([]byte("block parts "), DefaultPacketCompressThreshold)
+	pkt = newPacket(module.ProtoFastSync, module.ProtocolInfo(0x0100), payload, src)
+	pkt.ttl = 3
+	pkt.extendInfo = newPacketExtendInfo(1, 4)
+	pkt.ext = []byte{1, 2, 3, 4}
+	_ = pkt.updateHash(false)
+
+	wpkt := compressPacket(pkt)
+	assert.NotNil(t, wpkt)
+	assert.Equal(t, p2pProtoControl, wpkt.protocol)
+	assert.Equal(t, p2pProtoCompressed, wpkt.subProtocol)
+	assert.Less(t, wpkt.lengthOfPayload, pkt.lengthOfPayload)
+
+	//through the wire
+	b := bytes.NewBuffer(nil)
+	_, err := wpkt.WriteTo(b)
+	assert.NoError(t, err)
+	rpkt, err := NewPacketReader(b).ReadPacket()
+	assert.NoError(t, err)
+
+	dpkt, err := decompressPacket(rpkt)
+	assert.NoError(t, err)
+	assert.Equal(t, pkt.protocol, dpkt.protocol)
+	assert.Equal(t, pkt.subProtocol, dpkt.subProtocol)
+	assert.True(t, pkt.src.Equal(dpkt.src))
+	assert.Equal(t, pkt.ttl, dpkt.ttl)
+	assert.Equal(t, pkt.hashOfPacket, dpkt.hashOfPacket)
+	assert.Equal(t, pkt.extendInfo, dpkt.extendInfo)
+	assert.Equal(t, pkt.ext, dpkt.ext)
+	assert.Equal(t, payload, dpkt.payload)
+
+	//corrupted payload
+	rpkt.payload[len(rpkt.payload)-1] ^= 0xFF
+	_, err = decompressPacket(rpkt)
+	assert.Error(t, err)
+}
+
+func Test_compress_PeerOnReceive(t *testing.T) {
+	src := generatePeerID()
+	payload := bytes.Repeat([]byte("block parts "), DefaultPacketCompressThreshold)
+	pkt := newPacket(module.ProtoFastSync, module.ProtocolInfo(0x0100), payload, src)
+	_ = pkt.updateHash(false)
+	wpkt := compressPacket(pkt)
+	assert.NotNil(t, wpkt)
+
+	var received []*Packet
+	onPacket := func(pkt *Packet, p *Peer) {
+		received = append(received, pkt)
+	}
+
+	p, _ := newPeerWithFakeConn(true)
+	p.setMetric(metric.NewNetworkMetric(metric.DefaultMetricContext()))
+	p.setPacketCbFunc(onPacket)
+	p.PutAttr(AttrSupportCompression, true)
+	assert.True(t, p.onReceive(wpkt))
+	assert.Len(t, received, 1)
+	assert.Equal(t, payload, received[0].payload)
+
+	//compression is not negotiated
+	p, _ = newPeerWithFakeConn(true)
+	p.setPacketCbFunc(onPacket)
+	assert.False(t, p.onReceive(wpkt))
+	assert.Len(t, received, 1)
+	assert.True(t, p.IsClosed())
+}
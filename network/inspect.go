@@ -46,6 +46,11 @@ func inspectP2P(mgr *manager, informal bool) map[string]interface{} {
 		m["reject"] = peerSetToMapArray(mgr.p2p.reject, informal)
 	}
 	m["trustSeeds"] = mgr.p2p.trustSeeds.Map()
+	m["bans"] = mgr.p2p.scorer.banMapArray()
+	m["bandwidthLimits"] = mgr.p2p.limiter.String()
+	if informal {
+		m["scores"] = mgr.p2p.scorer.scoreMap()
+	}
 	return m
 }
 
@@ -0,0 +1,437 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const memoryNetworkName = "memory"
+
+// MemoryLinkConfig describes faults injected into links of MemoryNetwork.
+// Loss and Reorder apply to packets of reactors only, so that handshakes and
+// p2p topology management are not affected.
+type MemoryLinkConfig struct {
+	Latency time.Duration // delay of each packet
+	Jitter  time.Duration // random extra delay up to Jitter, it may reorder packets
+	Loss    float64       // probability to drop a packet
+	Reorder float64       // probability to send a packet without delay, so that it overtakes preceding ones
+}
+
+type memoryLinkKey struct {
+	from string
+	to   string
+}
+
+// MemoryNetwork connects transports created by NewMemoryTransport over
+// in-process pipes instead of sockets. Delivery of delayed packets is driven
+// by the clock, so that tests using a fake clock can control it. Random
+// decisions use the seed given on creation.
+type MemoryNetwork struct {
+	mtx       sync.Mutex
+	clock     common.Clock
+	rand      *rand.Rand
+	listeners map[string]*memoryListener
+	config    MemoryLinkConfig
+	links     map[memoryLinkKey]MemoryLinkConfig
+	groups    map[string]int
+}
+
+func NewMemoryNetwork(cl common.Clock, seed int64) *MemoryNetwork {
+	if cl == nil {
+		cl = &common.GoTimeClock{}
+	}
+	return &MemoryNetwork{
+		clock:     cl,
+		rand:      rand.New(rand.NewSource(seed)),
+		listeners: make(map[string]*memoryListener),
+		links:     make(map[memoryLinkKey]MemoryLinkConfig),
+		groups:    make(map[string]int),
+	}
+}
+
+// SetLinkConfig sets the default configuration of links.
+func (n *MemoryNetwork) SetLinkConfig(c MemoryLinkConfig) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	n.config = c
+}
+
+// SetLinkConfigBetween sets the configuration of the link from the address
+// to the other, which overrides the default.
+func (n *MemoryNetwork) SetLinkConfigBetween(from, to string, c MemoryLinkConfig) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	n.links[memoryLinkKey{from, to}] = c
+}
+
+// Partition splits the network into the groups of addresses. Addresses
+// which are not in any group belong to another group together. Packets
+// between groups are dropped, and dials across groups fail until Heal.
+func (n *MemoryNetwork) Partition(groups ...[]string) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	n.groups = make(map[string]int)
+	for i, g := range groups {
+		for _, a := range g {
+			n.groups[a] = i + 1
+		}
+	}
+}
+
+// Heal removes the partition.
+func (n *MemoryNetwork) Heal() {
+	n.Partition()
+}
+
+func (n *MemoryNetwork) _isPartitioned(from, to string) bool {
+	return n.groups[from] != n.groups[to]
+}
+
+func (n *MemoryNetwork) _linkConfig(from, to string) MemoryLinkConfig {
+	if c, ok := n.links[memoryLinkKey{from, to}]; ok {
+		return c
+	}
+	return n.config
+}
+
+func (n *MemoryNetwork) listen(address string) (net.Listener, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if _, ok := n.listeners[address]; ok {
+		return nil, fmt.Errorf("address %s already in use", address)
+	}
+	ln := &memoryListener{
+		n:       n,
+		addr:    memoryAddr(address),
+		connCh:  make(chan net.Conn),
+		closeCh: make(chan struct{}),
+	}
+	n.listeners[address] = ln
+	return ln, nil
+}
+
+func (n *MemoryNetwork) dial(from, to string) (net.Conn, error) {
+	n.mtx.Lock()
+	ln, ok := n.listeners[to]
+	if !ok {
+		n.mtx.Unlock()
+		return nil, fmt.Errorf("dial %s: connection refused", to)
+	}
+	if n._isPartitioned(from, to) {
+		n.mtx.Unlock()
+		return nil, fmt.Errorf("dial %s: network is unreachable", to)
+	}
+	n.mtx.Unlock()
+
+	out := newMemoryPipe(n, from, to)
+	in := newMemoryPipe(n, to, from)
+	c := &memoryConn{local: memoryAddr(from), remote: memoryAddr(to), rp: in, wp: out}
+	if !ln.accept(&memoryConn{local: memoryAddr(to), remote: memoryAddr(from), rp: out, wp: in}) {
+		return nil, fmt.Errorf("dial %s: connection refused", to)
+	}
+	return c, nil
+}
+
+type memoryAddr string
+
+func (a memoryAddr) Network() string {
+	return memoryNetworkName
+}
+
+func (a memoryAddr) String() string {
+	return string(a)
+}
+
+type memoryListener struct {
+	n       *MemoryNetwork
+	addr    memoryAddr
+	connCh  chan net.Conn
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func (l *memoryListener) accept(c net.Conn) bool {
+	select {
+	case l.connCh <- c:
+		return true
+	case <-l.closeCh:
+		return false
+	}
+}
+
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.connCh:
+		return c, nil
+	case <-l.closeCh:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *memoryListener) Close() error {
+	l.once.Do(func() {
+		l.n.mtx.Lock()
+		delete(l.n.listeners, string(l.addr))
+		l.n.mtx.Unlock()
+		close(l.closeCh)
+	})
+	return nil
+}
+
+func (l *memoryListener) Addr() net.Addr {
+	return l.addr
+}
+
+type memorySegment struct {
+	at time.Time
+	b  []byte
+}
+
+// memoryPipe delivers bytes in one direction. Written bytes are split into
+// packets, and each packet is delivered after the delay of the link.
+type memoryPipe struct {
+	n        *MemoryNetwork
+	from, to string
+
+	mtx     sync.Mutex
+	cond    *sync.Cond
+	wbuf    []byte
+	pending []*memorySegment
+	rbuf    []byte
+	wclosed bool
+	rclosed bool
+}
+
+func newMemoryPipe(n *MemoryNetwork, from, to string) *memoryPipe {
+	p := &memoryPipe{n: n, from: from, to: to}
+	p.cond = sync.NewCond(&p.mtx)
+	return p
+}
+
+// framePacket returns the size and the protocol of the first packet in b,
+// and false if the packet is not complete.
+func framePacket(b []byte) (int, module.ProtocolInfo, bool) {
+	if len(b) < packetHeaderSize {
+		return 0, 0, false
+	}
+	pkt := &Packet{}
+	if _, err := pkt.setHeader(b); err != nil {
+		return 0, 0, false
+	}
+	size := packetHeaderSize + int(pkt.lengthOfPayload) + packetFooterSize
+	if len(b) < size {
+		return 0, 0, false
+	}
+	if _, err := pkt.setFooter(b[size-packetFooterSize:]); err != nil {
+		return 0, 0, false
+	}
+	size += pkt.extendInfo.len()
+	if len(b) < size {
+		return 0, 0, false
+	}
+	return size, pkt.protocol, true
+}
+
+func (p *memoryPipe) write(b []byte) (int, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.wclosed || p.rclosed {
+		return 0, io.ErrClosedPipe
+	}
+	p.wbuf = append(p.wbuf, b...)
+	for {
+		size, pi, ok := framePacket(p.wbuf)
+		if !ok {
+			break
+		}
+		seg := append([]byte(nil), p.wbuf[:size]...)
+		p.wbuf = p.wbuf[size:]
+		p._send(seg, pi)
+	}
+	return len(b), nil
+}
+
+func (p *memoryPipe) _send(b []byte, pi module.ProtocolInfo) {
+	n := p.n
+	n.mtx.Lock()
+	if n._isPartitioned(p.from, p.to) {
+		n.mtx.Unlock()
+		return
+	}
+	c := n._linkConfig(p.from, p.to)
+	delay := c.Latency
+	if c.Jitter > 0 {
+		delay += time.Duration(n.rand.Int63n(int64(c.Jitter) + 1))
+	}
+	if pi != p2pProtoControl {
+		if c.Loss > 0 && n.rand.Float64() < c.Loss {
+			n.mtx.Unlock()
+			return
+		}
+		if c.Reorder > 0 && n.rand.Float64() < c.Reorder {
+			delay = 0
+		}
+	}
+	n.mtx.Unlock()
+
+	now := n.clock.Now()
+	if delay <= 0 && len(p.pending) == 0 {
+		p.rbuf = append(p.rbuf, b...)
+		p.cond.Broadcast()
+		return
+	}
+	// keep the order of segments delivered at the same time
+	seg := &memorySegment{at: now.Add(delay), b: b}
+	idx := sort.Search(len(p.pending), func(i int) bool {
+		return p.pending[i].at.After(seg.at)
+	})
+	p.pending = append(p.pending, nil)
+	copy(p.pending[idx+1:], p.pending[idx:])
+	p.pending[idx] = seg
+	if delay <= 0 {
+		p._flush(now)
+	} else {
+		n.clock.AfterFunc(delay, p.flush)
+	}
+}
+
+func (p *memoryPipe) flush() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p._flush(p.n.clock.Now())
+}
+
+func (p *memoryPipe) _flush(now time.Time) {
+	i := 0
+	for ; i < len(p.pending) && !p.pending[i].at.After(now); i++ {
+		p.rbuf = append(p.rbuf, p.pending[i].b...)
+	}
+	if i > 0 {
+		p.pending = p.pending[i:]
+		p.cond.Broadcast()
+	}
+}
+
+func (p *memoryPipe) read(b []byte) (int, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for len(p.rbuf) == 0 && !p.rclosed && !(p.wclosed && len(p.pending) == 0) {
+		p.cond.Wait()
+	}
+	if p.rclosed {
+		return 0, io.ErrClosedPipe
+	}
+	if len(p.rbuf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, p.rbuf)
+	p.rbuf = p.rbuf[n:]
+	return n, nil
+}
+
+func (p *memoryPipe) closeWrite() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.wclosed = true
+	p.wbuf = nil
+	p.cond.Broadcast()
+}
+
+func (p *memoryPipe) closeRead() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.rclosed = true
+	p.rbuf = nil
+	p.pending = nil
+	p.cond.Broadcast()
+}
+
+type memoryConn struct {
+	local  memoryAddr
+	remote memoryAddr
+	rp     *memoryPipe
+	wp     *memoryPipe
+	once   sync.Once
+}
+
+func (c *memoryConn) Read(b []byte) (int, error) {
+	return c.rp.read(b)
+}
+
+func (c *memoryConn) Write(b []byte) (int, error) {
+	return c.wp.write(b)
+}
+
+func (c *memoryConn) Close() error {
+	c.once.Do(func() {
+		c.rp.closeRead()
+		c.wp.closeWrite()
+	})
+	return nil
+}
+
+func (c *memoryConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *memoryConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+func (c *memoryConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *memoryConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *memoryConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// memoryTransport is the transport over MemoryNetwork.
+type memoryTransport struct {
+	*transport
+}
+
+// NewMemoryTransport returns the transport which listens and dials on the
+// MemoryNetwork instead of sockets. The address is used to identify the
+// transport in the network, and it only needs to be unique in it.
+func NewMemoryTransport(n *MemoryNetwork, address string, w module.Wallet, l log.Logger) module.NetworkTransport {
+	t := newTransport(address, w, l)
+	t.l.listen = func(network, address string) (net.Listener, error) {
+		return n.listen(address)
+	}
+	t.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return n.dial(string(t.address), address)
+	}
+	return &memoryTransport{t}
+}
+
+// SetSecureSuites accepts SecureSuiteNone only, because links of
+// MemoryNetwork inject faults on packet boundaries.
+func (t *memoryTransport) SetSecureSuites(channel string, secureSuites string) error {
+	if secureSuites != "" && secureSuites != SecureSuite(SecureSuiteNone).String() {
+		return fmt.Errorf("not supported SecureSuites %s on memory transport", secureSuites)
+	}
+	return t.transport.SetSecureSuites(channel, SecureSuite(SecureSuiteNone).String())
+}
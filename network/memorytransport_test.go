@@ -0,0 +1,160 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/test/clock"
+)
+
+func Test_memoryConn(t *testing.T) {
+	cl := &clock.Clock{}
+	n := NewMemoryNetwork(cl, 1)
+	ln, err := n.listen("n1:8080")
+	assert.NoError(t, err)
+	_, err = n.listen("n1:8080")
+	assert.Error(t, err)
+
+	_, err = n.dial("n2:8080", "n3:8080")
+	assert.Error(t, err, "dial to unknown address")
+
+	connCh := make(chan *PacketReader, 1)
+	go func() {
+		c, err := ln.Accept()
+		assert.NoError(t, err)
+		connCh <- NewPacketReader(c)
+	}()
+	c, err := n.dial("n2:8080", "n1:8080")
+	assert.NoError(t, err)
+	w := NewPacketWriter(c)
+	r := <-connCh
+
+	send := func(msg string) {
+		pkt := NewPacket(ProtoTestNetwork, ProtoTestNetworkRequest, []byte(msg))
+		pkt.src = generatePeerID()
+		assert.NoError(t, w.WritePacket(pkt))
+	}
+	recvCh := make(chan string, 10)
+	go func() {
+		for {
+			pkt, err := r.ReadPacket()
+			if err != nil {
+				close(recvCh)
+				return
+			}
+			recvCh <- string(pkt.payload)
+		}
+	}()
+	expect := func(msgs ...string) {
+		for _, msg := range msgs {
+			select {
+			case m := <-recvCh:
+				assert.Equal(t, msg, m)
+			case <-time.After(time.Second):
+				assert.Fail(t, "timeout", msg)
+			}
+		}
+		select {
+		case m, ok := <-recvCh:
+			if ok {
+				assert.Fail(t, "unexpected packet", m)
+			}
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	send("m1")
+	expect("m1")
+
+	// packets are delivered after the latency
+	n.SetLinkConfig(MemoryLinkConfig{Latency: 100 * time.Millisecond})
+	send("m2")
+	send("m3")
+	expect()
+	cl.PassTime(100 * time.Millisecond)
+	expect("m2", "m3")
+
+	// reordered packet overtakes preceding ones
+	send("m4")
+	n.SetLinkConfig(MemoryLinkConfig{Latency: 100 * time.Millisecond, Reorder: 1})
+	send("m5")
+	expect("m5")
+	cl.PassTime(100 * time.Millisecond)
+	expect("m4")
+
+	n.SetLinkConfigBetween("n2:8080", "n1:8080", MemoryLinkConfig{Loss: 1})
+	send("m6")
+	expect()
+
+	n.SetLinkConfigBetween("n2:8080", "n1:8080", MemoryLinkConfig{})
+	n.Partition([]string{"n1:8080"})
+	send("m7")
+	expect()
+	_, err = n.dial("n2:8080", "n1:8080")
+	assert.Error(t, err)
+
+	n.Heal()
+	send("m8")
+	expect("m8")
+
+	assert.NoError(t, c.Close())
+	_, ok := <-recvCh
+	assert.False(t, ok)
+	assert.NoError(t, ln.Close())
+	_, err = ln.Accept()
+	assert.Error(t, err)
+}
+
+func Test_memoryTransport(t *testing.T) {
+	cl := &clock.Clock{}
+	n := NewMemoryNetwork(cl, 1)
+	idx := 0
+	arr := generateNetworkOn("TestMemory", 2, t, func(w module.Wallet, l log.Logger) module.NetworkTransport {
+		idx++
+		return NewMemoryTransport(n, fmt.Sprintf("node%d:8080", idx), w, l)
+	}, module.RoleValidator)
+	assert.Error(t, arr[0].nt.SetSecureSuites(testChannel, "tls"))
+	assert.NoError(t, arr[0].nt.SetSecureSuites(testChannel, "none"))
+
+	ch := make(chan context.Context, 10)
+	for _, r := range arr {
+		r.ch = ch
+		r.responseFunc = func(r *testReactor, rm *testNetworkRequest, id module.PeerID) error {
+			return nil
+		}
+	}
+	r1, r2 := arr[0], arr[1]
+	assert.NoError(t, r1.p2p.dial(r2.p2p.NetAddress()))
+	assert.Eventually(t, func() bool {
+		return r1.p2pConnInfo().friends == 1 && r2.p2pConnInfo().friends == 1
+	}, 3*DefaultDiscoveryPeriod, 10*time.Millisecond)
+
+	msg := r1.Request("Test1", r2.nt.PeerID())
+	assert.NoError(t, wait(ch, ProtoTestNetworkRequest, msg, 1, time.Second, r2.name))
+
+	n.SetLinkConfig(MemoryLinkConfig{Latency: time.Second})
+	msg = r1.Request("Test2", r2.nt.PeerID())
+	assert.Error(t, wait(ch, ProtoTestNetworkRequest, msg, 1, 100*time.Millisecond, r2.name))
+	cl.PassTime(time.Second)
+	assert.NoError(t, wait(ch, ProtoTestNetworkRequest, msg, 1, time.Second, r2.name))
+
+	n.SetLinkConfig(MemoryLinkConfig{})
+	n.Partition([]string{string(r1.p2p.NetAddress())})
+	msg = r1.Request("Test3", r2.nt.PeerID())
+	assert.Error(t, wait(ch, ProtoTestNetworkRequest, msg, 1, 100*time.Millisecond, r2.name))
+
+	n.Heal()
+	msg = r1.Request("Test4", r2.nt.PeerID())
+	assert.NoError(t, wait(ch, ProtoTestNetworkRequest, msg, 1, time.Second, r2.name))
+
+	for _, r := range arr {
+		assert.NoError(t, r.nt.Close())
+		r.nm.Term()
+	}
+}
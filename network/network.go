@@ -35,6 +35,9 @@ type manager struct {
 	mtr *metric.NetworkMetric
 
 	streamReactors []*streamReactor
+
+	//packets exchanged with reactors, nil if it's disabled
+	capture *packetCapture
 }
 
 func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, roles ...module.Role) module.NetworkManager {
@@ -50,6 +53,8 @@ func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, r
 		&Peer{id: nt.PeerID(), netAddress: NetAddress(nt.Address())},
 		m.t.GetDialer(m.channel),
 		m.mtr,
+		newPeerScorer(c.Database(), m.logger),
+		newAddressBook(c.Database(), m.channel, m.logger),
 		m.logger)
 
 	m.SetInitialRoles(roles...)
@@ -57,6 +62,18 @@ func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, r
 
 	m.p2p.setConnectionLimit(p2pConnTypeChildren, c.ChildrenLimit())
 	m.p2p.setConnectionLimit(p2pConnTypeNephew, c.NephewsLimit())
+	m.p2p.scorer.setConfig(c.PeerBanThreshold(), c.PeerScoreHalfLife(), c.PeerBanDuration())
+	if err := m.SetBandwidthLimits(c.BandwidthLimits()); err != nil {
+		m.logger.Warnf("ignore invalid bandwidth limits err=%+v", err)
+	}
+	if f := c.PacketCapture(); len(f) > 0 {
+		if pc, err := newPacketCapture(f); err != nil {
+			m.logger.Warnf("fail to capture packets file=%s err=%+v", f, err)
+		} else {
+			m.logger.Infof("NetworkManager capture packets to file=%s", f)
+			m.capture = pc
+		}
+	}
 
 	m.logger.Infof("NetworkManager use channel=%s for cid=%#x nid=%#x",
 		m.channel, c.CID(), c.NID())
@@ -96,6 +113,9 @@ func (m *manager) Term() {
 		ph.Term()
 		m.t.removeProtocol(m.channel, module.ProtocolInfo(k))
 	}
+	if err := m.capture.close(); err != nil {
+		m.logger.Infof("fail to close packet capture err=%+v", err)
+	}
 }
 
 func (m *manager) Start() error {
@@ -250,6 +270,15 @@ func (m *manager) SetInitialRoles(roles ...module.Role) {
 	m.p2p.setRole(NewPeerRoleFlag(roles...))
 }
 
+func (m *manager) SetBandwidthLimits(limits string) error {
+	l, err := ParseBandwidthLimits(limits)
+	if err != nil {
+		return err
+	}
+	m.p2p.limiter.setLimits(l)
+	return nil
+}
+
 func ChannelOfNetID(id int) string {
 	return strconv.FormatInt(int64(id), 16)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 )
@@ -296,6 +297,12 @@ func (c *dummyChain) Logger() log.Logger                    { return c.logger }
 func (c *dummyChain) MetricContext() context.Context        { return c.metricCtx }
 func (c *dummyChain) ChildrenLimit() int                    { return -1 }
 func (c *dummyChain) NephewsLimit() int                     { return -1 }
+func (c *dummyChain) PeerBanThreshold() int                 { return 0 }
+func (c *dummyChain) PeerScoreHalfLife() time.Duration      { return 0 }
+func (c *dummyChain) PeerBanDuration() time.Duration        { return 0 }
+func (c *dummyChain) BandwidthLimits() string               { return "" }
+func (c *dummyChain) PacketCapture() string                 { return "" }
+func (c *dummyChain) Database() db.Database                 { return nil }
 func (c *dummyChain) NetworkManager() module.NetworkManager { return c.nm }
 
 type dummyReactor struct{}
@@ -313,6 +320,12 @@ func failIfError(t *testing.T, err error, msgAndArgs ...interface{}) {
 }
 
 func generateNetwork(name string, n int, t *testing.T, roles ...module.Role) []*testReactor {
+	return generateNetworkOn(name, n, t, func(w module.Wallet, l log.Logger) module.NetworkTransport {
+		return NewTransport(getAvailableLocalhostAddress(t), w, l)
+	}, roles...)
+}
+
+func generateNetworkOn(name string, n int, t *testing.T, nf func(w module.Wallet, l log.Logger) module.NetworkTransport, roles ...module.Role) []*testReactor {
 	lv := log.GlobalLogger().GetLevel()
 	if testing.Verbose() {
 		lv = log.TraceLevel
@@ -323,7 +336,7 @@ func generateNetwork(name string, n int, t *testing.T, roles ...module.Role) []*
 		nodeLogger := log.New().WithFields(log.Fields{log.FieldKeyWallet: hex.EncodeToString(w.Address().ID())})
 		nodeLogger.SetLevel(lv)
 		nodeLogger.SetConsoleLevel(lv)
-		nt := NewTransport(getAvailableLocalhostAddress(t), w, nodeLogger)
+		nt := nf(w, nodeLogger)
 		chainLogger := nodeLogger.WithFields(log.Fields{log.FieldKeyCID: "1"})
 		c := &dummyChain{nid: 1, metricCtx: context.Background(), logger: chainLogger}
 		nm := NewManager(c, nt, "", roles...)
@@ -0,0 +1,145 @@
+package network
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/flynn/noise"
+)
+
+const (
+	noiseConnHeaderSize = 2
+	noiseConnFrameSize  = 16 * 1024
+	noiseConnTagSize    = 16
+	noisePrologue       = "GOLOOP_NOISE_XX:"
+	noiseSignPrefix     = "GOLOOP_NOISE_STATIC_KEY:"
+)
+
+// NoiseIdentity is the payload of the handshake message for binding
+// the static key of Noise to the wallet of the node.
+type NoiseIdentity struct {
+	PublicKey []byte
+	Signature []byte
+}
+
+type NoiseMessage struct {
+	Message []byte
+}
+
+func noiseCipherFunc(sa SecureAeadSuite) (noise.CipherFunc, bool) {
+	switch sa {
+	case SecureAeadSuiteChaCha20Poly1305:
+		return noise.CipherChaChaPoly, true
+	case SecureAeadSuiteAes256Gcm:
+		return noise.CipherAESGCM, true
+	default:
+		return nil, false
+	}
+}
+
+// noiseAeadSuite returns the first SecureAeadSuite which is available for Noise
+func noiseAeadSuite(sas []SecureAeadSuite) SecureAeadSuite {
+	for _, sa := range sas {
+		if _, ok := noiseCipherFunc(sa); ok {
+			return sa
+		}
+	}
+	return SecureAeadSuiteNone
+}
+
+func noiseSignContent(static []byte) []byte {
+	return append([]byte(noiseSignPrefix), static...)
+}
+
+type noiseHandshake struct {
+	*noise.HandshakeState
+	sa     SecureAeadSuite
+	static []byte
+}
+
+// newNoiseHandshake returns HandshakeState of Noise_XX_25519 with a static
+// key for the connection, which is authenticated by NoiseIdentity.
+func newNoiseHandshake(channel string, sa SecureAeadSuite, initiator bool) (*noiseHandshake, error) {
+	cf, ok := noiseCipherFunc(sa)
+	if !ok {
+		return nil, fmt.Errorf("not supported secure aead %v for noise", sa)
+	}
+	cs := noise.NewCipherSuite(noise.DH25519, cf, noise.HashSHA256)
+	static, err := cs.GenerateKeypair(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	hs, err := noise.NewHandshakeState(noise.Config{
+		CipherSuite:   cs,
+		Random:        rand.Reader,
+		Pattern:       noise.HandshakeXX,
+		Initiator:     initiator,
+		Prologue:      []byte(noisePrologue + channel),
+		StaticKeypair: static,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &noiseHandshake{HandshakeState: hs, sa: sa, static: static.Public}, nil
+}
+
+type NoiseConn struct {
+	net.Conn
+	rd   io.Reader
+	enc  *noise.CipherState
+	dec  *noise.CipherState
+	rbuf []byte
+}
+
+// NewNoiseConn returns net.Conn encrypting with enc and decrypting with dec.
+// Bytes in buffered are read before reading from conn, which are received
+// before finishing the handshake.
+func NewNoiseConn(conn net.Conn, enc, dec *noise.CipherState, buffered []byte) *NoiseConn {
+	var rd io.Reader = conn
+	if len(buffered) > 0 {
+		rd = io.MultiReader(bytes.NewReader(buffered), conn)
+	}
+	return &NoiseConn{Conn: conn, rd: rd, enc: enc, dec: dec}
+}
+
+func (c *NoiseConn) Read(b []byte) (n int, err error) {
+	if len(c.rbuf) == 0 {
+		header := make([]byte, noiseConnHeaderSize)
+		if _, err = io.ReadFull(c.rd, header); err != nil {
+			return
+		}
+		sealed := make([]byte, binary.BigEndian.Uint16(header))
+		if _, err = io.ReadFull(c.rd, sealed); err != nil {
+			return
+		}
+		if c.rbuf, err = c.dec.Decrypt(sealed[:0], nil, sealed); err != nil {
+			return
+		}
+	}
+	n = copy(b, c.rbuf)
+	c.rbuf = c.rbuf[n:]
+	return
+}
+
+func (c *NoiseConn) Write(b []byte) (n int, err error) {
+	sealed := make([]byte, noiseConnHeaderSize, noiseConnHeaderSize+noiseConnFrameSize+noiseConnTagSize)
+	for len(b) > n {
+		cn := len(b) - n
+		if cn > noiseConnFrameSize {
+			cn = noiseConnFrameSize
+		}
+		if sealed, err = c.enc.Encrypt(sealed[:noiseConnHeaderSize], nil, b[n:n+cn]); err != nil {
+			return
+		}
+		binary.BigEndian.PutUint16(sealed, uint16(len(sealed)-noiseConnHeaderSize))
+		if _, err = c.Conn.Write(sealed); err != nil {
+			return
+		}
+		n += cn
+	}
+	return
+}
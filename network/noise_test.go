@@ -0,0 +1,80 @@
+package network
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_noise_noiseAeadSuite(t *testing.T) {
+	assert.Equal(t, SecureAeadSuite(SecureAeadSuiteAes256Gcm),
+		noiseAeadSuite([]SecureAeadSuite{SecureAeadSuiteAes128Gcm, SecureAeadSuiteAes256Gcm}))
+	assert.Equal(t, SecureAeadSuite(SecureAeadSuiteNone),
+		noiseAeadSuite([]SecureAeadSuite{SecureAeadSuiteAes128Gcm}))
+}
+
+func Test_noise_NoiseConn(t *testing.T) {
+	ihs, err := newNoiseHandshake(testChannel, SecureAeadSuiteChaCha20Poly1305, true)
+	assert.NoError(t, err)
+	rhs, err := newNoiseHandshake(testChannel, SecureAeadSuiteChaCha20Poly1305, false)
+	assert.NoError(t, err)
+
+	msg1, _, _, err := ihs.WriteMessage(nil, nil)
+	assert.NoError(t, err)
+	_, _, _, err = rhs.ReadMessage(nil, msg1)
+	assert.NoError(t, err)
+	msg2, _, _, err := rhs.WriteMessage(nil, []byte("responder"))
+	assert.NoError(t, err)
+	payload, _, _, err := ihs.ReadMessage(nil, msg2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("responder"), payload)
+	assert.Equal(t, rhs.static, ihs.PeerStatic())
+	msg3, ienc, idec, err := ihs.WriteMessage(nil, []byte("initiator"))
+	assert.NoError(t, err)
+	payload, rdec, renc, err := rhs.ReadMessage(nil, msg3)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("initiator"), payload)
+	assert.Equal(t, ihs.static, rhs.PeerStatic())
+
+	data := make([]byte, noiseConnFrameSize*2+10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	//the first frame is buffered by the reader before finishing the handshake
+	buffered := &bytes.Buffer{}
+	_, err = NewNoiseConn(&writerConn{Conn: c1, w: buffered}, ienc, idec, nil).Write(data[:10])
+	assert.NoError(t, err)
+	go func() {
+		_, _ = NewNoiseConn(c1, ienc, idec, nil).Write(data[10:])
+	}()
+
+	rc := NewNoiseConn(c2, renc, rdec, buffered.Bytes())
+	b := make([]byte, len(data))
+	for n := 0; n < len(data); {
+		//read by small buffer
+		e := n + 100
+		if e > len(data) {
+			e = len(data)
+		}
+		rn, err := rc.Read(b[n:e])
+		assert.NoError(t, err)
+		n += rn
+	}
+	assert.Equal(t, data, b)
+}
+
+type writerConn struct {
+	net.Conn
+	w io.Writer
+}
+
+func (c *writerConn) Write(b []byte) (int, error) {
+	return c.w.Write(b)
+}
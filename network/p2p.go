@@ -53,6 +53,7 @@ const (
 	AttrP2PConnectionRequest    = "P2PConnectionRequest"
 	AttrP2PLegacy               = "P2PLegacy"
 	AttrSupportDefaultProtocols = "SupportDefaultProtocols"
+	AttrSupportCompression      = "SupportCompression"
 	DefaultQueryElementLength   = 200
 )
 
@@ -68,6 +69,8 @@ var (
 	p2pProtoConnResp  = module.ProtocolInfo(0x0A00)
 	p2pProtoRttReq    = module.ProtocolInfo(0x0B00)
 	p2pProtoRttResp   = module.ProtocolInfo(0x0C00)
+	//wrapper of a compressed packet, see compressPacket
+	p2pProtoCompressed = module.ProtocolInfo(0x0D00)
 )
 
 type PeerToPeer struct {
@@ -104,6 +107,15 @@ type PeerToPeer struct {
 	//monitor
 	mtr *metric.NetworkMetric
 
+	//misbehaving peers
+	scorer *peerScorer
+
+	//known peers for reconnection
+	book *addressBook
+
+	//outbound bandwidth budgets by priority
+	limiter *bandwidthLimiter
+
 	stopCh chan bool
 	run    bool
 	mtx    sync.RWMutex
@@ -118,7 +130,7 @@ const (
 	p2pEventNotAllowed = "not allowed"
 )
 
-func newPeerToPeer(channel string, self *Peer, d *Dialer, mtr *metric.NetworkMetric, l log.Logger) *PeerToPeer {
+func newPeerToPeer(channel string, self *Peer, d *Dialer, mtr *metric.NetworkMetric, ps *peerScorer, book *addressBook, l log.Logger) *PeerToPeer {
 	p2p := &PeerToPeer{
 		peerHandler: newPeerHandler(
 			self.ID(),
@@ -146,8 +158,12 @@ func newPeerToPeer(channel string, self *Peer, d *Dialer, mtr *metric.NetworkMet
 		//
 		cLimit: make(map[PeerConnectionType]int),
 		//
-		mtr: mtr,
+		mtr:     mtr,
+		scorer:  ps,
+		book:    book,
+		limiter: newBandwidthLimiter(),
 	}
+	ps.onBan = p2p.onBan
 	for connType := p2pConnTypeNone; connType < p2pConnTypeReserved; connType++ {
 		p2p.m[connType] = NewPeerSet()
 	}
@@ -216,6 +232,7 @@ func (p2p *PeerToPeer) Stop() {
 	}()
 	p2p.logger.Debugln("Stop", "wait peer Closing")
 	wg.Wait()
+	p2p.book.flush()
 
 	p2p.run = false
 	p2p.logger.Debugln("Stop", "Done")
@@ -232,6 +249,7 @@ func (p2p *PeerToPeer) dial(na NetAddress) error {
 			return nil
 		}
 		p2p.logger.Infoln("Dial fail", na, err)
+		p2p.book.onFailure(na)
 		return err
 	}
 	return nil
@@ -282,11 +300,52 @@ func (p2p *PeerToPeer) onPeer(p *Peer) {
 		p.CloseByError(fmt.Errorf("onPeer not allowed connection"))
 		return
 	}
+	if p2p.scorer.isBanned(p.ID()) || p2p.scorer.isBannedIP(p.RemoteIP()) {
+		p2p.onEvent(p2pEventNotAllowed, p)
+		p.CloseByError(ErrBannedPeer)
+		return
+	}
 	if p2p.isTrustSeed(p) {
 		p2p.trustSeeds.SetAndRemoveByData(p.DialNetAddress(), string(p.NetAddress()))
 	}
-	if p2p.addPeer(p) && !p.In() {
-		p2p.sendQuery(p)
+	if p.enableStreams() {
+		p2p.logger.Debugln("onPeer", "streams enabled", p.ID())
+	}
+	if p2p.addPeer(p) {
+		p2p.book.onConnect(p)
+		if !p.In() {
+			p2p.sendQuery(p)
+		}
+	}
+}
+
+//callback from peerScorer.report
+func (p2p *PeerToPeer) onBan(id module.PeerID) {
+	p2p.closePeers(func(p *Peer) bool {
+		return p.ID().Equal(id)
+	}, ErrBannedPeer)
+}
+
+//close connected peers which satisfy f, and returns the number of them
+func (p2p *PeerToPeer) closePeers(f func(p *Peer) bool, err error) int {
+	ps := p2p.findPeers(f)
+	for _, p := range ps {
+		p.CloseByError(err)
+	}
+	return len(ps)
+}
+
+func (p2p *PeerToPeer) isStaticPeer(p *Peer) bool {
+	return p2p.book.isStatic(p.DialNetAddress()) || p2p.book.isStatic(p.NetAddress())
+}
+
+//dial to static peers which are not connected
+func (p2p *PeerToPeer) dialStatics() {
+	for _, na := range p2p.book.staticArray() {
+		if !p2p.hasNetAddress(na) {
+			p2p.logger.Debugln("dialStatics", "dial to static peer", na)
+			go p2p.dial(na)
+		}
 	}
 }
 
@@ -330,6 +389,7 @@ func (p2p *PeerToPeer) _onClose(p *Peer) bool {
 		p2p.trustSeeds.RemoveData(p.DialNetAddress())
 	}
 	if ok := p2p._removePeer(p); ok {
+		p2p.book.onClose(p)
 		if p.ConnType() != p2pConnTypeNone {
 			p2p.onEvent(p2pEventLeave, p)
 		}
@@ -354,6 +414,7 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 	//	return
 	//}
 	if !p.ProtocolInfos().Exists(pkt.protocol) {
+		p2p.scorer.report(p.ID(), module.OffenceUnexpectedMessage)
 		p.CloseByError(ErrNotRegisteredProtocol)
 		return
 	}
@@ -374,6 +435,7 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 			case p2pProtoConnResp:
 				p2p.handleP2PConnectionResponse(pkt, p)
 			default:
+				p2p.scorer.report(p.ID(), module.OffenceUnexpectedMessage)
 				p.CloseByError(ErrNotRegisteredProtocol)
 			}
 		default:
@@ -397,12 +459,14 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 		isOneHop := pkt.ttl != 0 || pkt.dest == p2pDestPeer
 		if isOneHop && !isSourcePeer {
 			p2p.logger.Infoln("onPacket", "Drop, Invalid 1hop-src:", pkt.src, ",expected:", p.ID(), pkt.protocol, pkt.subProtocol)
+			p2p.scorer.report(p.ID(), module.OffenceUnexpectedMessage)
 			return
 		}
 
 		isBroadcast := pkt.dest == p2pDestAny && pkt.ttl == 0
 		if isBroadcast && isSourcePeer && !p.HasRole(p2pRoleRoot) {
 			p2p.logger.Infoln("onPacket", "Drop, Not authorized", p.ID(), pkt.protocol, pkt.subProtocol)
+			p2p.scorer.report(p.ID(), module.OffenceUnexpectedMessage)
 			return
 		}
 
@@ -572,6 +636,7 @@ func (p2p *PeerToPeer) handleQuery(pkt *Packet, p *Peer) {
 	err := p2p.decode(pkt.payload, qm)
 	if err != nil {
 		p2p.logger.Infoln("handleQuery", err, p)
+		p2p.scorer.report(p.ID(), module.OffenceMalformedMessage)
 		return
 	}
 	p2p.logger.Traceln("handleQuery", qm, p)
@@ -596,7 +661,7 @@ func (p2p *PeerToPeer) handleQuery(pkt *Packet, p *Peer) {
 		m.Roots = p2p.roots.Array()
 		m.Seeds = p2p.seeds.Array()
 	} else {
-		if r.Has(p2pRoleRoot) {
+		if r.Has(p2pRoleRoot) && !p2p.isStaticPeer(p) {
 			p2p.logger.Infoln("handleQuery", "not allowed connection", p)
 			p.Close("handleQuery not allowed connection")
 			return
@@ -638,6 +703,7 @@ func (p2p *PeerToPeer) handleQueryResult(pkt *Packet, p *Peer) {
 	err := p2p.decode(pkt.payload, qrm)
 	if err != nil {
 		p2p.logger.Infoln("handleQueryResult", err, p)
+		p2p.scorer.report(p.ID(), module.OffenceMalformedMessage)
 		return
 	}
 	p2p.stopRtt(p)
@@ -715,6 +781,7 @@ func (p2p *PeerToPeer) handleRttRequest(pkt *Packet, p *Peer) {
 	err := p2p.decode(pkt.payload, rm)
 	if err != nil {
 		p2p.logger.Infoln("handleRttRequest", err, p)
+		p2p.scorer.report(p.ID(), module.OffenceMalformedMessage)
 		return
 	}
 	p2p.logger.Traceln("handleRttRequest", rm, p)
@@ -741,6 +808,7 @@ func (p2p *PeerToPeer) handleRttResponse(pkt *Packet, p *Peer) {
 	err := p2p.decode(pkt.payload, rm)
 	if err != nil {
 		p2p.logger.Infoln("handleRttResponse", err, p)
+		p2p.scorer.report(p.ID(), module.OffenceMalformedMessage)
 		return
 	}
 	p2p.logger.Traceln("handleRttResponse", rm, p)
@@ -832,6 +900,7 @@ func (p2p *PeerToPeer) sendToFriends(ctx context.Context) {
 }
 
 func (p2p *PeerToPeer) sendRoutine() {
+	holder := newSendHolder(p2p.limiter)
 Loop:
 	for {
 		select {
@@ -845,80 +914,101 @@ Loop:
 					break
 				}
 				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
-				c := ctx.Value(p2pContextKeyCounter).(*Counter)
-				_ = pkt.updateHash(false)
-				r := p2p.Role()
-				switch pkt.dest {
-				case p2pDestPeer:
-					if p := p2p.getPeerByProtocol(pkt.destPeer, pkt.protocol); p != nil {
-						if err := p.send(ctx); err != nil && err != ErrDuplicatedPacket {
-							p2p.logger.Infoln("sendToPeer", err, pkt.protocol, pkt.subProtocol, p.ID())
-						}
-					}
-				case p2pDestAny:
-					if pkt.ttl == byte(module.BroadcastNeighbor) {
-						if r.Has(p2pRoleRoot) {
-							p2p.sendToPeers(ctx, p2pConnTypeFriend)
-						}
-						p2p.sendToPeers(ctx,
-							p2pConnTypeParent, p2pConnTypeUncle,
-							p2pConnTypeChildren, p2pConnTypeNephew, p2pConnTypeOther)
-					} else if pkt.ttl == byte(module.BroadcastChildren) {
-						if r.Has(p2pRoleRoot) {
-							p2p.sendToFriends(ctx)
-						}
-						p2p.sendToPeers(ctx, p2pConnTypeChildren, p2pConnTypeNephew, p2pConnTypeOther)
-					} else {
-						if r.Has(p2pRoleRoot) {
-							p2p.sendToFriends(ctx)
-						}
-						p2p.sendToPeers(ctx, p2pConnTypeChildren, p2pConnTypeOther)
-						c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeNephew)
-					}
-				case p2pDestRoot:
-					if r.Has(p2pRoleRoot) {
-						p2p.sendToFriends(ctx)
-					} else {
-						p2p.sendToPeers(ctx, p2pConnTypeParent)
-						c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeUncle)
-					}
-				case p2pDestSeed:
-					if r.Has(p2pRoleRoot) {
-						p2p.sendToFriends(ctx)
-						if r == p2pRoleRoot {
-							p2p.sendToPeers(ctx, p2pConnTypeChildren)
-							c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeNephew)
-						}
-					} else {
-						p2p.sendToPeers(ctx, p2pConnTypeParent)
-						c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeUncle)
-					}
-				default:
+				if held, err := holder.hold(ctx, pkt); err != nil {
+					c := ctx.Value(p2pContextKeyCounter).(*Counter)
+					atomic.StoreInt32(&c.fixed, 1)
+					p2p.onFailure(err, pkt, c)
+					continue
+				} else if held {
+					continue
 				}
+				p2p.dispatch(ctx)
+			}
+			holder.schedule()
+		case <-holder.C():
+			holder.release(p2p.dispatch)
+			holder.schedule()
+		}
+	}
+}
 
-				if c.alternate < 1 {
-					atomic.StoreInt32(&c.fixed, 1)
-					if c.peer < 1 {
-						p2p.onFailure(ErrNotAvailable, pkt, c)
-					} else {
-						if c.enqueue < 1 {
-							if c.overflow > 0 {
-								p2p.onFailure(ErrQueueOverflow, pkt, c)
-							} else { //if c.duplicate == c.peer
-								//flooding-end by peer-history
-							}
-						} else {
-							if c.enqueue == c.Close() {
-								p2p.onFailure(ErrNotAvailable, pkt, c)
-							}
-						}
-					}
-				} else if !p2p.alternateQueue.Push(ctx) && c.enqueue < 1 {
-					atomic.StoreInt32(&c.fixed, 1)
+//dispatch sends the packet to peers by its destination, and pushes it to
+//alternateQueue if it needs to be sent to alternate peers.
+func (p2p *PeerToPeer) dispatch(ctx context.Context) {
+	pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
+	c := ctx.Value(p2pContextKeyCounter).(*Counter)
+	_ = pkt.updateHash(false)
+	r := p2p.Role()
+	switch pkt.dest {
+	case p2pDestPeer:
+		if p := p2p.getPeerByProtocol(pkt.destPeer, pkt.protocol); p != nil {
+			if err := p.send(ctx); err != nil && err != ErrDuplicatedPacket {
+				p2p.logger.Infoln("sendToPeer", err, pkt.protocol, pkt.subProtocol, p.ID())
+			}
+		}
+	case p2pDestAny:
+		if pkt.ttl == byte(module.BroadcastNeighbor) {
+			if r.Has(p2pRoleRoot) {
+				p2p.sendToPeers(ctx, p2pConnTypeFriend)
+			}
+			p2p.sendToPeers(ctx,
+				p2pConnTypeParent, p2pConnTypeUncle,
+				p2pConnTypeChildren, p2pConnTypeNephew, p2pConnTypeOther)
+		} else if pkt.ttl == byte(module.BroadcastChildren) {
+			if r.Has(p2pRoleRoot) {
+				p2p.sendToFriends(ctx)
+			}
+			p2p.sendToPeers(ctx, p2pConnTypeChildren, p2pConnTypeNephew, p2pConnTypeOther)
+		} else {
+			if r.Has(p2pRoleRoot) {
+				p2p.sendToFriends(ctx)
+			}
+			p2p.sendToPeers(ctx, p2pConnTypeChildren, p2pConnTypeOther)
+			c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeNephew)
+		}
+	case p2pDestRoot:
+		if r.Has(p2pRoleRoot) {
+			p2p.sendToFriends(ctx)
+		} else {
+			p2p.sendToPeers(ctx, p2pConnTypeParent)
+			c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeUncle)
+		}
+	case p2pDestSeed:
+		if r.Has(p2pRoleRoot) {
+			p2p.sendToFriends(ctx)
+			if r == p2pRoleRoot {
+				p2p.sendToPeers(ctx, p2pConnTypeChildren)
+				c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeNephew)
+			}
+		} else {
+			p2p.sendToPeers(ctx, p2pConnTypeParent)
+			c.alternate = p2p.lenPeersByProtocol(pkt.protocol, p2pConnTypeUncle)
+		}
+	default:
+	}
+
+	p2p.limiter.consumePacket(pkt, c.enqueue)
+
+	if c.alternate < 1 {
+		atomic.StoreInt32(&c.fixed, 1)
+		if c.peer < 1 {
+			p2p.onFailure(ErrNotAvailable, pkt, c)
+		} else {
+			if c.enqueue < 1 {
+				if c.overflow > 0 {
 					p2p.onFailure(ErrQueueOverflow, pkt, c)
+				} else { //if c.duplicate == c.peer
+					//flooding-end by peer-history
+				}
+			} else {
+				if c.enqueue == c.Close() {
+					p2p.onFailure(ErrNotAvailable, pkt, c)
 				}
 			}
 		}
+	} else if !p2p.alternateQueue.Push(ctx) && c.enqueue < 1 {
+		atomic.StoreInt32(&c.fixed, 1)
+		p2p.onFailure(ErrQueueOverflow, pkt, c)
 	}
 }
 
@@ -949,7 +1039,12 @@ Loop:
 		case <-sendTicker.C:
 			for _, ctx := range m {
 				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
+				if p2p.limiter.delay(pkt.priority) > 0 {
+					//keep it until next tick for budget of priority
+					continue
+				}
 				c := ctx.Value(p2pContextKeyCounter).(*Counter)
+				enqueue := c.enqueue
 				switch pkt.dest {
 				case p2pDestPeer:
 				case p2pDestAny:
@@ -968,6 +1063,7 @@ Loop:
 				default:
 				}
 				delete(m, pkt.hashOfPacket)
+				p2p.limiter.consumePacket(pkt, c.enqueue-enqueue)
 
 				atomic.StoreInt32(&c.fixed, 1)
 				if c.peer < 1 {
@@ -1163,6 +1259,8 @@ func (p2p *PeerToPeer) discoverRoutine() {
 		seedTicker.Stop()
 		discoveryTicker.Stop()
 	}()
+	p2p.dialStatics()
+	p2p.dialAddressBook()
 	for na, _ := range p2p.trustSeeds.Map() {
 		p2p.logger.Debugln("discoverRoutine", "initialize", "dial to trustSeed", na)
 		p2p.dial(na)
@@ -1201,7 +1299,7 @@ Loop:
 				}
 			} else {
 				outSeeds := p2p.findPeers(func(p *Peer) bool {
-					return !p.In() && p.HasRole(p2pRoleSeed) && !p.HasRole(p2pRoleRoot)
+					return !p.In() && p.HasRole(p2pRoleSeed) && !p.HasRole(p2pRoleRoot) && !p2p.isStaticPeer(p)
 				}, p2pConnTypeNone)
 				for _, p := range outSeeds {
 					p2p.logger.Debugln("discoverRoutine", "seedTicker", "no need outgoing p2pRoleSeed connection")
@@ -1209,6 +1307,8 @@ Loop:
 				}
 			}
 		case <-discoveryTicker.C:
+			p2p.book.flush()
+			p2p.dialStatics()
 			r := p2p.Role()
 			if r.Has(p2pRoleRoot) {
 				p2p.discoverFriends()
@@ -1243,6 +1343,26 @@ Loop:
 	}
 }
 
+//dial to known peers in the address book concurrently, before querying seeds
+func (p2p *PeerToPeer) dialAddressBook() {
+	entries := p2p.book.candidates(DefaultAddressBookDialLimit, func(e *addressBookEntry) bool {
+		id := NewPeerID(e.ID)
+		if e.NetAddress == p2p.NetAddress() || id.Equal(p2p.ID()) {
+			return false
+		}
+		if !p2p.allowedPeers.IsEmpty() && !p2p.allowedPeers.Contains(id) {
+			return false
+		}
+		return !p2p.scorer.isBanned(id) && !p2p.scorer.isBannedIP(e.NetAddress.IP())
+	})
+	for _, e := range entries {
+		if !p2p.hasNetAddress(e.NetAddress) {
+			p2p.logger.Debugln("discoverRoutine", "initialize", "dial to known peer", e.NetAddress)
+			go p2p.dial(e.NetAddress)
+		}
+	}
+}
+
 func (p2p *PeerToPeer) query(r PeerRoleFlag) (needMoreSeeds bool) {
 	ps := make([]*Peer, 0)
 	if r.Has(p2pRoleRoot) {
@@ -1303,7 +1423,7 @@ func (p2p *PeerToPeer) discoverFriends() {
 			if p2p.tryTransitPeerConnection(p, p2pConnTypeNone) {
 				p2p.logger.Debugln("discoverFriends", "not allowed friend connection", p.id)
 			}
-		} else {
+		} else if !p2p.isStaticPeer(p) {
 			p2p.logger.Debugln("discoverFriends", "not allowed connection", p.id)
 			p.Close("discoverFriends not allowed connection")
 		}
@@ -1347,7 +1467,7 @@ func (p2p *PeerToPeer) discoverParents(pr PeerRoleFlag) (complete bool) {
 		return !p.HasRole(pr)
 	}, p2pConnTypeParent)
 	for _, p := range ps {
-		if !(pr == p2pRoleSeed && p2p.isTrustSeed(p)) {
+		if !(pr == p2pRoleSeed && p2p.isTrustSeed(p)) && !p2p.isStaticPeer(p) {
 			p2p.logger.Debugln("discoverParents", "not allowed connection", p.id)
 			p.Close("discoverParents not allowed connection")
 		}
@@ -1403,7 +1523,7 @@ func (p2p *PeerToPeer) discoverUncles(ur PeerRoleFlag) (complete bool) {
 		return !p.HasRole(ur)
 	}, p2pConnTypeUncle)
 	for _, p := range ps {
-		if !(ur == p2pRoleSeed && p2p.isTrustSeed(p)) {
+		if !(ur == p2pRoleSeed && p2p.isTrustSeed(p)) && !p2p.isStaticPeer(p) {
 			p2p.logger.Debugln("discoverUncles", "not allowed connection", p.id)
 			p.Close("discoverUncles not allowed connection")
 		}
@@ -1902,6 +2022,7 @@ func (p2p *PeerToPeer) handleP2PConnectionRequest(pkt *Packet, p *Peer) {
 	err := p2p.decode(pkt.payload, req)
 	if err != nil {
 		p2p.logger.Infoln("handleP2PConnectionRequest", err, p)
+		p2p.scorer.report(p.ID(), module.OffenceMalformedMessage)
 		return
 	}
 	p2p.logger.Debugln("handleP2PConnectionRequest", req, p)
@@ -1960,6 +2081,7 @@ func (p2p *PeerToPeer) handleP2PConnectionResponse(pkt *Packet, p *Peer) {
 	err := p2p.decode(pkt.payload, resp)
 	if err != nil {
 		p2p.logger.Infoln("handleP2PConnectionResponse", err, p)
+		p2p.scorer.report(p.ID(), module.OffenceMalformedMessage)
 		return
 	}
 	p2p.logger.Debugln("handleP2PConnectionResponse", resp, p)
@@ -2003,7 +2125,7 @@ func (p2p *PeerToPeer) handleP2PConnectionResponse(pkt *Packet, p *Peer) {
 					"from", p.ID(), p.ConnType())
 				if p2p.lenPeers(p2pConnTypeUncle) < p2p.getConnectionLimit(p2pConnTypeUncle) {
 					p2p.tryTransitPeerConnection(p, p2pConnTypeUncle)
-				} else {
+				} else if !p2p.isStaticPeer(p) {
 					p.Close("already has enough upstream connections")
 				}
 			}
@@ -2013,7 +2135,7 @@ func (p2p *PeerToPeer) handleP2PConnectionResponse(pkt *Packet, p *Peer) {
 					"from", p.ID(), p.ConnType())
 				if p2p.lenPeers(p2pConnTypeParent) < p2p.getConnectionLimit(p2pConnTypeParent) {
 					p2p.tryTransitPeerConnection(p, p2pConnTypeParent)
-				} else {
+				} else if !p2p.isStaticPeer(p) {
 					p.Close("already has enough upstream connections")
 				}
 			}
@@ -41,6 +41,7 @@ type Packet struct {
 	priority  uint8
 	timestamp time.Time
 	forceSend bool
+	stream    bool
 	mtx       sync.RWMutex
 }
 
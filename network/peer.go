@@ -35,6 +35,9 @@ type Peer struct {
 	closeInfoMtx sync.RWMutex
 	sendMtx      sync.Mutex
 	once         sync.Once
+	mux          streamMuxer
+	streams      map[uint16]*peerStream
+	streamMtx    sync.Mutex
 
 	//properties
 	id            module.PeerID
@@ -61,6 +64,7 @@ type Peer struct {
 	//
 	secureKey *secureKey
 	rtt       PeerRTT
+	traffic   PeerTraffic
 
 	//log
 	logger log.Logger
@@ -121,6 +125,17 @@ func (p *Peer) In() bool {
 	return p.in
 }
 
+//RemoteIP returns IP address of the connection, nil if it's unknown
+func (p *Peer) RemoteIP() net.IP {
+	if p.conn == nil {
+		return nil
+	}
+	if addr, ok := p.conn.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return NetAddress(p.conn.RemoteAddr().String()).IP()
+}
+
 func (p *Peer) DialNetAddress() NetAddress {
 	return p.dial
 }
@@ -387,16 +402,45 @@ func (p *Peer) receiveRoutine() {
 			}
 			continue
 		}
+		if !p.onReceive(pkt) {
+			return
+		}
+	}
+}
 
-		pkt.sender = p.ID()
-		p.pool.Put(pkt.hashOfPacket)
-		p.getMetric().OnRecv(pkt.dest, pkt.ttl, pkt.extendInfo.hint(), pkt.protocol.Uint16(), pkt.lengthOfPayload)
-		if cbFunc := p.getPacketCbFunc(); cbFunc != nil {
-			cbFunc(pkt, p)
-		} else {
-			p.logger.Infof("Peer[%s].onPacket in nil, Drop %s", p.ConnString(), pkt.String())
+//onReceive handles the packet from the connection or one of its streams,
+//it returns false if the peer is closed.
+func (p *Peer) onReceive(pkt *Packet) bool {
+	wireLen := pkt.lengthOfPayload
+	if pkt.protocol == p2pProtoControl && pkt.subProtocol == p2pProtoCompressed {
+		//compressed packets are allowed only if the capability is negotiated.
+		if !p.EqualsAttr(AttrSupportCompression, true) {
+			err := fmt.Errorf("compressed packet without capability")
+			p.logger.Infof("Peer[%s].onReceive err:%+v", p.ConnString(), err)
+			p.CloseByError(err)
+			return false
+		}
+		var err error
+		if pkt, err = decompressPacket(pkt); err != nil {
+			p.logger.Infof("Peer[%s].onReceive fail to decompress err:%+v", p.ConnString(), err)
+			p.CloseByError(err)
+			return false
 		}
 	}
+
+	pkt.sender = p.ID()
+	p.pool.Put(pkt.hashOfPacket)
+	p.traffic.onRecv(pkt)
+	p.getMetric().OnRecv(pkt.dest, pkt.ttl, pkt.extendInfo.hint(), pkt.protocol.Uint16(), wireLen)
+	if id := p.ID(); id != nil {
+		p.getMetric().OnPeerRecv(id.String(), pkt.protocol.Uint16(), wireLen)
+	}
+	if cbFunc := p.getPacketCbFunc(); cbFunc != nil {
+		cbFunc(pkt, p)
+	} else {
+		p.logger.Infof("Peer[%s].onPacket in nil, Drop %s", p.ConnString(), pkt.String())
+	}
+	return true
 }
 
 func (p *Peer) sendDirect(pkt *Packet) error {
@@ -408,9 +452,28 @@ func (p *Peer) sendDirect(pkt *Packet) error {
 	} else if err := p.writer.WritePacket(pkt); err != nil {
 		return err
 	}
+	p.traffic.onSend(pkt)
 	return nil
 }
 
+//packetToWrite returns the packet to be written instead of pkt
+func (p *Peer) packetToWrite(pkt *Packet) *Packet {
+	if p.EqualsAttr(AttrSupportCompression, true) {
+		if cpkt := compressPacket(pkt); cpkt != nil {
+			return cpkt
+		}
+	}
+	return pkt
+}
+
+func (p *Peer) onSent(pkt, wpkt *Packet) {
+	p.pool.Put(pkt.hashOfPacket)
+	p.getMetric().OnSend(pkt.dest, pkt.ttl, pkt.extendInfo.hint(), pkt.protocol.Uint16(), wpkt.lengthOfPayload)
+	if id := p.ID(); id != nil {
+		p.getMetric().OnPeerSend(id.String(), pkt.protocol.Uint16(), wpkt.lengthOfPayload)
+	}
+}
+
 func (p *Peer) sendRoutine() {
 	secondTick := time.NewTicker(time.Second)
 	defer secondTick.Stop()
@@ -426,15 +489,15 @@ Loop:
 					break
 				}
 				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
-				if err := p.sendDirect(pkt); err != nil {
+				wpkt := p.packetToWrite(pkt)
+				if err := p.sendDirect(wpkt); err != nil {
 					r := p.isTemporaryError(err)
 					p.logger.Tracef("Peer.sendRoutine Error isTemporary:{%v} error:{%+v} peer:%s pkt:%s",
 						r, err, p, pkt)
 					p.CloseByError(err)
 					return
 				}
-				p.pool.Put(pkt.hashOfPacket)
-				p.getMetric().OnSend(pkt.dest, pkt.ttl, pkt.extendInfo.hint(), pkt.protocol.Uint16(), pkt.lengthOfPayload)
+				p.onSent(pkt, wpkt)
 			}
 		case <-secondTick.C:
 			p.pool.RemoveBefore(DefaultPeerPoolExpireSecond)
@@ -468,7 +531,13 @@ func (p *Peer) send(ctx context.Context) error {
 		c.duplicate++
 		return ErrDuplicatedPacket
 	}
-	if ok := p.q.Push(ctx, int(pkt.priority)); !ok {
+	var ok bool
+	if s := p.streamFor(pkt); s != nil {
+		ok = s.q.Push(ctx)
+	} else {
+		ok = p.q.Push(ctx, int(pkt.priority))
+	}
+	if !ok {
 		c.overflow++
 		return ErrQueueOverflow
 	}
@@ -476,6 +545,141 @@ func (p *Peer) send(ctx context.Context) error {
 	return nil
 }
 
+//enableStreams makes packets of stream protocols be sent through their own
+//streams if the connection supports it, so that a protocol transferring
+//large data doesn't block others. Packets through the streams are protected
+//only by the connection, so it returns false if the connection is wrapped
+//by SecureSuite.
+func (p *Peer) enableStreams() bool {
+	mux, ok := p.conn.(streamMuxer)
+	if !ok {
+		return false
+	}
+	p.streamMtx.Lock()
+	defer p.streamMtx.Unlock()
+	if p.mux != nil {
+		return true
+	}
+	p.mux = mux
+	p.streams = make(map[uint16]*peerStream)
+	go p.acceptStreamRoutine()
+	return true
+}
+
+func (p *Peer) acceptStreamRoutine() {
+	for {
+		conn, err := p.mux.acceptStream()
+		if err != nil {
+			if !p.IsClosed() {
+				p.logger.Debugf("Peer[%s].acceptStreamRoutine err:%+v", p.ConnString(), err)
+			}
+			return
+		}
+		go p.receiveStreamRoutine(conn)
+	}
+}
+
+func (p *Peer) receiveStreamRoutine(conn net.Conn) {
+	defer func() {
+		if err := recover(); err != nil {
+			p.logger.Warnf("Peer[%s].receiveStreamRoutine recover from %+v\n %s", p.ConnString(), err, string(debug.Stack()))
+			p.CloseByError(fmt.Errorf("recover from %+v", err))
+		}
+		_ = conn.Close()
+	}()
+	r := NewPacketReader(conn)
+	for {
+		pkt, err := r.ReadPacket()
+		if err != nil {
+			if !p.IsClosed() {
+				p.logger.Tracef("Peer.receiveStreamRoutine error:{%+v} peer:%s", err, p)
+			}
+			return
+		}
+		if !p.onReceive(pkt) {
+			return
+		}
+	}
+}
+
+//streamFor returns the stream for the packet, nil if the packet should be
+//sent through the connection. Once opening the stream fails, packets of the
+//protocol keep going through the connection to preserve their order.
+func (p *Peer) streamFor(pkt *Packet) *peerStream {
+	if !pkt.stream {
+		return nil
+	}
+	p.streamMtx.Lock()
+	defer p.streamMtx.Unlock()
+	if p.mux == nil {
+		return nil
+	}
+	k := pkt.protocol.Uint16()
+	if s, ok := p.streams[k]; ok {
+		return s
+	}
+	var s *peerStream
+	if conn, err := p.mux.openStream(); err != nil {
+		p.logger.Debugf("Peer[%s].streamFor fail to open stream protocol:%v err:%+v",
+			p.ConnString(), pkt.protocol, err)
+	} else {
+		s = newPeerStream(p, conn)
+		go s.sendRoutine()
+	}
+	p.streams[k] = s
+	return s
+}
+
+//peerStream sends packets of a protocol through its own stream
+type peerStream struct {
+	p      *Peer
+	conn   net.Conn
+	writer *PacketWriter
+	q      Queue
+}
+
+func newPeerStream(p *Peer, conn net.Conn) *peerStream {
+	return &peerStream{
+		p:      p,
+		conn:   conn,
+		writer: NewPacketWriter(conn),
+		q:      NewQueue(DefaultPeerSendQueueSize),
+	}
+}
+
+func (s *peerStream) sendRoutine() {
+	p := s.p
+	defer func() {
+		_ = s.conn.Close()
+	}()
+	for {
+		select {
+		case <-p.close:
+			return
+		case <-s.q.Wait():
+			for {
+				ctx := s.q.Pop()
+				if ctx == nil {
+					break
+				}
+				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
+				wpkt := p.packetToWrite(pkt)
+				if err := s.conn.SetWriteDeadline(time.Now().Add(DefaultSendTimeout)); err != nil {
+					p.CloseByError(err)
+					return
+				}
+				if err := s.writer.WritePacket(wpkt); err != nil {
+					p.logger.Tracef("peerStream.sendRoutine error:{%+v} peer:%s pkt:%s", err, p, pkt)
+					p.CloseByError(err)
+					return
+				}
+				p.traffic.onSend(wpkt)
+				p.onSent(pkt, wpkt)
+			}
+		}
+	}
+}
+
 func (p *Peer) sendPacket(pkt *Packet) error {
 	if p == nil || p.IsClosed() {
 		return ErrNotAvailable
@@ -574,6 +778,15 @@ func (na NetAddress) Validate() error {
 	return nil
 }
 
+//IP returns IP address of the host, nil if the host is not an IP address
+func (na NetAddress) IP() net.IP {
+	host, _, err := net.SplitHostPort(string(na))
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
 type PeerRTT struct {
 	last time.Duration
 	avg  time.Duration
@@ -665,6 +878,33 @@ func (r *PeerRTT) String() string {
 	return fmt.Sprintf("{last:%v,avg:%v}", r.last.String(), r.avg.String())
 }
 
+//PeerTraffic counts packets and bytes of payload
+type PeerTraffic struct {
+	sendPackets int64
+	sendBytes   int64
+	recvPackets int64
+	recvBytes   int64
+}
+
+func (t *PeerTraffic) onSend(pkt *Packet) {
+	atomic.AddInt64(&t.sendPackets, 1)
+	atomic.AddInt64(&t.sendBytes, int64(pkt.lengthOfPayload))
+}
+
+func (t *PeerTraffic) onRecv(pkt *Packet) {
+	atomic.AddInt64(&t.recvPackets, 1)
+	atomic.AddInt64(&t.recvBytes, int64(pkt.lengthOfPayload))
+}
+
+func (t *PeerTraffic) Map() map[string]interface{} {
+	return map[string]interface{}{
+		"sendPackets": atomic.LoadInt64(&t.sendPackets),
+		"sendBytes":   atomic.LoadInt64(&t.sendBytes),
+		"recvPackets": atomic.LoadInt64(&t.recvPackets),
+		"recvBytes":   atomic.LoadInt64(&t.recvBytes),
+	}
+}
+
 const (
 	p2pRoleNone = PeerRoleFlag(module.RoleNormal)
 	p2pRoleSeed = PeerRoleFlag(module.RoleSeed)
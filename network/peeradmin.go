@@ -0,0 +1,203 @@
+package network
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+func parsePeerID(s string) (module.PeerID, error) {
+	addr := new(common.Address)
+	if err := addr.SetStringStrict(s); err != nil || addr.IsContract() {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidPeerID(id=%s)", s)
+	}
+	return NewPeerIDFromAddress(addr), nil
+}
+
+// parseBanTarget parses the target of ban which is either a peer ID, an IP
+// address or an IP range in CIDR notation.
+func parseBanTarget(s string) (module.PeerID, *net.IPNet, error) {
+	if _, n, err := net.ParseCIDR(s); err == nil {
+		return nil, n, nil
+	}
+	if ip := net.ParseIP(s); ip != nil {
+		bits := 8 * net.IPv4len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		} else {
+			bits = 8 * net.IPv6len
+		}
+		return nil, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	id, err := parsePeerID(s)
+	if err != nil {
+		return nil, nil, errors.IllegalArgumentError.Errorf("InvalidBanTarget(target=%s)", s)
+	}
+	return id, nil, nil
+}
+
+func parseStaticPeer(s string) (NetAddress, error) {
+	na := NetAddress(s)
+	if err := na.Validate(); err != nil {
+		return "", errors.IllegalArgumentError.Wrapf(err, "InvalidAddress(address=%s)", s)
+	}
+	return na, nil
+}
+
+func adminPeerToMap(p2p *PeerToPeer, p *Peer) map[string]interface{} {
+	m := peerToMap(p, false)
+	m["conn"] = strPeerConnectionType[p.ConnType()]
+	m["rtt"] = p.rtt.String()
+	m["traffic"] = p.traffic.Map()
+	m["static"] = p2p.isStaticPeer(p)
+	return m
+}
+
+// Peers returns connected peers of the chain with connection type, RTT and
+// traffic counters, which is sorted by address.
+func Peers(c module.Chain) ([]map[string]interface{}, error) {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return nil, err
+	}
+	ps := mgr.p2p.findPeers(nil)
+	rarr := make([]map[string]interface{}, len(ps))
+	for i, p := range ps {
+		rarr[i] = adminPeerToMap(mgr.p2p, p)
+	}
+	sort.Slice(rarr, func(i int, j int) bool {
+		return rarr[i]["addr"].(string) < rarr[j]["addr"].(string)
+	})
+	return rarr, nil
+}
+
+// DisconnectPeer closes connections of the peer. The peer may reconnect
+// unless it's banned.
+func DisconnectPeer(c module.Chain, id string) error {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return err
+	}
+	pid, err := parsePeerID(id)
+	if err != nil {
+		return err
+	}
+	ps := mgr.p2p.findPeers(func(p *Peer) bool {
+		return p.ID().Equal(pid)
+	})
+	if len(ps) == 0 {
+		return errors.NotFoundError.Errorf("PeerNotFound(id=%s)", id)
+	}
+	for _, p := range ps {
+		p.Close("disconnected by admin")
+	}
+	return nil
+}
+
+// Bans returns active bans of the chain.
+func Bans(c module.Chain) ([]map[string]interface{}, error) {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.p2p.scorer.banMapArray(), nil
+}
+
+// BanPeer bans the target which is a peer ID, an IP address or an IP range
+// in CIDR notation, and closes matching connections. If d is not positive,
+// it uses the ban duration of the chain.
+func BanPeer(c module.Chain, target string, d time.Duration) error {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return err
+	}
+	id, n, err := parseBanTarget(target)
+	if err != nil {
+		return err
+	}
+	if id != nil {
+		mgr.p2p.scorer.ban(id, d)
+		mgr.p2p.onBan(id)
+	} else {
+		mgr.p2p.scorer.banNet(n, d)
+		mgr.p2p.closePeers(func(p *Peer) bool {
+			return n.Contains(p.RemoteIP())
+		}, ErrBannedPeer)
+	}
+	return nil
+}
+
+// UnbanPeer removes the ban of the target.
+func UnbanPeer(c module.Chain, target string) error {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return err
+	}
+	id, n, err := parseBanTarget(target)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if id != nil {
+		ok = mgr.p2p.scorer.unban(id)
+	} else {
+		ok = mgr.p2p.scorer.unbanNet(n)
+	}
+	if !ok {
+		return errors.NotFoundError.Errorf("BanNotFound(target=%s)", target)
+	}
+	return nil
+}
+
+// StaticPeers returns addresses of static peers of the chain.
+func StaticPeers(c module.Chain) ([]NetAddress, error) {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.p2p.book.staticArray(), nil
+}
+
+// AddStaticPeer adds the address to static peers which are always
+// connected, and dials to it.
+func AddStaticPeer(c module.Chain, address string) error {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return err
+	}
+	na, err := parseStaticPeer(address)
+	if err != nil {
+		return err
+	}
+	if na == mgr.p2p.NetAddress() {
+		return errors.IllegalArgumentError.Errorf("SelfAddress(address=%s)", address)
+	}
+	if !mgr.p2p.book.addStatic(na) {
+		return errors.InvalidStateError.Errorf("AlreadyExists(address=%s)", address)
+	}
+	if mgr.p2p.IsStarted() {
+		mgr.p2p.dialStatics()
+	}
+	return nil
+}
+
+// RemoveStaticPeer removes the address from static peers. The connection
+// is kept, but it may be closed by discovery.
+func RemoveStaticPeer(c module.Chain, address string) error {
+	mgr, err := managerOf(c)
+	if err != nil {
+		return err
+	}
+	na, err := parseStaticPeer(address)
+	if err != nil {
+		return err
+	}
+	if !mgr.p2p.book.removeStatic(na) {
+		return errors.NotFoundError.Errorf("StaticPeerNotFound(address=%s)", address)
+	}
+	return nil
+}
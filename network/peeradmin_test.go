@@ -0,0 +1,44 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseBanTarget(t *testing.T) {
+	id := generatePeerID()
+	pid, n, err := parseBanTarget(id.String())
+	assert.NoError(t, err)
+	assert.Nil(t, n)
+	assert.True(t, id.Equal(pid))
+
+	pid, n, err = parseBanTarget("192.168.0.1")
+	assert.NoError(t, err)
+	assert.Nil(t, pid)
+	assert.Equal(t, "192.168.0.1/32", n.String())
+
+	_, n, err = parseBanTarget("::1")
+	assert.NoError(t, err)
+	assert.Equal(t, "::1/128", n.String())
+
+	_, n, err = parseBanTarget("192.168.0.1/16")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.0.0/16", n.String())
+
+	for _, s := range []string{"", "cx0000000000000000000000000000000000000000", "host:8080", "1.2.3.4/40"} {
+		_, _, err = parseBanTarget(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func Test_parseStaticPeer(t *testing.T) {
+	na, err := parseStaticPeer("127.0.0.1:8080")
+	assert.NoError(t, err)
+	assert.Equal(t, NetAddress("127.0.0.1:8080"), na)
+
+	for _, s := range []string{"", "127.0.0.1", "127.0.0.1:0", "host:port"} {
+		_, err = parseStaticPeer(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,373 @@
+package network
+
+import (
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultPeerBanThreshold  = -100
+	DefaultPeerScoreHalfLife = 10 * time.Minute
+	DefaultPeerBanDuration   = time.Hour
+
+	peerScoreForget = -1
+	keyPeerBans     = "network.bans"
+	keyNetBans      = "network.bans.net"
+)
+
+var offencePenalties = map[module.Offence]float64{
+	module.OffenceMalformedMessage:  25,
+	module.OffenceInvalidMessage:    25,
+	module.OffenceDuplicateMessage:  50,
+	module.OffenceUnexpectedMessage: 10,
+}
+
+type peerScore struct {
+	value float64
+	last  time.Time
+}
+
+// decay applies exponential decay of the score toward zero.
+func (s *peerScore) decay(now time.Time, halfLife time.Duration) {
+	if elapsed := now.Sub(s.last); elapsed > 0 {
+		s.value *= math.Pow(0.5, float64(elapsed)/float64(halfLife))
+		s.last = now
+	}
+}
+
+type peerBan struct {
+	ID    []byte
+	Until int64 // unix time in milliseconds
+}
+
+type netBan struct {
+	Net   string // IP range in CIDR notation
+	Until int64  // unix time in milliseconds
+}
+
+type peerScorer struct {
+	mtx sync.Mutex
+
+	threshold   float64
+	halfLife    time.Duration
+	banDuration time.Duration
+
+	scores  map[string]*peerScore
+	bans    map[string]*peerBan
+	netBans map[string]*netBan
+	nets    map[string]*net.IPNet
+
+	bucket *db.CodedBucket
+	logger log.Logger
+	now    func() time.Time
+
+	// onBan is called with the banned peer outside the lock.
+	onBan func(id module.PeerID)
+}
+
+func newPeerScorer(dbase db.Database, l log.Logger) *peerScorer {
+	s := &peerScorer{
+		threshold:   DefaultPeerBanThreshold,
+		halfLife:    DefaultPeerScoreHalfLife,
+		banDuration: DefaultPeerBanDuration,
+		scores:      make(map[string]*peerScore),
+		bans:        make(map[string]*peerBan),
+		netBans:     make(map[string]*netBan),
+		nets:        make(map[string]*net.IPNet),
+		logger:      l,
+		now:         time.Now,
+	}
+	if dbase != nil {
+		if bk, err := db.NewCodedBucket(dbase, db.ChainProperty, nil); err != nil {
+			l.Warnf("fail to get bucket for peer bans err=%+v", err)
+		} else {
+			s.bucket = bk
+			s.load()
+		}
+	}
+	return s
+}
+
+func (s *peerScorer) load() {
+	var bans []*peerBan
+	if err := s.bucket.Get(db.Raw(keyPeerBans), &bans); err != nil {
+		if !errors.NotFoundError.Equals(err) {
+			s.logger.Warnf("fail to load peer bans err=%+v", err)
+		}
+	}
+	for _, b := range bans {
+		s.bans[NewPeerID(b.ID).String()] = b
+	}
+
+	var netBans []*netBan
+	if err := s.bucket.Get(db.Raw(keyNetBans), &netBans); err != nil {
+		if !errors.NotFoundError.Equals(err) {
+			s.logger.Warnf("fail to load net bans err=%+v", err)
+		}
+	}
+	for _, b := range netBans {
+		if _, n, err := net.ParseCIDR(b.Net); err == nil {
+			s.netBans[n.String()] = b
+			s.nets[n.String()] = n
+		}
+	}
+}
+
+func (s *peerScorer) _save() {
+	if s.bucket == nil {
+		return
+	}
+	bans := make([]*peerBan, 0, len(s.bans))
+	for _, b := range s.bans {
+		bans = append(bans, b)
+	}
+	if err := s.bucket.Set(db.Raw(keyPeerBans), bans); err != nil {
+		s.logger.Warnf("fail to save peer bans err=%+v", err)
+	}
+	netBans := make([]*netBan, 0, len(s.netBans))
+	for _, b := range s.netBans {
+		netBans = append(netBans, b)
+	}
+	if err := s.bucket.Set(db.Raw(keyNetBans), netBans); err != nil {
+		s.logger.Warnf("fail to save net bans err=%+v", err)
+	}
+}
+
+// setConfig sets the threshold which must be negative, the half-life of
+// scores, and the duration of bans. Non-positive values are ignored.
+func (s *peerScorer) setConfig(threshold int, halfLife, banDuration time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if threshold < 0 {
+		s.threshold = float64(threshold)
+	}
+	if halfLife > 0 {
+		s.halfLife = halfLife
+	}
+	if banDuration > 0 {
+		s.banDuration = banDuration
+	}
+}
+
+// report lowers the score of the peer, and it returns true if the peer gets
+// banned by the offence.
+func (s *peerScorer) report(id module.PeerID, o module.Offence) bool {
+	penalty, ok := offencePenalties[o]
+	if !ok {
+		return false
+	}
+
+	s.mtx.Lock()
+	now := s.now()
+	key := id.String()
+	if s._isBanned(key, now) {
+		s.mtx.Unlock()
+		return false
+	}
+	s._sweep(now)
+	ps := s.scores[key]
+	if ps == nil {
+		ps = &peerScore{last: now}
+		s.scores[key] = ps
+	}
+	ps.decay(now, s.halfLife)
+	ps.value -= penalty
+	s.logger.Debugln("report", id, o, ps.value)
+
+	banned := ps.value < s.threshold
+	if banned {
+		s._ban(id, now.Add(s.banDuration))
+		s.logger.Infoln("ban", id, "offence", o, "until", now.Add(s.banDuration))
+	}
+	onBan := s.onBan
+	s.mtx.Unlock()
+
+	if banned && onBan != nil {
+		onBan(id)
+	}
+	return banned
+}
+
+// _sweep removes scores which are decayed enough to be forgotten.
+func (s *peerScorer) _sweep(now time.Time) {
+	for key, ps := range s.scores {
+		ps.decay(now, s.halfLife)
+		if ps.value > peerScoreForget {
+			delete(s.scores, key)
+		}
+	}
+}
+
+func (s *peerScorer) _isBanned(key string, now time.Time) bool {
+	b, ok := s.bans[key]
+	if !ok {
+		return false
+	}
+	if b.Until <= now.UnixMilli() {
+		delete(s.bans, key)
+		s._save()
+		return false
+	}
+	return true
+}
+
+func (s *peerScorer) isBanned(id module.PeerID) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s._isBanned(id.String(), s.now())
+}
+
+func (s *peerScorer) _ban(id module.PeerID, until time.Time) {
+	key := id.String()
+	delete(s.scores, key)
+	s.bans[key] = &peerBan{
+		ID:    id.Bytes(),
+		Until: until.UnixMilli(),
+	}
+	s._save()
+}
+
+// ban bans the peer for the duration. If d is not positive, it uses the
+// configured duration.
+func (s *peerScorer) ban(id module.PeerID, d time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if d <= 0 {
+		d = s.banDuration
+	}
+	s._ban(id, s.now().Add(d))
+}
+
+func (s *peerScorer) unban(id module.PeerID) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key := id.String()
+	if _, ok := s.bans[key]; !ok {
+		return false
+	}
+	delete(s.bans, key)
+	s._save()
+	return true
+}
+
+// banNet bans peers connected from the IP range for the duration. If d is
+// not positive, it uses the configured duration.
+func (s *peerScorer) banNet(n *net.IPNet, d time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if d <= 0 {
+		d = s.banDuration
+	}
+	key := n.String()
+	s.netBans[key] = &netBan{
+		Net:   key,
+		Until: s.now().Add(d).UnixMilli(),
+	}
+	s.nets[key] = n
+	s._save()
+}
+
+func (s *peerScorer) unbanNet(n *net.IPNet) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key := n.String()
+	if _, ok := s.netBans[key]; !ok {
+		return false
+	}
+	delete(s.netBans, key)
+	delete(s.nets, key)
+	s._save()
+	return true
+}
+
+func (s *peerScorer) _isBannedNet(key string, now time.Time) bool {
+	b, ok := s.netBans[key]
+	if !ok {
+		return false
+	}
+	if b.Until <= now.UnixMilli() {
+		delete(s.netBans, key)
+		delete(s.nets, key)
+		s._save()
+		return false
+	}
+	return true
+}
+
+func (s *peerScorer) isBannedIP(ip net.IP) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	for key, n := range s.nets {
+		if n.Contains(ip) && s._isBannedNet(key, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// banMapArray returns the list of active bans with "id" or "net", and
+// "until" in RFC3339 format, which is sorted by id and net.
+func (s *peerScorer) banMapArray() []map[string]interface{} {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	keyOf := func(m map[string]interface{}) string {
+		if id, ok := m["id"]; ok {
+			return id.(string)
+		}
+		return m["net"].(string)
+	}
+	rarr := make([]map[string]interface{}, 0, len(s.bans)+len(s.netBans))
+	for key, b := range s.bans {
+		if !s._isBanned(key, now) {
+			continue
+		}
+		rarr = append(rarr, map[string]interface{}{
+			"id":    key,
+			"until": time.UnixMilli(b.Until).UTC().Format(time.RFC3339),
+		})
+	}
+	for key, b := range s.netBans {
+		if !s._isBannedNet(key, now) {
+			continue
+		}
+		rarr = append(rarr, map[string]interface{}{
+			"net":   key,
+			"until": time.UnixMilli(b.Until).UTC().Format(time.RFC3339),
+		})
+	}
+	sort.Slice(rarr, func(i int, j int) bool {
+		return keyOf(rarr[i]) < keyOf(rarr[j])
+	})
+	return rarr
+}
+
+// scoreMap returns scores of peers which are not forgotten yet.
+func (s *peerScorer) scoreMap() map[string]interface{} {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s._sweep(s.now())
+	m := make(map[string]interface{})
+	for key, ps := range s.scores {
+		m[key] = math.Round(ps.value*100) / 100
+	}
+	return m
+}
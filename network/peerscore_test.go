@@ -0,0 +1,113 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func Test_peerScorer(t *testing.T) {
+	dbase := db.NewMapDB()
+	now := time.Unix(1000, 0)
+	s := newPeerScorer(dbase, log.New())
+	s.now = func() time.Time { return now }
+	s.setConfig(-30, time.Minute, time.Hour)
+
+	var banned []module.PeerID
+	s.onBan = func(id module.PeerID) {
+		banned = append(banned, id)
+	}
+
+	id := generatePeerID()
+	assert.False(t, s.report(id, module.OffenceUnexpectedMessage))
+	assert.False(t, s.report(id, module.OffenceUnexpectedMessage))
+	assert.Equal(t, float64(-20), s.scoreMap()[id.String()])
+
+	// penalty is halved after the half-life
+	now = now.Add(time.Minute)
+	assert.Equal(t, float64(-10), s.scoreMap()[id.String()])
+	assert.False(t, s.report(id, module.OffenceUnexpectedMessage))
+	assert.False(t, s.isBanned(id))
+	assert.Empty(t, banned)
+
+	assert.True(t, s.report(id, module.OffenceInvalidMessage))
+	assert.True(t, s.isBanned(id))
+	assert.Equal(t, []module.PeerID{id}, banned)
+	assert.NotContains(t, s.scoreMap(), id.String())
+	assert.False(t, s.report(id, module.OffenceInvalidMessage))
+	assert.Len(t, banned, 1)
+
+	bans := s.banMapArray()
+	assert.Len(t, bans, 1)
+	assert.Equal(t, id.String(), bans[0]["id"])
+	assert.Equal(t, "1970-01-01T01:17:40Z", bans[0]["until"])
+
+	// bans survive restarts
+	s2 := newPeerScorer(dbase, log.New())
+	s2.now = func() time.Time { return now }
+	assert.True(t, s2.isBanned(id))
+	assert.False(t, s2.isBanned(generatePeerID()))
+
+	// and expire after the duration
+	now = now.Add(time.Hour)
+	assert.False(t, s2.isBanned(id))
+	assert.Empty(t, s2.banMapArray())
+
+	s3 := newPeerScorer(dbase, log.New())
+	assert.Empty(t, s3.banMapArray())
+}
+
+func Test_peerScorer_forget(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newPeerScorer(nil, log.New())
+	s.now = func() time.Time { return now }
+
+	id := generatePeerID()
+	s.report(id, module.OffenceDuplicateMessage)
+	assert.Contains(t, s.scoreMap(), id.String())
+
+	now = now.Add(DefaultPeerScoreHalfLife * 6)
+	assert.Empty(t, s.scoreMap())
+}
+
+func Test_peerScorer_manual(t *testing.T) {
+	dbase := db.NewMapDB()
+	now := time.Unix(1000, 0)
+	s := newPeerScorer(dbase, log.New())
+	s.now = func() time.Time { return now }
+
+	id := generatePeerID()
+	s.ban(id, 0)
+	assert.True(t, s.isBanned(id))
+	assert.True(t, s.unban(id))
+	assert.False(t, s.isBanned(id))
+	assert.False(t, s.unban(id))
+
+	_, n, _ := net.ParseCIDR("10.0.0.0/24")
+	s.banNet(n, time.Minute)
+	assert.True(t, s.isBannedIP(net.ParseIP("10.0.0.7")))
+	assert.False(t, s.isBannedIP(net.ParseIP("10.0.1.7")))
+	assert.False(t, s.isBannedIP(nil))
+
+	bans := s.banMapArray()
+	assert.Len(t, bans, 1)
+	assert.Equal(t, "10.0.0.0/24", bans[0]["net"])
+
+	// bans of IP range survive restarts
+	s2 := newPeerScorer(dbase, log.New())
+	s2.now = func() time.Time { return now }
+	assert.True(t, s2.isBannedIP(net.ParseIP("10.0.0.7")))
+
+	now = now.Add(time.Minute)
+	assert.False(t, s2.isBannedIP(net.ParseIP("10.0.0.7")))
+
+	assert.True(t, s.unbanNet(n))
+	assert.False(t, s.unbanNet(n))
+	assert.Empty(t, s.banMapArray())
+}
@@ -16,6 +16,7 @@ type protocolHandler struct {
 	name         string
 	priority     uint8
 	policy       module.NotRegisteredProtocolPolicy
+	stream       bool
 	receiveQueue Queue
 	eventQueue   Queue
 	//log
@@ -99,6 +100,12 @@ func (ph *protocolHandler) getPriority() uint8 {
 	return ph.priority
 }
 
+//setStream makes packets of the protocol be sent through its own stream
+//if the connection supports it.
+func (ph *protocolHandler) setStream(stream bool) {
+	ph.stream = stream
+}
+
 func (ph *protocolHandler) getPolicy() module.NotRegisteredProtocolPolicy {
 	return ph.policy
 }
@@ -136,6 +143,7 @@ Loop:
 				}
 				pkt := ctx.Value(p2pContextKeyPacket).(*Packet)
 				p := ctx.Value(p2pContextKeyPeer).(*Peer)
+				ph.m.capture.capture(CaptureIn, pkt, p.ID())
 				r := ph.getReactor()
 				isRelay, _ := r.OnReceive(pkt.subProtocol, pkt.payload, p.ID())
 				if isRelay && pkt.ttl == byte(module.BroadcastAll) && pkt.dest != p2pDestPeer {
@@ -241,6 +249,8 @@ func (ph *protocolHandler) send(
 	pkt.ttl = ttl
 	pkt.destPeer = destPeer
 	pkt.forceSend = forceSend
+	pkt.stream = ph.stream
+	ph.m.capture.capture(CaptureOut, pkt, destPeer)
 	return ph.m.send(pkt)
 }
 
@@ -271,3 +281,7 @@ func (ph *protocolHandler) Broadcast(pi module.ProtocolInfo, b []byte, bt module
 func (ph *protocolHandler) GetPeers() []module.PeerID {
 	return ph.m.getPeersByProtocol(ph.protocol)
 }
+
+func (ph *protocolHandler) ReportPeer(id module.PeerID, offence module.Offence) {
+	ph.m.p2p.scorer.report(id, offence)
+}
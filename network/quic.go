@@ -0,0 +1,25 @@
+package network
+
+import (
+	"net"
+	"strings"
+)
+
+// QUICScheme is the scheme of the listen address for listening
+// QUIC on UDP in addition to TCP with the same port. ex) quic://0.0.0.0:8080
+// It's supported only for the binaries built with quic tag.
+const QUICScheme = "quic://"
+
+func parseListenAddress(address string) (string, bool) {
+	if strings.HasPrefix(address, QUICScheme) {
+		return address[len(QUICScheme):], true
+	}
+	return address, false
+}
+
+// streamMuxer is implemented by the connection which is able to open
+// additional streams to the peer.
+type streamMuxer interface {
+	openStream() (net.Conn, error)
+	acceptStream() (net.Conn, error)
+}
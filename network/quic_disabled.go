@@ -0,0 +1,28 @@
+//go:build !quic
+// +build !quic
+
+package network
+
+import (
+	"net"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+func listenQUIC(tcp net.Listener, address string, l log.Logger) (net.Listener, error) {
+	return nil, errors.UnsupportedError.Errorf(
+		"QUICNotSupported(address=%s,build with quic tag)", address)
+}
+
+// quicDialer dials TCP only for the binaries built without quic tag.
+type quicDialer struct{}
+
+func newQUICDialer() *quicDialer {
+	return &quicDialer{}
+}
+
+func (d *quicDialer) dial(network, address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout(network, address, timeout)
+}
@@ -0,0 +1,146 @@
+//go:build quic
+// +build quic
+
+package network
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/metric"
+)
+
+func newTestQUICListener(t *testing.T) (*Listener, chan net.Conn) {
+	ch := make(chan net.Conn, 2)
+	l := newListener(QUICScheme+getAvailableLocalhostAddress(t), func(conn net.Conn) {
+		ch <- conn
+	}, testLogger())
+	assert.NoError(t, l.Listen())
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+	return l, ch
+}
+
+func waitConn(t *testing.T, ch chan net.Conn) net.Conn {
+	select {
+	case conn := <-ch:
+		return conn
+	case <-time.After(DefaultQUICHandshakeTimeout):
+		assert.FailNow(t, "timeout")
+		return nil
+	}
+}
+
+func Test_quic_Listener(t *testing.T) {
+	l, ch := newTestQUICListener(t)
+	assert.True(t, l.useQUIC())
+	address, ok := parseListenAddress(l.Address())
+	assert.True(t, ok)
+
+	qd := newQUICDialer()
+	conn, err := qd.dialQUIC(address, DefaultQUICDialTimeout)
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("quic"))
+	assert.NoError(t, err)
+	aconn := waitConn(t, ch)
+	assert.IsType(t, &quicConn{}, aconn)
+	b := make([]byte, 4)
+	_, err = io.ReadFull(aconn, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("quic"), b)
+
+	tconn, err := net.Dial(DefaultTransportNet, address)
+	assert.NoError(t, err)
+	defer tconn.Close()
+	_, err = tconn.Write([]byte("tcp"))
+	assert.NoError(t, err)
+	aconn = waitConn(t, ch)
+	assert.IsType(t, &net.TCPConn{}, aconn)
+	b = make([]byte, 3)
+	_, err = io.ReadFull(aconn, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("tcp"), b)
+}
+
+func Test_quic_quicDialer_fallback(t *testing.T) {
+	ln, err := net.Listen(DefaultTransportNet, "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+	address := ln.Addr().String()
+
+	qd := newQUICDialer()
+	conn, err := qd.dial(DefaultTransportNet, address, DefaultDialTimeout)
+	assert.NoError(t, err)
+	defer conn.Close()
+	assert.IsType(t, &net.TCPConn{}, conn)
+	assert.True(t, qd.isFailed(address))
+
+	// redial uses TCP without waiting for QUIC
+	start := time.Now()
+	conn2, err := qd.dial(DefaultTransportNet, address, DefaultDialTimeout)
+	assert.NoError(t, err)
+	defer conn2.Close()
+	assert.IsType(t, &net.TCPConn{}, conn2)
+	assert.Less(t, time.Since(start), DefaultQUICDialTimeout)
+
+	// QUIC is checked only once for an interval
+	assert.False(t, qd.needProbe(address))
+	qd.failed[address] = time.Now().Add(-DefaultQUICRetryInterval)
+	assert.True(t, qd.needProbe(address))
+	assert.False(t, qd.needProbe(address))
+}
+
+func Test_quic_Peer_streams(t *testing.T) {
+	l, ch := newTestQUICListener(t)
+	address, _ := parseListenAddress(l.Address())
+	conn, err := newQUICDialer().dialQUIC(address, DefaultQUICDialTimeout)
+	assert.NoError(t, err)
+
+	p1 := newPeer(conn, false, "", testLogger())
+	p1.setID(generatePeerID())
+	p1.setMetric(metric.NewNetworkMetric(metric.DefaultMetricContext()))
+	p1.setPacketCbFunc(func(pkt *Packet, p *Peer) {})
+	defer p1.Close("done")
+	//stream is notified to the listener after writing
+	src := generatePeerID()
+	mpkt := NewPacket(ProtoTestNetwork, ProtoTestNetworkRequest, []byte("main"))
+	mpkt.src = src
+	assert.NoError(t, p1.sendPacket(mpkt))
+
+	p2 := newPeer(waitConn(t, ch), true, "", testLogger())
+	p2.setID(generatePeerID())
+	p2.setMetric(metric.NewNetworkMetric(metric.DefaultMetricContext()))
+	recv := make(chan *Packet, 2)
+	p2.setPacketCbFunc(func(pkt *Packet, p *Peer) {
+		recv <- pkt
+	})
+	defer p2.Close("done")
+	assert.True(t, p1.enableStreams())
+	assert.True(t, p2.enableStreams())
+
+	pkt := <-recv
+	assert.Equal(t, []byte("main"), pkt.payload)
+
+	spkt := NewPacket(ProtoTestNetwork, ProtoTestNetworkRequest, []byte("stream"))
+	spkt.src = src
+	spkt.stream = true
+	assert.NoError(t, p1.sendPacket(spkt))
+	select {
+	case pkt = <-recv:
+		assert.Equal(t, []byte("stream"), pkt.payload)
+	case <-time.After(DefaultSendTimeout):
+		assert.FailNow(t, "timeout")
+	}
+	assert.NotNil(t, p1.streams[ProtoTestNetwork.Uint16()])
+
+	//wrapped connection doesn't support streams
+	p3, _ := newPeerWithFakeConn(false)
+	assert.False(t, p3.enableStreams())
+	assert.Nil(t, p3.streamFor(spkt))
+}
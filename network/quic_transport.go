@@ -0,0 +1,318 @@
+//go:build quic
+// +build quic
+
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	// DefaultQUICNet follows the address family of the listen address,
+	// so IPv6 addresses can be used for QUIC.
+	DefaultQUICNet              = "udp"
+	DefaultQUICALPN             = "goloop-p2p"
+	DefaultQUICDialTimeout      = 2 * time.Second
+	DefaultQUICHandshakeTimeout = 5 * time.Second
+	DefaultQUICRetryInterval    = 5 * time.Minute
+	DefaultQUICKeepAlivePeriod  = 15 * time.Second
+	DefaultQUICMaxStreams       = 64
+)
+
+func newQUICConfig() *quic.Config {
+	return &quic.Config{
+		HandshakeIdleTimeout: DefaultQUICHandshakeTimeout,
+		KeepAlivePeriod:      DefaultQUICKeepAlivePeriod,
+		MaxIncomingStreams:   DefaultQUICMaxStreams,
+	}
+}
+
+// newQUICServerTLSConfig returns TLS configuration with self-signed certificate.
+// QUIC requires TLS, but identity of the peer is verified by Authenticator
+// as it does for TCP connection.
+func newQUICServerTLSConfig() (*tls.Config, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano()),
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(10 * 365 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		NextProtos:   []string{DefaultQUICALPN},
+	}, nil
+}
+
+func newQUICClientTLSConfig() *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{DefaultQUICALPN},
+	}
+}
+
+// quicConn is net.Conn for the first stream of QUIC connection,
+// closing it closes the connection with all of its streams.
+type quicConn struct {
+	quic.Stream
+	conn quic.Connection
+}
+
+func (c *quicConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *quicConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *quicConn) Close() error {
+	return c.conn.CloseWithError(0, "")
+}
+
+func (c *quicConn) openStream() (net.Conn, error) {
+	s, err := c.conn.OpenStream()
+	if err != nil {
+		return nil, err
+	}
+	return &quicStream{Stream: s, conn: c.conn}, nil
+}
+
+func (c *quicConn) acceptStream() (net.Conn, error) {
+	s, err := c.conn.AcceptStream(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &quicStream{Stream: s, conn: c.conn}, nil
+}
+
+// quicStream is net.Conn for the additional stream of QUIC connection.
+type quicStream struct {
+	quic.Stream
+	conn quic.Connection
+}
+
+func (s *quicStream) LocalAddr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+func (s *quicStream) RemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
+
+func (s *quicStream) Close() error {
+	s.Stream.CancelRead(0)
+	return s.Stream.Close()
+}
+
+// quicListener accepts connections from TCP listener and QUIC listener.
+type quicListener struct {
+	tcp   net.Listener
+	pconn net.PacketConn
+	ln    *quic.Listener
+	ch    chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+	//log
+	logger log.Logger
+}
+
+func listenQUIC(tcp net.Listener, address string, l log.Logger) (net.Listener, error) {
+	tlsConfig, err := newQUICServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	pconn, err := net.ListenPacket(DefaultQUICNet, address)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := quic.Listen(pconn, tlsConfig, newQUICConfig())
+	if err != nil {
+		_ = pconn.Close()
+		return nil, err
+	}
+	ql := &quicListener{
+		tcp:    tcp,
+		pconn:  pconn,
+		ln:     ln,
+		ch:     make(chan net.Conn),
+		done:   make(chan struct{}),
+		logger: l,
+	}
+	go ql.acceptTCPRoutine()
+	go ql.acceptQUICRoutine()
+	return ql, nil
+}
+
+func (l *quicListener) push(conn net.Conn) {
+	select {
+	case l.ch <- conn:
+	case <-l.done:
+		_ = conn.Close()
+	}
+}
+
+func (l *quicListener) acceptTCPRoutine() {
+	defer l.Close()
+	for {
+		conn, err := l.tcp.Accept()
+		if err != nil {
+			l.logger.Infoln("acceptTCPRoutine", err)
+			return
+		}
+		l.push(conn)
+	}
+}
+
+func (l *quicListener) acceptQUICRoutine() {
+	defer l.Close()
+	for {
+		conn, err := l.ln.Accept(context.Background())
+		if err != nil {
+			l.logger.Infoln("acceptQUICRoutine", err)
+			return
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultQUICHandshakeTimeout)
+			defer cancel()
+			s, err := conn.AcceptStream(ctx)
+			if err != nil {
+				l.logger.Debugln("acceptQUICRoutine", "fail to accept stream", conn.RemoteAddr(), err)
+				_ = conn.CloseWithError(0, "")
+				return
+			}
+			l.push(&quicConn{Stream: s, conn: conn})
+		}()
+	}
+}
+
+func (l *quicListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.ch:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *quicListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		close(l.done)
+		err = l.tcp.Close()
+		if qerr := l.ln.Close(); err == nil {
+			err = qerr
+		}
+		if perr := l.pconn.Close(); err == nil {
+			err = perr
+		}
+	})
+	return err
+}
+
+func (l *quicListener) Addr() net.Addr {
+	return l.tcp.Addr()
+}
+
+// quicDialer tries QUIC first, and falls back to TCP for the address
+// which is not reachable with QUIC. Once it falls back, it dials TCP directly
+// for the address, and checks QUIC in background every DefaultQUICRetryInterval
+// so that redials don't wait for DefaultQUICDialTimeout.
+type quicDialer struct {
+	failed map[string]time.Time
+	mtx    sync.Mutex
+}
+
+func newQUICDialer() *quicDialer {
+	return &quicDialer{failed: make(map[string]time.Time)}
+}
+
+func (d *quicDialer) isFailed(address string) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	_, ok := d.failed[address]
+	return ok
+}
+
+// needProbe returns true if QUIC for the failed address needs to be checked
+// again, and it marks the address to prevent concurrent checks.
+func (d *quicDialer) needProbe(address string) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if t, ok := d.failed[address]; ok && time.Since(t) >= DefaultQUICRetryInterval {
+		d.failed[address] = time.Now()
+		return true
+	}
+	return false
+}
+
+func (d *quicDialer) setFailed(address string) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.failed[address] = time.Now()
+}
+
+func (d *quicDialer) clearFailed(address string) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	delete(d.failed, address)
+}
+
+func (d *quicDialer) probe(address string) {
+	conn, err := d.dialQUIC(address, DefaultQUICDialTimeout)
+	if err != nil {
+		d.setFailed(address)
+		return
+	}
+	_ = conn.Close()
+	d.clearFailed(address)
+}
+
+func (d *quicDialer) dialQUIC(address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := quic.DialAddr(ctx, address, newQUICClientTLSConfig(), newQUICConfig())
+	if err != nil {
+		return nil, err
+	}
+	//stream is notified to the listener when the first data is written,
+	//which is done by the dialer side for authentication.
+	s, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		_ = conn.CloseWithError(0, "")
+		return nil, err
+	}
+	return &quicConn{Stream: s, conn: conn}, nil
+}
+
+func (d *quicDialer) dial(network, address string, timeout time.Duration) (net.Conn, error) {
+	if !d.isFailed(address) {
+		if conn, err := d.dialQUIC(address, DefaultQUICDialTimeout); err == nil {
+			return conn, nil
+		}
+		d.setFailed(address)
+	} else if d.needProbe(address) {
+		go d.probe(address)
+	}
+	return net.DialTimeout(network, address, timeout)
+}
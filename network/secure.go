@@ -164,6 +164,7 @@ type secureKey struct {
 	secret       [][]byte
 	extra        []byte
 	sa           SecureAeadSuite
+	noise        *noiseHandshake
 	keyLogWriter io.Writer
 }
 
@@ -361,6 +362,7 @@ const (
 	SecureSuiteNone
 	SecureSuiteTls
 	SecureSuiteEcdhe
+	SecureSuiteNoise
 )
 
 func (s SecureSuite) String() string {
@@ -371,6 +373,8 @@ func (s SecureSuite) String() string {
 		return "tls"
 	case SecureSuiteEcdhe:
 		return "ecdhe"
+	case SecureSuiteNoise:
+		return "noise"
 	default:
 		return "unknown"
 	}
@@ -384,6 +388,8 @@ func SecureSuiteFromString(s string) SecureSuite {
 		return SecureSuiteTls
 	case "ecdhe":
 		return SecureSuiteEcdhe
+	case "noise":
+		return SecureSuiteNoise
 	default:
 		return SecureSuiteUnknown
 	}
@@ -158,6 +158,10 @@ func (r *streamReactor) GetPeers() []module.PeerID {
 	return r.ph.GetPeers()
 }
 
+func (r *streamReactor) ReportPeer(id module.PeerID, offence module.Offence) {
+	r.ph.ReportPeer(id, offence)
+}
+
 func newStream(r *streamReactor, id module.PeerID) *stream {
 	return &stream{
 		r:  r,
@@ -356,6 +360,9 @@ func (m *manager) RegisterReactorForStreams(name string, pi module.ProtocolInfo,
 	if err != nil {
 		return r, err
 	}
+	if ph, ok := r.ph.(*protocolHandler); ok {
+		ph.setStream(true)
+	}
 	m.streamReactors = append(m.streamReactors, r)
 	return r, nil
 }
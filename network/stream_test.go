@@ -141,6 +141,9 @@ func (ph *tProtocolHandler) GetPeers() []module.PeerID {
 	return ph.nm.GetPeers()
 }
 
+func (ph *tProtocolHandler) ReportPeer(id module.PeerID, offence module.Offence) {
+}
+
 func createAPeerID() module.PeerID {
 	return NewPeerIDFromAddress(wallet.New().Address())
 }
@@ -5,6 +5,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -19,10 +20,16 @@ type transport struct {
 	cn      *ChannelNegotiator
 	pd      *PeerDispatcher
 	dMap    map[string]*Dialer
+	dial    dialFunc
+	qd      *quicDialer
 	logger  log.Logger
 }
 
 func NewTransport(address string, w module.Wallet, l log.Logger) module.NetworkTransport {
+	return newTransport(address, w, l)
+}
+
+func newTransport(address string, w module.Wallet, l log.Logger) *transport {
 	na := NetAddress(address)
 	if err := na.Validate(); err != nil {
 		l.Panicf("invalid P2P Address err:%+v", err)
@@ -41,6 +48,7 @@ func NewTransport(address string, w module.Wallet, l log.Logger) module.NetworkT
 		cn:      cn,
 		pd:      pd,
 		dMap:    make(map[string]*Dialer),
+		qd:      newQUICDialer(),
 		logger:  transportLogger,
 	}
 	return t
@@ -79,11 +87,23 @@ func (t *transport) GetDialer(channel string) *Dialer {
 	d, ok := t.dMap[channel]
 	if !ok {
 		d = newDialer(channel, t.pd.onConnect)
+		if t.dial != nil {
+			d.dial = t.dial
+		} else {
+			d.dial = t.dialDefault
+		}
 		t.dMap[channel] = d
 	}
 	return d
 }
 
+func (t *transport) dialDefault(network, address string, timeout time.Duration) (net.Conn, error) {
+	if t.l.useQUIC() {
+		return t.qd.dial(network, address, timeout)
+	}
+	return net.DialTimeout(network, address, timeout)
+}
+
 func (t *transport) SetSecureSuites(channel string, secureSuites string) error {
 	if secureSuites == "" {
 		return t.a.SetSecureSuites(channel, nil)
@@ -155,6 +175,7 @@ func (t *transport) unregisterPeerHandler(channel string) {
 type Listener struct {
 	address  string
 	ln       net.Listener
+	listen   listenFunc
 	mtx      sync.Mutex
 	closeCh  chan bool
 	onAccept acceptCbFunc
@@ -163,10 +184,12 @@ type Listener struct {
 }
 
 type acceptCbFunc func(conn net.Conn)
+type listenFunc func(network, address string) (net.Listener, error)
 
 func newListener(address string, cbFunc acceptCbFunc, l log.Logger) *Listener {
 	return &Listener{
 		address:  address,
+		listen:   net.Listen,
 		onAccept: cbFunc,
 		logger:   l.WithFields(log.Fields{LoggerFieldKeySubModule: "listener"}),
 	}
@@ -176,8 +199,19 @@ func (l *Listener) Address() string {
 	if l.ln == nil {
 		return l.address
 	}
-	la := l.ln.Addr()
-	return la.String()
+	la := l.ln.Addr().String()
+	if _, ok := parseListenAddress(l.address); ok {
+		return QUICScheme + la
+	}
+	return la
+}
+
+func (l *Listener) useQUIC() bool {
+	defer l.mtx.Unlock()
+	l.mtx.Lock()
+
+	_, ok := parseListenAddress(l.address)
+	return ok
 }
 
 func (l *Listener) SetAddress(address string) error {
@@ -199,10 +233,19 @@ func (l *Listener) Listen() error {
 	if l.ln != nil {
 		return ErrAlreadyListened
 	}
-	ln, err := net.Listen(DefaultTransportNet, l.address)
+	address, useQUIC := parseListenAddress(l.address)
+	ln, err := l.listen(DefaultTransportNet, address)
 	if err != nil {
 		return err
 	}
+	if useQUIC {
+		qln, err := listenQUIC(ln, ln.Addr().String(), l.logger)
+		if err != nil {
+			_ = ln.Close()
+			return err
+		}
+		ln = qln
+	}
 	l.ln = ln
 	l.closeCh = make(chan bool)
 	go l.acceptRoutine()
@@ -243,15 +286,18 @@ type Dialer struct {
 	onConnect connectCbFunc
 	channel   string
 	dialing   *Set
+	dial      dialFunc
 }
 
 type connectCbFunc func(conn net.Conn, addr, channel string)
+type dialFunc func(network, address string, timeout time.Duration) (net.Conn, error)
 
 func newDialer(channel string, cbFunc connectCbFunc) *Dialer {
 	return &Dialer{
 		onConnect: cbFunc,
 		channel:   channel,
 		dialing:   NewSet(),
+		dial:      net.DialTimeout,
 	}
 }
 
@@ -259,7 +305,7 @@ func (d *Dialer) Dial(addr string) error {
 	if !d.dialing.Add(addr) {
 		return ErrAlreadyDialing
 	}
-	conn, err := net.DialTimeout(DefaultTransportNet, addr, DefaultDialTimeout)
+	conn, err := d.dial(DefaultTransportNet, addr, DefaultDialTimeout)
 	_ = d.dialing.Remove(addr)
 	if err != nil {
 		return err
@@ -77,6 +77,9 @@ func (ph *testTransportPeerHandler) onPeer(p *Peer) {
 				cs = tls.TLS_CHACHA20_POLY1305_SHA256
 			}
 			assert.Equal(ph.t, cs, c.ConnectionState().CipherSuite)
+		case *NoiseConn:
+			assert.Equal(ph.t, ph.expectedSecureSuite, SecureSuite(SecureSuiteNoise))
+			assert.Equal(ph.t, ph.expectedSecureAeadSuite, p.secureKey.sa)
 		default:
 			assert.Equal(ph.t, ph.expectedSecureSuite, SecureSuite(SecureSuiteNone))
 			assert.Equal(ph.t, SecureAeadSuite(SecureAeadSuiteNone), p.secureKey.sa)
@@ -164,6 +167,7 @@ func Test_transport(t *testing.T) {
 		SecureSuiteNone,
 		SecureSuiteTls,
 		SecureSuiteEcdhe,
+		SecureSuiteNoise,
 	}
 	sas := []SecureAeadSuite{
 		SecureAeadSuiteChaCha20Poly1305,
@@ -222,8 +226,12 @@ func Test_transport(t *testing.T) {
 	//enable secureKeyLogWriter
 	DefaultSecureKeyLogWriter = &testKeyLogWriter{}
 	d := nt2.GetDialer(testChannel)
+	assert.NoError(t, nt2.SetSecureSuites(testChannel, sliceToString(sss)))
 	for _, ss := range sss {
 		for _, sa := range sas {
+			if _, ok := noiseCipherFunc(sa); ss == SecureSuiteNoise && !ok {
+				continue
+			}
 			t.Log("SecureSuite:", ss, "SecureAeadSuite:", sa)
 
 			strSS := sliceToString([]SecureSuite{ss})
@@ -232,6 +240,10 @@ func Test_transport(t *testing.T) {
 			strSA := sliceToString([]SecureAeadSuite{sa})
 			assert.NoError(t, nt1.SetSecureAeads(testChannel, strSA))
 			assert.Equal(t, strSA, nt1.GetSecureAeads(testChannel))
+			if ss == SecureSuiteNoise {
+				//AEAD for noise is decided by the requester
+				assert.NoError(t, nt2.SetSecureAeads(testChannel, strSA))
+			}
 
 			tph1.expectedSecureSuite = ss
 			tph1.expectedSecureAeadSuite = sa
@@ -32,6 +32,8 @@ type StaticConfig struct {
 	AuthSkipIfEmptyUsers bool `json:"auth_skip_if_empty_users,omitempty"`
 	NIDForP2P            bool `json:"nid_for_p2p,omitempty"`
 
+	RPCTrustedProxies string `json:"rpc_trusted_proxies,omitempty"`
+
 	BaseDir  string `json:"node_dir"`
 	FilePath string `json:"-"` // absolute path
 
@@ -107,12 +109,16 @@ const (
 )
 
 type RuntimeConfig struct {
-	EEInstances       int    `json:"eeInstances"`
-	RPCDefaultChannel string `json:"rpcDefaultChannel"`
-	RPCIncludeDebug   bool   `json:"rpcIncludeDebug"`
-	RPCRosetta        bool   `json:"rpcRosetta"`
-	RPCBatchLimit     int    `json:"rpcBatchLimit"`
-	WSMaxSession      int    `json:"wsMaxSession"`
+	EEInstances        int    `json:"eeInstances"`
+	RPCDefaultChannel  string `json:"rpcDefaultChannel"`
+	RPCIncludeDebug    bool   `json:"rpcIncludeDebug"`
+	RPCRosetta         bool   `json:"rpcRosetta"`
+	RPCGraphQL         bool   `json:"rpcGraphQL"`
+	RPCBatchLimit      int    `json:"rpcBatchLimit"`
+	WSMaxSession       int    `json:"wsMaxSession"`
+	RPCRateLimit       int    `json:"rpcRateLimit"`
+	RPCMethodRateLimit string `json:"rpcMethodRateLimit"`
+	RPCCallConcurrency int    `json:"rpcCallConcurrency"`
 
 	FilePath string `json:"-"` // absolute path
 }
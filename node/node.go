@@ -15,6 +15,7 @@ import (
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/chain/pruning"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -391,29 +392,38 @@ func (n *Node) JoinChain(
 	cfgFile, _ := filepath.Abs(path.Join(chainDir, ChainConfigFileName))
 
 	cfg := &chain.Config{
-		NID:              nid,
-		DBType:           p.DBType,
-		Platform:         p.Platform,
-		Channel:          channel,
-		SecureSuites:     p.SecureSuites,
-		SecureAeads:      p.SecureAeads,
-		SeedAddr:         p.SeedAddr,
-		Role:             p.Role,
-		GenesisStorage:   genesisStorage,
-		ConcurrencyLevel: p.ConcurrencyLevel,
-		NormalTxPoolSize: p.NormalTxPoolSize,
-		PatchTxPoolSize:  p.PatchTxPoolSize,
-		MaxBlockTxBytes:  p.MaxBlockTxBytes,
-		NodeCache:        p.NodeCache,
-		DefWaitTimeout:   p.DefWaitTimeout,
-		MaxWaitTimeout:   p.MaxWaitTimeout,
-		TxTimeout:        p.TxTimeout,
-		AutoStart:        p.AutoStart,
-		FilePath:         cfgFile,
-		NIDForP2P:        n.cfg.NIDForP2P,
-		ChildrenLimit:    p.ChildrenLimit,
-		NephewsLimit:     p.NephewsLimit,
-		ValidateTxOnSend: p.ValidateTxOnSend,
+		NID:               nid,
+		DBType:            p.DBType,
+		Platform:          p.Platform,
+		Channel:           channel,
+		SecureSuites:      p.SecureSuites,
+		SecureAeads:       p.SecureAeads,
+		SeedAddr:          p.SeedAddr,
+		Role:              p.Role,
+		GenesisStorage:    genesisStorage,
+		ConcurrencyLevel:  p.ConcurrencyLevel,
+		NormalTxPoolSize:  p.NormalTxPoolSize,
+		PatchTxPoolSize:   p.PatchTxPoolSize,
+		MaxBlockTxBytes:   p.MaxBlockTxBytes,
+		NodeCache:         p.NodeCache,
+		DefWaitTimeout:    p.DefWaitTimeout,
+		MaxWaitTimeout:    p.MaxWaitTimeout,
+		TxTimeout:         p.TxTimeout,
+		AutoStart:         p.AutoStart,
+		FilePath:          cfgFile,
+		NIDForP2P:         n.cfg.NIDForP2P,
+		ChildrenLimit:     p.ChildrenLimit,
+		NephewsLimit:      p.NephewsLimit,
+		PeerBanThreshold:  p.PeerBanThreshold,
+		PeerScoreHalfLife: p.PeerScoreHalfLife,
+		PeerBanDuration:   p.PeerBanDuration,
+		BandwidthLimits:   p.BandwidthLimits,
+		PacketCapture:     p.PacketCapture,
+		ValidateTxOnSend:  p.ValidateTxOnSend,
+		LogIndex:          p.LogIndex,
+		TxIndex:           p.TxIndex,
+		PruningWindow:     p.PruningWindow,
+		PruningCheckpoint: p.PruningCheckpoint,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -657,6 +667,11 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			}
 			pr := network.PeerRoleFlag(c.cfg.Role)
 			c.NetworkManager().SetInitialRoles(pr.ToRoles()...)
+		case "bandwidthLimits":
+			if err := c.NetworkManager().SetBandwidthLimits(value); err != nil {
+				return err
+			}
+			c.cfg.BandwidthLimits = value
 		case "autoStart":
 			if as, err := strconv.ParseBool(value); err != nil {
 				return err
@@ -761,12 +776,65 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.NephewsLimit = &intVal
 			}
+		case "peerBanThreshold":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.PeerBanThreshold = &intVal
+			}
+		case "peerScoreHalfLife":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.PeerScoreHalfLife = intVal
+			}
+		case "peerBanDuration":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.PeerBanDuration = intVal
+			}
+		case "bandwidthLimits":
+			if _, err := network.ParseBandwidthLimits(value); err != nil {
+				return err
+			}
+			c.cfg.BandwidthLimits = value
+		case "packetCapture":
+			c.cfg.PacketCapture = value
 		case "validateTxOnSend":
 			if bc, err := strconv.ParseBool(value); err != nil {
 				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
 			} else {
 				c.cfg.ValidateTxOnSend = bc
 			}
+		case "logIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.LogIndex = bc
+			}
+		case "txIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.TxIndex = bc
+			}
+		case "pruningWindow":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else if intVal != 0 && intVal < pruning.MinWindow {
+				return errors.Errorf("invalid pruning window %d (min=%d)", intVal, pruning.MinWindow)
+			} else {
+				c.cfg.PruningWindow = intVal
+			}
+		case "pruningCheckpoint":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else if intVal < 0 {
+				return errors.Errorf("invalid pruning checkpoint %d", intVal)
+			} else {
+				c.cfg.PruningCheckpoint = intVal
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
@@ -879,6 +947,13 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.RPCRosetta = boolVal
 		}
 		n.srv.SetRosetta(n.rcfg.RPCRosetta)
+	case "rpcGraphQL":
+		if boolVal, err := strconv.ParseBool(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCGraphQL = boolVal
+		}
+		n.srv.SetGraphQL(n.rcfg.RPCGraphQL)
 	case "rpcBatchLimit":
 		if intVal, err := strconv.Atoi(value); err != nil {
 			return errors.Wrapf(err, "invalid value type")
@@ -893,6 +968,25 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.WSMaxSession = intVal
 		}
 		n.srv.SetWSMaxSession(n.rcfg.WSMaxSession)
+	case "rpcRateLimit":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCRateLimit = intVal
+		}
+		n.srv.SetRateLimit(n.rcfg.RPCRateLimit)
+	case "rpcMethodRateLimit":
+		if err := n.srv.SetMethodRateLimit(value); err != nil {
+			return errors.Wrapf(err, "invalid value")
+		}
+		n.rcfg.RPCMethodRateLimit = value
+	case "rpcCallConcurrency":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCCallConcurrency = intVal
+		}
+		n.srv.SetCallConcurrency(n.rcfg.RPCCallConcurrency)
 	default:
 		return errors.Errorf("not found key")
 	}
@@ -929,9 +1023,14 @@ func NewNode(
 		JSONRPCDump:           cfg.RPCDump,
 		JSONRPCIncludeDebug:   rcfg.RPCIncludeDebug,
 		JSONRPCRosetta:        rcfg.RPCRosetta,
+		GraphQL:               rcfg.RPCGraphQL,
 		JSONRPCDefaultChannel: rcfg.RPCDefaultChannel,
 		JSONRPCBatchLimit:     rcfg.RPCBatchLimit,
 		WSMaxSession:          rcfg.WSMaxSession,
+		RPCRateLimit:          rcfg.RPCRateLimit,
+		RPCMethodRateLimit:    rcfg.RPCMethodRateLimit,
+		RPCCallConcurrency:    rcfg.RPCCallConcurrency,
+		RPCTrustedProxies:     cfg.RPCTrustedProxies,
 	}
 	srv := server.NewManager(config, w, l)
 
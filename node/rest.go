@@ -84,25 +84,34 @@ type ChainInspectView struct {
 }
 
 type ChainConfig struct {
-	DBType           string `json:"dbType"`
-	Platform         string `json:"platform"`
-	SeedAddr         string `json:"seedAddress"`
-	Role             uint   `json:"role"`
-	ConcurrencyLevel int    `json:"concurrencyLevel,omitempty"`
-	NormalTxPoolSize int    `json:"normalTxPool,omitempty"`
-	PatchTxPoolSize  int    `json:"patchTxPool,omitempty"`
-	MaxBlockTxBytes  int    `json:"maxBlockTxBytes,omitempty"`
-	NodeCache        string `json:"nodeCache,omitempty"`
-	Channel          string `json:"channel"`
-	SecureSuites     string `json:"secureSuites"`
-	SecureAeads      string `json:"secureAeads"`
-	DefWaitTimeout   int64  `json:"defaultWaitTimeout"`
-	MaxWaitTimeout   int64  `json:"maxWaitTimeout"`
-	TxTimeout        int64  `json:"txTimeout"`
-	AutoStart        bool   `json:"autoStart"`
-	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
-	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
-	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	DBType            string `json:"dbType"`
+	Platform          string `json:"platform"`
+	SeedAddr          string `json:"seedAddress"`
+	Role              uint   `json:"role"`
+	ConcurrencyLevel  int    `json:"concurrencyLevel,omitempty"`
+	NormalTxPoolSize  int    `json:"normalTxPool,omitempty"`
+	PatchTxPoolSize   int    `json:"patchTxPool,omitempty"`
+	MaxBlockTxBytes   int    `json:"maxBlockTxBytes,omitempty"`
+	NodeCache         string `json:"nodeCache,omitempty"`
+	Channel           string `json:"channel"`
+	SecureSuites      string `json:"secureSuites"`
+	SecureAeads       string `json:"secureAeads"`
+	DefWaitTimeout    int64  `json:"defaultWaitTimeout"`
+	MaxWaitTimeout    int64  `json:"maxWaitTimeout"`
+	TxTimeout         int64  `json:"txTimeout"`
+	AutoStart         bool   `json:"autoStart"`
+	ChildrenLimit     *int   `json:"childrenLimit,omitempty"`
+	NephewsLimit      *int   `json:"nephewsLimit,omitempty"`
+	PeerBanThreshold  *int   `json:"peerBanThreshold,omitempty"`
+	PeerScoreHalfLife int64  `json:"peerScoreHalfLife,omitempty"`
+	PeerBanDuration   int64  `json:"peerBanDuration,omitempty"`
+	BandwidthLimits   string `json:"bandwidthLimits,omitempty"`
+	PacketCapture     string `json:"packetCapture,omitempty"`
+	ValidateTxOnSend  bool   `json:"validateTxOnSend,omitempty"`
+	LogIndex          bool   `json:"logIndex,omitempty"`
+	TxIndex           bool   `json:"txIndex,omitempty"`
+	PruningWindow     int64  `json:"pruningWindow,omitempty"`
+	PruningCheckpoint int64  `json:"pruningCheckpoint,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -124,6 +133,15 @@ type ChainBackupParam struct {
 	Manual bool `json:"manual,omitempty"`
 }
 
+type PeerBanParam struct {
+	Target   string `json:"target"`
+	Duration string `json:"duration,omitempty"`
+}
+
+type StaticPeerParam struct {
+	Address string `json:"address"`
+}
+
 type ConfigureParam struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -166,25 +184,34 @@ func NewChainInspectView(c *Chain) *ChainInspectView {
 
 func NewChainConfig(cfg *chain.Config) *ChainConfig {
 	v := &ChainConfig{
-		DBType:           cfg.DBType,
-		Platform:         cfg.Platform,
-		SeedAddr:         cfg.SeedAddr,
-		Role:             cfg.Role,
-		ConcurrencyLevel: cfg.ConcurrencyLevel,
-		NormalTxPoolSize: cfg.NormalTxPoolSize,
-		PatchTxPoolSize:  cfg.PatchTxPoolSize,
-		MaxBlockTxBytes:  cfg.MaxBlockTxBytes,
-		NodeCache:        cfg.NodeCache,
-		Channel:          cfg.Channel,
-		SecureSuites:     cfg.SecureSuites,
-		SecureAeads:      cfg.SecureAeads,
-		DefWaitTimeout:   cfg.DefWaitTimeout,
-		MaxWaitTimeout:   cfg.MaxWaitTimeout,
-		TxTimeout:        cfg.TxTimeout,
-		AutoStart:        cfg.AutoStart,
-		ChildrenLimit:    cfg.ChildrenLimit,
-		NephewsLimit:     cfg.NephewsLimit,
-		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		DBType:            cfg.DBType,
+		Platform:          cfg.Platform,
+		SeedAddr:          cfg.SeedAddr,
+		Role:              cfg.Role,
+		ConcurrencyLevel:  cfg.ConcurrencyLevel,
+		NormalTxPoolSize:  cfg.NormalTxPoolSize,
+		PatchTxPoolSize:   cfg.PatchTxPoolSize,
+		MaxBlockTxBytes:   cfg.MaxBlockTxBytes,
+		NodeCache:         cfg.NodeCache,
+		Channel:           cfg.Channel,
+		SecureSuites:      cfg.SecureSuites,
+		SecureAeads:       cfg.SecureAeads,
+		DefWaitTimeout:    cfg.DefWaitTimeout,
+		MaxWaitTimeout:    cfg.MaxWaitTimeout,
+		TxTimeout:         cfg.TxTimeout,
+		AutoStart:         cfg.AutoStart,
+		ChildrenLimit:     cfg.ChildrenLimit,
+		NephewsLimit:      cfg.NephewsLimit,
+		PeerBanThreshold:  cfg.PeerBanThreshold,
+		PeerScoreHalfLife: cfg.PeerScoreHalfLife,
+		PeerBanDuration:   cfg.PeerBanDuration,
+		BandwidthLimits:   cfg.BandwidthLimits,
+		PacketCapture:     cfg.PacketCapture,
+		ValidateTxOnSend:  cfg.ValidateTxOnSend,
+		LogIndex:          cfg.LogIndex,
+		TxIndex:           cfg.TxIndex,
+		PruningWindow:     cfg.PruningWindow,
+		PruningCheckpoint: cfg.PruningCheckpoint,
 	}
 	return v
 }
@@ -216,6 +243,9 @@ func RegisterRest(n *Node) {
 	_ = RegisterInspectFunc("metrics", metric.Inspect)
 	_ = RegisterInspectFunc("network", network.Inspect)
 	_ = RegisterInspectFunc("service", service.Inspect)
+	_ = RegisterInspectFunc("index", func(c module.Chain, informal bool) map[string]interface{} {
+		return chain.InspectIndexers(c.(*Chain).Chain, informal)
+	})
 
 	// json rpc
 	n.srv.RegisterAPIHandler(n.cliSrv.e.Group("/api"))
@@ -243,6 +273,16 @@ func (r *Rest) RegisterChainHandlers(g *echo.Group) {
 	}
 	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector)
 	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector)
+	g.GET(UrlChainRes+"/addressbook", r.GetAddressBook, r.ChainInjector)
+	g.DELETE(UrlChainRes+"/addressbook", r.ClearAddressBook, r.ChainInjector)
+	g.GET(UrlChainRes+"/peers", r.GetPeers, r.ChainInjector)
+	g.DELETE(UrlChainRes+"/peers/:"+ParamID, r.DisconnectPeer, r.ChainInjector)
+	g.GET(UrlChainRes+"/peers/bans", r.GetPeerBans, r.ChainInjector)
+	g.POST(UrlChainRes+"/peers/bans", r.BanPeer, r.ChainInjector)
+	g.DELETE(UrlChainRes+"/peers/bans", r.UnbanPeer, r.ChainInjector)
+	g.GET(UrlChainRes+"/peers/statics", r.GetStaticPeers, r.ChainInjector)
+	g.POST(UrlChainRes+"/peers/statics", r.AddStaticPeer, r.ChainInjector)
+	g.DELETE(UrlChainRes+"/peers/statics", r.RemoveStaticPeer, r.ChainInjector)
 	g.POST(UrlChainRes+"/:"+TaskID, r.RunChainTask, r.ChainInjector)
 }
 
@@ -451,6 +491,118 @@ func (r *Rest) ConfigureChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) GetAddressBook(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	l, err := network.AddressBook(c)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) ClearAddressBook(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := network.ClearAddressBook(c); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func peerAdminError(ctx echo.Context, err error) error {
+	switch {
+	case errors.NotFoundError.Equals(err):
+		return ctx.String(http.StatusNotFound, err.Error())
+	case errors.IllegalArgumentError.Equals(err):
+		return ctx.String(http.StatusBadRequest, err.Error())
+	case errors.InvalidStateError.Equals(err):
+		return ctx.String(http.StatusConflict, err.Error())
+	default:
+		return err
+	}
+}
+
+func (r *Rest) GetPeers(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	l, err := network.Peers(c)
+	if err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) DisconnectPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := network.DisconnectPeer(c, ctx.Param(ParamID)); err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) GetPeerBans(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	l, err := network.Bans(c)
+	if err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) BanPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &PeerBanParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	var d time.Duration
+	if len(param.Duration) > 0 {
+		var err error
+		if d, err = time.ParseDuration(param.Duration); err != nil || d < 0 {
+			return ctx.String(http.StatusBadRequest, "InvalidDuration(duration="+param.Duration+")")
+		}
+	}
+	if err := network.BanPeer(c, param.Target, d); err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) UnbanPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := network.UnbanPeer(c, ctx.QueryParam("target")); err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) GetStaticPeers(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	l, err := network.StaticPeers(c)
+	if err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) AddStaticPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &StaticPeerParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := network.AddStaticPeer(c, param.Address); err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) RemoveStaticPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := network.RemoveStaticPeer(c, ctx.QueryParam("address")); err != nil {
+		return peerAdminError(ctx, err)
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RunChainTask(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	task := ctx.Param(TaskID)
@@ -0,0 +1,547 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Type is one of *Scalar, *Object and *List.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Values returned by resolvers are encoded with
+// encoding/json as they are.
+type Scalar struct {
+	Name string
+}
+
+func (t *Scalar) String() string {
+	return t.Name
+}
+
+// List is a list of the type. Resolvers of the fields with List type
+// must return []interface{}.
+type List struct {
+	Of Type
+}
+
+func (t *List) String() string {
+	return "[" + t.Of.String() + "]"
+}
+
+// Object is a type with fields, which must be selected in the query.
+type Object struct {
+	Name   string
+	Fields map[string]*FieldDef
+}
+
+func (t *Object) String() string {
+	return t.Name
+}
+
+var (
+	String  = &Scalar{"String"}
+	Int     = &Scalar{"Int"}
+	Boolean = &Scalar{"Boolean"}
+)
+
+// ResolveParams is the parameter for ResolveFunc.
+type ResolveParams struct {
+	Context context.Context
+	// Source is the value resolved for the parent object.
+	Source interface{}
+	// Args has values of the arguments. Integers are int64, and variables
+	// are replaced with their values.
+	Args map[string]interface{}
+}
+
+// ResolveFunc returns the value of the field. Returning nil value means
+// null. Returning an error makes the field null, and the error is reported
+// in the result.
+type ResolveFunc func(p *ResolveParams) (interface{}, error)
+
+// SizeFunc returns the estimated number of items of a List field for the
+// arguments. Arguments given by undefined variables are missing.
+type SizeFunc func(args map[string]interface{}) int
+
+type FieldDef struct {
+	Type    Type
+	Args    []string
+	Resolve ResolveFunc
+	// Size estimates the number of items for the cost of the query. Nil
+	// means one item.
+	Size SizeFunc
+}
+
+// Schema is the schema of the queries.
+type Schema struct {
+	Query *Object
+	// MaxDepth is the maximum depth of the selections. Zero means
+	// no limit.
+	MaxDepth int
+	// MaxFields is the maximum number of fields in a selection set after
+	// expanding fragments. Zero means no limit.
+	MaxFields int
+	// MaxCost is the maximum cost of the query, which is the estimated
+	// number of fields to be resolved. Zero means no limit.
+	MaxCost int
+	// SDL describes the schema for clients.
+	SDL string
+}
+
+// Request is the body of GraphQL request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+type Result struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// orderedMap keeps the order of the fields in the selection set.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) set(k string, v interface{}) {
+	if _, ok := m.values[k]; !ok {
+		m.keys = append(m.keys, k)
+	}
+	m.values[k] = v
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type executor struct {
+	ctx       context.Context
+	schema    *Schema
+	doc       *Document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) addError(path []interface{}, err error) {
+	p := make([]interface{}, len(path))
+	copy(p, path)
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: p})
+}
+
+func requestError(f string, args ...interface{}) *Result {
+	return &Result{Errors: []*Error{{Message: fmt.Sprintf(f, args...)}}}
+}
+
+// Execute runs the query of the request on the schema.
+func (s *Schema) Execute(ctx context.Context, req *Request) *Result {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return requestError("%s", err.Error())
+	}
+	var op *Operation
+	for _, o := range doc.Operations {
+		if req.OperationName == "" || o.Name == req.OperationName {
+			if op != nil {
+				return requestError("operationName is required for multiple operations")
+			}
+			op = o
+		}
+	}
+	if op == nil {
+		return requestError("unknown operation %q", req.OperationName)
+	}
+
+	e := &executor{
+		ctx:       ctx,
+		schema:    s,
+		doc:       doc,
+		variables: make(map[string]interface{}),
+	}
+	for _, vd := range op.Variables {
+		v, ok := req.Variables[vd.Name]
+		if !ok {
+			v = vd.Default
+		}
+		if v == nil && vd.NonNull {
+			return requestError("variable $%s is required", vd.Name)
+		}
+		e.variables[vd.Name] = normalizeValue(v)
+	}
+
+	if _, err := e.validate(s.Query, op.SelectionSet, 1); err != nil {
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+	data := e.executeSelectionSet(s.Query, nil, op.SelectionSet, nil)
+	return &Result{Data: data, Errors: e.errors}
+}
+
+// normalizeValue converts numbers of variables decoded by encoding/json
+// to int64 if possible to be same as literals in the query.
+func normalizeValue(v interface{}) interface{} {
+	switch obj := v.(type) {
+	case float64:
+		if i := int64(obj); float64(i) == obj {
+			return i
+		}
+		return obj
+	case json.Number:
+		if i, err := obj.Int64(); err == nil {
+			return i
+		}
+		if f, err := obj.Float64(); err == nil {
+			return f
+		}
+		return obj.String()
+	case []interface{}:
+		list := make([]interface{}, len(obj))
+		for i, item := range obj {
+			list[i] = normalizeValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(obj))
+		for k, item := range obj {
+			m[k] = normalizeValue(item)
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+func (e *executor) resolveValue(v interface{}) (interface{}, error) {
+	switch obj := v.(type) {
+	case Variable:
+		value, ok := e.variables[string(obj)]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", obj)
+		}
+		return value, nil
+	case []interface{}:
+		list := make([]interface{}, len(obj))
+		for i, item := range obj {
+			var err error
+			if list[i], err = e.resolveValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(obj))
+		for k, item := range obj {
+			var err error
+			if m[k], err = e.resolveValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case EnumValue:
+		return string(obj), nil
+	default:
+		return v, nil
+	}
+}
+
+func (e *executor) resolveArgs(args map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		value, err := e.resolveValue(v)
+		if err != nil {
+			return nil, err
+		}
+		values[k] = value
+	}
+	return values, nil
+}
+
+// shouldInclude handles @skip and @include directives.
+func (e *executor) shouldInclude(ds []*Directive) (bool, error) {
+	for _, d := range ds {
+		switch d.Name {
+		case "skip", "include":
+			args, err := e.resolveArgs(d.Arguments)
+			if err != nil {
+				return false, err
+			}
+			cond, ok := args["if"].(bool)
+			if !ok {
+				return false, fmt.Errorf("invalid argument \"if\" for @%s", d.Name)
+			}
+			if cond == (d.Name == "skip") {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("unknown directive @%s", d.Name)
+		}
+	}
+	return true, nil
+}
+
+// collectFields returns the fields of the selection set for the object
+// expanding fragments. It fails if the selections to expand exceed
+// the maximum fields of the schema.
+func (e *executor) collectFields(t *Object, ss []Selection) ([]*Field, error) {
+	count := 0
+	return e.collectFieldsIn(t, ss, make(map[string]bool), &count)
+}
+
+func (e *executor) collectFieldsIn(t *Object, ss []Selection, visited map[string]bool, count *int) ([]*Field, error) {
+	var fields []*Field
+	for _, s := range ss {
+		if *count++; e.schema.MaxFields > 0 && *count > e.schema.MaxFields {
+			return nil, fmt.Errorf("selection exceeds the maximum fields %d", e.schema.MaxFields)
+		}
+		if ok, err := e.shouldInclude(s.directives()); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		var sub []Selection
+		var cond, spread string
+		switch sel := s.(type) {
+		case *Field:
+			fields = append(fields, sel)
+			continue
+		case *FragmentSpread:
+			f, ok := e.doc.Fragments[sel.Name]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment %q", sel.Name)
+			}
+			if visited[sel.Name] {
+				return nil, fmt.Errorf("cycle in fragment %q", sel.Name)
+			}
+			sub, cond, spread = f.SelectionSet, f.TypeCondition, sel.Name
+		case *InlineFragment:
+			sub, cond = sel.SelectionSet, sel.TypeCondition
+		}
+		if cond != "" && cond != t.Name {
+			continue
+		}
+		visited[spread] = true
+		fs, err := e.collectFieldsIn(t, sub, visited, count)
+		if err != nil {
+			return nil, err
+		}
+		delete(visited, spread)
+		fields = append(fields, fs...)
+	}
+	return fields, nil
+}
+
+func namedTypeOf(t Type) Type {
+	for {
+		if l, ok := t.(*List); ok {
+			t = l.Of
+		} else {
+			return t
+		}
+	}
+}
+
+// validate checks fields and arguments in the selection set, the depth
+// of the selections and the cost of the query before the execution. It
+// returns the cost of the selection set. Every field costs one, and the
+// cost of the selection set of a field is multiplied by the size of it.
+// Fields with the same response key are counted separately.
+func (e *executor) validate(t *Object, ss []Selection, depth int) (int, error) {
+	s := e.schema
+	if s.MaxDepth > 0 && depth > s.MaxDepth {
+		return 0, fmt.Errorf("query exceeds the maximum depth %d", s.MaxDepth)
+	}
+	fields, err := e.collectFields(t, ss)
+	if err != nil {
+		return 0, err
+	}
+	cost := 0
+	for _, f := range fields {
+		cost++
+		if s.MaxCost > 0 && cost > s.MaxCost {
+			return 0, fmt.Errorf("query exceeds the maximum cost %d", s.MaxCost)
+		}
+		if f.Name == "__typename" {
+			if f.SelectionSet != nil {
+				return 0, fmt.Errorf("field \"__typename\" must not have a selection")
+			}
+			continue
+		}
+		fd, ok := t.Fields[f.Name]
+		if !ok {
+			return 0, fmt.Errorf("unknown field %q on type %s", f.Name, t.Name)
+		}
+		for name := range f.Arguments {
+			if !containsString(fd.Args, name) {
+				return 0, fmt.Errorf("unknown argument %q on field %s.%s", name, t.Name, f.Name)
+			}
+		}
+		if obj, ok := namedTypeOf(fd.Type).(*Object); ok {
+			if f.SelectionSet == nil {
+				return 0, fmt.Errorf("field %q of type %s must have a selection", f.Name, fd.Type)
+			}
+			sub, err := e.validate(obj, f.SelectionSet, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			cost += e.sizeOf(fd, f) * sub
+			if s.MaxCost > 0 && cost > s.MaxCost {
+				return 0, fmt.Errorf("query exceeds the maximum cost %d", s.MaxCost)
+			}
+		} else if f.SelectionSet != nil {
+			return 0, fmt.Errorf("field %q of type %s must not have a selection", f.Name, fd.Type)
+		}
+	}
+	return cost, nil
+}
+
+// sizeOf returns the estimated number of items of the field.
+func (e *executor) sizeOf(fd *FieldDef, f *Field) int {
+	if fd.Size == nil {
+		return 1
+	}
+	args := make(map[string]interface{}, len(f.Arguments))
+	for k, v := range f.Arguments {
+		if value, err := e.resolveValue(v); err == nil {
+			args[k] = value
+		}
+	}
+	if size := fd.Size(args); size > 1 {
+		return size
+	}
+	return 1
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *executor) executeSelectionSet(t *Object, source interface{}, ss []Selection, path []interface{}) interface{} {
+	fields, err := e.collectFields(t, ss)
+	if err != nil {
+		e.addError(path, err)
+		return nil
+	}
+	result := newOrderedMap()
+	for _, f := range fields {
+		key := f.ResponseKey()
+		if f.Name == "__typename" {
+			result.set(key, t.Name)
+			continue
+		}
+		if _, ok := result.values[key]; ok {
+			// merged with the field of the same response key
+			continue
+		}
+		fd := t.Fields[f.Name]
+		fpath := append(path, key)
+		args, err := e.resolveArgs(f.Arguments)
+		if err != nil {
+			e.addError(fpath, err)
+			result.set(key, nil)
+			continue
+		}
+		v, err := fd.Resolve(&ResolveParams{
+			Context: e.ctx,
+			Source:  source,
+			Args:    args,
+		})
+		if err != nil {
+			e.addError(fpath, err)
+			result.set(key, nil)
+			continue
+		}
+		result.set(key, e.completeValue(fd.Type, v, e.mergedSelectionSet(fields, key), fpath))
+	}
+	return result
+}
+
+// mergedSelectionSet returns the selection set of the fields with the key.
+func (e *executor) mergedSelectionSet(fields []*Field, key string) []Selection {
+	var ss []Selection
+	for _, f := range fields {
+		if f.ResponseKey() == key {
+			ss = append(ss, f.SelectionSet...)
+		}
+	}
+	return ss
+}
+
+func (e *executor) completeValue(t Type, v interface{}, ss []Selection, path []interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch tt := t.(type) {
+	case *List:
+		items, ok := v.([]interface{})
+		if !ok {
+			e.addError(path, fmt.Errorf("invalid value %T for %s", v, t))
+			return nil
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			list[i] = e.completeValue(tt.Of, item, ss, append(path, i))
+		}
+		return list
+	case *Object:
+		return e.executeSelectionSet(tt, v, ss, path)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNode struct {
+	id int64
+}
+
+func newTestSchema(maxDepth int) *Schema {
+	nodeType := &Object{Name: "Node"}
+	nodeType.Fields = map[string]*FieldDef{
+		"id": {Type: Long, Resolve: func(p *ResolveParams) (interface{}, error) {
+			return p.Source.(*testNode).id, nil
+		}},
+		"next": {Type: nodeType, Resolve: func(p *ResolveParams) (interface{}, error) {
+			return &testNode{p.Source.(*testNode).id + 1}, nil
+		}},
+		"fail": {Type: String, Resolve: func(p *ResolveParams) (interface{}, error) {
+			return nil, errors.New("failure")
+		}},
+	}
+	queryType := &Object{
+		Name: "Query",
+		Fields: map[string]*FieldDef{
+			"node": {
+				Type: nodeType,
+				Args: []string{"id"},
+				Resolve: func(p *ResolveParams) (interface{}, error) {
+					id, _ := p.Args["id"].(int64)
+					return &testNode{id}, nil
+				},
+			},
+			"nodes": {
+				Type: &List{nodeType},
+				Args: []string{"ids"},
+				Size: func(args map[string]interface{}) int {
+					ids, _ := args["ids"].([]interface{})
+					return len(ids)
+				},
+				Resolve: func(p *ResolveParams) (interface{}, error) {
+					ids, ok := p.Args["ids"].([]interface{})
+					if !ok {
+						return nil, errors.New("invalid ids")
+					}
+					var nodes []interface{}
+					for _, id := range ids {
+						v, ok := id.(int64)
+						if !ok {
+							return nil, errors.New("invalid id")
+						}
+						nodes = append(nodes, &testNode{v})
+					}
+					return nodes, nil
+				},
+			},
+		},
+	}
+	return &Schema{Query: queryType, MaxDepth: maxDepth}
+}
+
+func TestSchema_Execute_Limits(t *testing.T) {
+	s := newTestSchema(0)
+	s.MaxFields = 4
+	s.MaxCost = 15
+
+	for _, q := range []string{
+		`{ node { id } }`,
+		`{ a: node { id } b: node { id } c: node { id } d: node { id } }`,
+		`{ nodes(ids: [1, 2, 3]) { id next { id } } }`,
+		`{ node { ...F } } fragment F on Node { id next { id } }`,
+	} {
+		res := s.Execute(context.Background(), &Request{Query: q})
+		assert.Empty(t, res.Errors, q)
+	}
+
+	for _, q := range []string{
+		`{ a: node { id } b: node { id } c: node { id } d: node { id } e: node { id } }`,
+		`{ node { id id id id id } }`,
+		`{ node { ...F ...F } } fragment F on Node { id id id }`,
+		`{ node { ...F } } fragment F on Node { ...G ...G } fragment G on Node { ... on X { id } ... on X { id } }`,
+		`{ nodes(ids: [1, 2, 3, 4, 5, 6, 7]) { id next { id } } }`,
+		`{ a: nodes(ids: [1, 2, 3]) { id next { id } } b: nodes(ids: [1, 2, 3]) { id next { id } } }`,
+		`{ nodes(ids: [1, 2, 3]) { id next { id } } nodes(ids: [1, 2, 3]) { id next { id } } }`,
+	} {
+		res := s.Execute(context.Background(), &Request{Query: q})
+		assert.Nil(t, res.Data, q)
+		assert.Len(t, res.Errors, 1, q)
+	}
+}
+
+func executeToJSON(t *testing.T, s *Schema, req *Request) string {
+	bs, err := json.Marshal(s.Execute(context.Background(), req))
+	assert.NoError(t, err)
+	return string(bs)
+}
+
+func TestSchema_Execute(t *testing.T) {
+	s := newTestSchema(0)
+
+	assert.Equal(t,
+		`{"data":{"n":{"next":{"id":3},"id":2,"__typename":"Node"},"node":{"id":0}}}`,
+		executeToJSON(t, s, &Request{
+			Query: `{ n: node(id: 2) { next { id } id __typename } node { id } }`,
+		}))
+
+	assert.Equal(t,
+		`{"data":{"nodes":[{"id":1,"next":{"id":2}},{"id":5,"next":{"id":6}}]}}`,
+		executeToJSON(t, s, &Request{
+			Query: `
+				query Q($ids: [Long!]!, $skip: Boolean = false) {
+					nodes(ids: $ids) {
+						...F
+						next @skip(if: $skip) { id }
+						fail @include(if: $skip)
+					}
+				}
+				fragment F on Node { id }`,
+			Variables: map[string]interface{}{"ids": []interface{}{float64(1), float64(5)}},
+		}))
+
+	assert.Equal(t,
+		`{"data":{"node":{"id":1,"fail":null}},"errors":[{"message":"failure","path":["node","fail"]}]}`,
+		executeToJSON(t, s, &Request{Query: `{ node(id: 1) { id fail } }`}))
+
+	assert.Equal(t,
+		`{"data":{"node":{"id":7}}}`,
+		executeToJSON(t, s, &Request{
+			Query:         `query A { node(id: 1) { id } } query B { node(id: 7) { id } }`,
+			OperationName: "B",
+		}))
+}
+
+func TestSchema_Execute_Errors(t *testing.T) {
+	s := newTestSchema(3)
+
+	for _, q := range []string{
+		`{ node { id`,
+		`{ unknown }`,
+		`{ node }`,
+		`{ node { id { x } } }`,
+		`{ node(x: 1) { id } }`,
+		`{ node { ...F } }`,
+		`{ node { ...F } } fragment F on Node { ...F }`,
+		`{ node { id @unknown } }`,
+		`{ node { next { next { id } } } }`,
+		`query ($id: Long!) { node(id: $id) { id } }`,
+		`query A { node { id } } query B { node { id } }`,
+	} {
+		res := s.Execute(context.Background(), &Request{Query: q})
+		assert.Nil(t, res.Data, q)
+		assert.Len(t, res.Errors, 1, q)
+	}
+
+	res := s.Execute(context.Background(), &Request{Query: `{ node { next { id } } }`})
+	assert.Empty(t, res.Errors)
+}
+
+func FuzzSchema_Execute(f *testing.F) {
+	for _, q := range []string{
+		`{ n: node(id: 2) { next { id } id __typename } node { id } }`,
+		`query Q($ids: [Long!]!, $skip: Boolean = false) { nodes(ids: $ids) { ...F next @skip(if: $skip) { id } } } fragment F on Node { id }`,
+		`{ node { ...F } } fragment F on Node { ...G ...G } fragment G on Node { ... on X { id } ... on X { id } }`,
+		`{ node { ...F } } fragment F on Node { ...F }`,
+	} {
+		f.Add(q, `{"ids":[1,5]}`)
+	}
+	s := newTestSchema(8)
+	s.MaxFields = 64
+	s.MaxCost = 256
+	f.Fuzz(func(t *testing.T, q string, vars string) {
+		req := &Request{Query: q}
+		if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+			req.Variables = nil
+		}
+		res := s.Execute(context.Background(), req)
+		if _, err := json.Marshal(res); err != nil {
+			t.Fatalf("fail to marshal result: %v", err)
+		}
+	})
+}
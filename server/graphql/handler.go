@@ -0,0 +1,65 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/module"
+)
+
+// MaxBodySize is the maximum size of the body of a POST request.
+const MaxBodySize = 1024 * 1024
+
+// Handler returns the handler for GraphQL requests on the chain set in
+// the context by the key "chain".
+// A POST request has the request in its body as JSON. A GET request has
+// "query", "operationName" and "variables" as query parameters, and it
+// returns the schema if there is no query.
+func Handler(schema *Schema) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := new(Request)
+		if c.Request().Method == http.MethodGet {
+			req.Query = c.QueryParam("query")
+			if req.Query == "" {
+				return c.String(http.StatusOK, schema.SDL)
+			}
+			req.OperationName = c.QueryParam("operationName")
+			if vars := c.QueryParam("variables"); vars != "" {
+				if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+					return c.JSON(http.StatusBadRequest, requestError("invalid variables"))
+				}
+			}
+		} else {
+			if c.Request().ContentLength > MaxBodySize {
+				return c.JSON(http.StatusRequestEntityTooLarge,
+					requestError("request body exceeds %d bytes", MaxBodySize))
+			}
+			body := http.MaxBytesReader(c.Response(), c.Request().Body, MaxBodySize)
+			if err := json.NewDecoder(body).Decode(req); err != nil {
+				return c.JSON(http.StatusBadRequest, requestError("invalid request body"))
+			}
+		}
+
+		chain, _ := c.Get("chain").(module.Chain)
+		ctx := WithChain(c.Request().Context(), chain)
+		return c.JSON(http.StatusOK, schema.Execute(ctx, req))
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_BodyLimit(t *testing.T) {
+	e := echo.New()
+	h := Handler(newTestSchema(0))
+	post := func(body io.Reader, length int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.ContentLength = length
+		rec := httptest.NewRecorder()
+		assert.NoError(t, h(e.NewContext(req, rec)))
+		return rec
+	}
+
+	q := `{"query":"{ node(id: 1) { id } }"}`
+	rec := post(strings.NewReader(q), int64(len(q)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":1`)
+
+	large := `{"query":"{ node { id } }","operationName":"` + strings.Repeat("a", MaxBodySize) + `"}`
+	rec = post(strings.NewReader(large), int64(len(large)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// the body without length (chunked) is limited while it's read.
+	rec = post(strings.NewReader(large), -1)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
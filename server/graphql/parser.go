@@ -0,0 +1,736 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed GraphQL request document. Only query operations
+// are supported.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+type Operation struct {
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []Selection
+}
+
+type VariableDefinition struct {
+	Name    string
+	NonNull bool
+	Default interface{}
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+}
+
+// Selection is one of *Field, *FragmentSpread and *InlineFragment.
+type Selection interface {
+	directives() []*Directive
+}
+
+type Directive struct {
+	Name      string
+	Arguments map[string]interface{}
+}
+
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    map[string]interface{}
+	Directives   []*Directive
+	SelectionSet []Selection
+}
+
+func (f *Field) directives() []*Directive {
+	return f.Directives
+}
+
+// ResponseKey returns the key of the field in the result.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+func (f *FragmentSpread) directives() []*Directive {
+	return f.Directives
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+}
+
+func (f *InlineFragment) directives() []*Directive {
+	return f.Directives
+}
+
+// Variable is a reference to a variable in argument values.
+type Variable string
+
+// EnumValue is an enum value in argument values.
+type EnumValue string
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "<EOF>"
+	}
+	return strconv.Quote(t.value)
+}
+
+type SyntaxError struct {
+	Pos     int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("Syntax Error: %s (pos=%d)", e.Message, e.Pos)
+}
+
+// bom is the unicode BOM, which is ignored like white spaces.
+const bom = "\ufeff"
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; c {
+		case ' ', '\t', '\n', '\r', ',':
+			l.pos += 1
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos += 1
+			}
+		default:
+			if strings.HasPrefix(l.src[l.pos:], bom) {
+				l.pos += len(bom)
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+	c := l.src[l.pos]
+	switch {
+	case strings.IndexByte("!$()[]{}:=@|&", c) >= 0:
+		l.pos += 1
+		return token{tokenPunct, string(c), start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.src[l.pos:], "...") {
+			l.pos += 3
+			return token{tokenPunct, "...", start}, nil
+		}
+		return token{}, &SyntaxError{start, "unexpected \".\""}
+	case isNameStart(c):
+		for l.pos < len(l.src) && (isNameStart(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos += 1
+		}
+		return token{tokenName, l.src[start:l.pos], start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		return l.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return token{}, &SyntaxError{start, fmt.Sprintf("unexpected character %q", r)}
+	}
+}
+
+func (l *lexer) digits() int {
+	start := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos += 1
+	}
+	return l.pos - start
+}
+
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.pos += 1
+	}
+	if l.digits() == 0 {
+		return token{}, &SyntaxError{start, "invalid number"}
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos += 1
+		if l.digits() == 0 {
+			return token{}, &SyntaxError{start, "invalid number"}
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos += 1
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos += 1
+		}
+		if l.digits() == 0 {
+			return token{}, &SyntaxError{start, "invalid number"}
+		}
+	}
+	return token{kind, l.src[start:l.pos], start}, nil
+}
+
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		end := strings.Index(l.src[l.pos+3:], `"""`)
+		if end < 0 {
+			return token{}, &SyntaxError{start, "unterminated string"}
+		}
+		value := l.src[l.pos+3 : l.pos+3+end]
+		l.pos += end + 6
+		return token{tokenString, value, start}, nil
+	}
+	l.pos += 1
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos += 1
+			return token{tokenString, sb.String(), start}, nil
+		case '\n', '\r':
+			return token{}, &SyntaxError{start, "unterminated string"}
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, &SyntaxError{start, "unterminated string"}
+			}
+			e := l.src[l.pos+1]
+			l.pos += 2
+			switch e {
+			case '"', '\\', '/':
+				sb.WriteByte(e)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, &SyntaxError{start, "invalid unicode escape"}
+				}
+				v, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, &SyntaxError{start, "invalid unicode escape"}
+				}
+				sb.WriteRune(rune(v))
+				l.pos += 4
+			default:
+				return token{}, &SyntaxError{l.pos - 2, fmt.Sprintf("invalid escape \\%c", e)}
+			}
+		default:
+			sb.WriteByte(c)
+			l.pos += 1
+		}
+	}
+	return token{}, &SyntaxError{start, "unterminated string"}
+}
+
+const (
+	// MaxTokens is the maximum number of tokens in a document.
+	MaxTokens = 10000
+	// MaxNesting is the maximum nesting of selection sets and values.
+	MaxNesting = 32
+)
+
+type parser struct {
+	lex     lexer
+	tok     token
+	tokens  int
+	nesting int
+}
+
+func (p *parser) advance() error {
+	if p.tokens++; p.tokens > MaxTokens {
+		return p.errorf("document exceeds %d tokens", MaxTokens)
+	}
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(f string, args ...interface{}) error {
+	return &SyntaxError{p.tok.pos, fmt.Sprintf(f, args...)}
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *parser) skip(kind tokenKind, value string) (bool, error) {
+	if p.peek(kind, value) {
+		return true, p.advance()
+	}
+	return false, nil
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.peek(kind, value) {
+		return p.errorf("expected %q, found %s", value, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) enter() error {
+	if p.nesting++; p.nesting > MaxNesting {
+		return p.errorf("nesting exceeds %d", MaxNesting)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.nesting--
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected name, found %s", p.tok)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+// Parse parses the GraphQL request document.
+func Parse(src string) (*Document, error) {
+	p := &parser{lex: lexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			ss, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{SelectionSet: ss})
+		case p.peek(tokenName, "query"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peek(tokenName, "fragment"):
+			f, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[f.Name]; ok {
+				return nil, p.errorf("duplicate fragment %q", f.Name)
+			}
+			doc.Fragments[f.Name] = f
+		case p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			return nil, p.errorf("%s is not supported", p.tok.value)
+		default:
+			return nil, p.errorf("unexpected %s", p.tok)
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, p.errorf("no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) operation() (*Operation, error) {
+	if err := p.expect(tokenName, "query"); err != nil {
+		return nil, err
+	}
+	op := new(Operation)
+	var err error
+	if p.tok.kind == tokenName {
+		if op.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if ok, err := p.skip(tokenPunct, "("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(tokenPunct, ")") {
+			vd, err := p.variableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, vd)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	if op.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) variableDefinition() (*VariableDefinition, error) {
+	if err := p.expect(tokenPunct, "$"); err != nil {
+		return nil, err
+	}
+	vd := new(VariableDefinition)
+	var err error
+	if vd.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if err = p.expect(tokenPunct, ":"); err != nil {
+		return nil, err
+	}
+	if vd.NonNull, err = p.typeRef(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(tokenPunct, "="); err != nil {
+		return nil, err
+	} else if ok {
+		if vd.Default, err = p.value(true); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	return vd, nil
+}
+
+// typeRef parses the type of the variable, and returns whether it's non-null.
+// Types of variables are not checked, so the type itself is ignored.
+func (p *parser) typeRef() (bool, error) {
+	if ok, err := p.skip(tokenPunct, "["); err != nil {
+		return false, err
+	} else if ok {
+		if _, err := p.typeRef(); err != nil {
+			return false, err
+		}
+		if err := p.expect(tokenPunct, "]"); err != nil {
+			return false, err
+		}
+	} else if _, err := p.name(); err != nil {
+		return false, err
+	}
+	return p.skip(tokenPunct, "!")
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	if err := p.expect(tokenName, "fragment"); err != nil {
+		return nil, err
+	}
+	f := new(Fragment)
+	var err error
+	if f.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if f.Name == "on" {
+		return nil, p.errorf("invalid fragment name \"on\"")
+	}
+	if err = p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	if f.TypeCondition, err = p.name(); err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	if f.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	var ss []Selection
+	for !p.peek(tokenPunct, "}") {
+		s, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	if len(ss) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+	return ss, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	if ok, err := p.skip(tokenPunct, "..."); err != nil {
+		return nil, err
+	} else if ok {
+		return p.fragmentSelection()
+	}
+	return p.field()
+}
+
+func (p *parser) fragmentSelection() (Selection, error) {
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		fs := new(FragmentSpread)
+		var err error
+		if fs.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if fs.Directives, err = p.directives(); err != nil {
+			return nil, err
+		}
+		return fs, nil
+	}
+	inf := new(InlineFragment)
+	var err error
+	if ok, err := p.skip(tokenName, "on"); err != nil {
+		return nil, err
+	} else if ok {
+		if inf.TypeCondition, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if inf.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if inf.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return inf, nil
+}
+
+func (p *parser) field() (*Field, error) {
+	f := new(Field)
+	var err error
+	if f.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(tokenPunct, ":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.Alias = f.Name
+		if f.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if f.Arguments, err = p.arguments(); err != nil {
+		return nil, err
+	}
+	if f.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, "{") {
+		if f.SelectionSet, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) arguments() (map[string]interface{}, error) {
+	if ok, err := p.skip(tokenPunct, "("); err != nil || !ok {
+		return nil, err
+	}
+	args := make(map[string]interface{})
+	for !p.peek(tokenPunct, ")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := args[name]; ok {
+			return nil, p.errorf("duplicate argument %q", name)
+		}
+		if err = p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		if args[name], err = p.value(false); err != nil {
+			return nil, err
+		}
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]*Directive, error) {
+	var ds []*Directive
+	for p.peek(tokenPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		d := new(Directive)
+		var err error
+		if d.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if d.Arguments, err = p.arguments(); err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+// value parses the value. Integers are int64, floats are float64, and
+// variables are Variable.
+func (p *parser) value(isConst bool) (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		v, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %s", tok.value)
+		}
+		return v, p.advance()
+	case tokenFloat:
+		v, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %s", tok.value)
+		}
+		return v, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		var v interface{}
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = EnumValue(tok.value)
+		}
+		return v, p.advance()
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if isConst {
+				return nil, p.errorf("unexpected variable")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			return Variable(name), err
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.enter(); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			list := []interface{}{}
+			for !p.peek(tokenPunct, "]") {
+				v, err := p.value(isConst)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.enter(); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			obj := make(map[string]interface{})
+			for !p.peek(tokenPunct, "}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err = p.expect(tokenPunct, ":"); err != nil {
+					return nil, err
+				}
+				if obj[name], err = p.value(isConst); err != nil {
+					return nil, err
+				}
+			}
+			return obj, p.advance()
+		}
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# comment
+		query Q($h: Long! = 3, $ids: [String!]) {
+			b: block(height: $h, hash: "0xA\n") @include(if: true) {
+				hash
+				...F
+				... on Block { height }
+			}
+			list(values: [1, -2.5e1, null, ENUM, {k: false}])
+		}
+		fragment F on Block { timestamp }
+	`)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Operations, 1)
+
+	op := doc.Operations[0]
+	assert.Equal(t, "Q", op.Name)
+	assert.Equal(t, []*VariableDefinition{
+		{Name: "h", NonNull: true, Default: int64(3)},
+		{Name: "ids"},
+	}, op.Variables)
+	assert.Len(t, op.SelectionSet, 2)
+
+	f := op.SelectionSet[0].(*Field)
+	assert.Equal(t, "b", f.ResponseKey())
+	assert.Equal(t, "block", f.Name)
+	assert.Equal(t, map[string]interface{}{
+		"height": Variable("h"),
+		"hash":   "0xA\n",
+	}, f.Arguments)
+	assert.Equal(t, "include", f.Directives[0].Name)
+	assert.Len(t, f.SelectionSet, 3)
+	assert.Equal(t, "F", f.SelectionSet[1].(*FragmentSpread).Name)
+	assert.Equal(t, "Block", f.SelectionSet[2].(*InlineFragment).TypeCondition)
+
+	f = op.SelectionSet[1].(*Field)
+	assert.Equal(t, []interface{}{
+		int64(1), float64(-25), nil, EnumValue("ENUM"),
+		map[string]interface{}{"k": false},
+	}, f.Arguments["values"])
+
+	assert.Equal(t, "Block", doc.Fragments["F"].TypeCondition)
+
+	doc, err = Parse(`{ a }`)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", doc.Operations[0].SelectionSet[0].(*Field).Name)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, q := range []string{
+		``,
+		`{ }`,
+		`{ a`,
+		`{ a(x: ) }`,
+		`{ a(x: 1, x: 2) }`,
+		`{ a(x: "abc) }`,
+		`{ a(x: 1.) }`,
+		`{ a. }`,
+		`mutation { a }`,
+		`query ($x: Int = $y) { a }`,
+		`fragment on on T { a }`,
+		`fragment F on T { a } fragment F on T { b } { a }`,
+	} {
+		_, err := Parse(q)
+		assert.Error(t, err, q)
+		if err != nil {
+			_, ok := err.(*SyntaxError)
+			assert.True(t, ok, q)
+		}
+	}
+}
+
+func TestParse_Limits(t *testing.T) {
+	nested := func(n int) string {
+		return strings.Repeat("{ a ", n) + strings.Repeat("}", n)
+	}
+	_, err := Parse(nested(MaxNesting))
+	assert.NoError(t, err)
+	_, err = Parse(nested(MaxNesting + 1))
+	assert.Error(t, err)
+
+	_, err = Parse(`{ a(x: ` + strings.Repeat("[", MaxNesting+1) + strings.Repeat("]", MaxNesting+1) + `) }`)
+	assert.Error(t, err)
+
+	_, err = Parse("{" + strings.Repeat(" a", MaxTokens-3) + " }")
+	assert.NoError(t, err)
+	_, err = Parse("{" + strings.Repeat(" a", MaxTokens-2) + " }")
+	assert.Error(t, err)
+}
+
+func FuzzParse(f *testing.F) {
+	for _, q := range []string{
+		`{ a }`,
+		`query Q($h: Long! = 3, $ids: [String!]) { b: block(height: $h, hash: "0xA\n") @include(if: true) { hash ...F ... on Block { height } } }`,
+		`{ list(values: [1, -2.5e1, null, ENUM, {k: false}]) } fragment F on Block { timestamp }`,
+		`{ a(x: "abc) }`,
+		`{ a(x: 1.) }`,
+		`fragment F on T { a } fragment F on T { b } { a }`,
+	} {
+		f.Add(q)
+	}
+	f.Fuzz(func(t *testing.T, q string) {
+		doc, err := Parse(q)
+		if err != nil {
+			if _, ok := err.(*SyntaxError); !ok {
+				t.Fatalf("unexpected error type %T: %v", err, err)
+			}
+			return
+		}
+		if len(doc.Operations) == 0 {
+			t.Fatal("no operation without error")
+		}
+	})
+}
@@ -0,0 +1,676 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+const (
+	ConfigMaxDepth  = 8
+	ConfigMaxBlocks = 100
+	ConfigMaxFields = 100
+	ConfigMaxCost   = 50000
+
+	// ConfigTransactionsPerBlock and ConfigLogsPerReceipt are the estimated
+	// number of items for the cost of the query.
+	ConfigTransactionsPerBlock = 100
+	ConfigLogsPerReceipt       = 10
+)
+
+var (
+	// Long is an integer encoded as JSON number, which may exceed 32 bits.
+	Long = &Scalar{"Long"}
+	// HexInt is an integer encoded as hex string like JSON-RPC.
+	HexInt = &Scalar{"HexInt"}
+	// Bytes is bytes encoded as hex string with "0x" prefix.
+	Bytes = &Scalar{"Bytes"}
+	// Address is the address of an account.
+	Address = &Scalar{"Address"}
+	// JSON is an arbitrary JSON value.
+	JSON = &Scalar{"JSON"}
+)
+
+type contextKey int
+
+const chainKey contextKey = 0
+
+// WithChain returns the context for the queries on the chain.
+func WithChain(ctx context.Context, chain module.Chain) context.Context {
+	return context.WithValue(ctx, chainKey, chain)
+}
+
+func blockManagerOf(ctx context.Context) (module.BlockManager, error) {
+	chain, ok := ctx.Value(chainKey).(module.Chain)
+	if !ok || chain == nil {
+		return nil, errors.InvalidStateError.New("NoChain")
+	}
+	bm := chain.BlockManager()
+	if bm == nil {
+		return nil, errors.InvalidStateError.New("Stopped")
+	}
+	return bm, nil
+}
+
+func serviceManagerOf(ctx context.Context) (module.ServiceManager, error) {
+	chain, ok := ctx.Value(chainKey).(module.Chain)
+	if !ok || chain == nil {
+		return nil, errors.InvalidStateError.New("NoChain")
+	}
+	sm := chain.ServiceManager()
+	if sm == nil {
+		return nil, errors.InvalidStateError.New("Stopped")
+	}
+	return sm, nil
+}
+
+func checkBaseHeight(ctx context.Context, height int64) error {
+	if height < 0 {
+		return errors.NotFoundError.Errorf("NegativeHeight(height=%d)", height)
+	}
+	chain := ctx.Value(chainKey).(module.Chain)
+	base := chain.GenesisStorage().Height()
+	if height < base {
+		return errors.NotFoundError.Errorf("PrunedBlock(height=%d,base=%d)", height, base)
+	}
+	return nil
+}
+
+func statePrunedError(err error, blk module.Block) error {
+	if service.StatePrunedError.Equals(err) {
+		return errors.NotFoundError.Errorf("StatePruned(height=%d)", blk.Height())
+	}
+	return err
+}
+
+func int64Arg(args map[string]interface{}, name string) (int64, bool, error) {
+	v, ok := args[name]
+	if !ok || v == nil {
+		return 0, false, nil
+	}
+	switch value := v.(type) {
+	case int64:
+		return value, true, nil
+	case string:
+		var h common.HexInt64
+		if err := h.UnmarshalJSON([]byte(`"` + value + `"`)); err != nil {
+			return 0, false, errors.IllegalArgumentError.Errorf("InvalidArgument(%s=%s)", name, value)
+		}
+		return h.Value, true, nil
+	default:
+		return 0, false, errors.IllegalArgumentError.Errorf("InvalidArgument(%s=%v)", name, v)
+	}
+}
+
+func bytesArg(args map[string]interface{}, name string) ([]byte, bool, error) {
+	v, ok := args[name]
+	if !ok || v == nil {
+		return nil, false, nil
+	}
+	if s, ok := v.(string); ok {
+		if bs, err := hex.DecodeString(strings.TrimPrefix(s, "0x")); err == nil {
+			return bs, true, nil
+		}
+	}
+	return nil, false, errors.IllegalArgumentError.Errorf("InvalidArgument(%s=%v)", name, v)
+}
+
+func addressArg(args map[string]interface{}, name string) (module.Address, error) {
+	if s, ok := args[name].(string); ok {
+		addr := new(common.Address)
+		if err := addr.SetStringStrict(s); err == nil {
+			return addr, nil
+		}
+	}
+	return nil, errors.IllegalArgumentError.Errorf("InvalidArgument(%s=%v)", name, args[name])
+}
+
+// blockByHeight returns the block at the height, or nil if it doesn't exist.
+func blockByHeight(ctx context.Context, height int64) (module.Block, error) {
+	bm, err := blockManagerOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBaseHeight(ctx, height); err != nil {
+		return nil, err
+	}
+	blk, err := bm.GetBlockByHeight(height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	}
+	return blk, err
+}
+
+// blockOfArgs returns the block for height or hash argument. If neither
+// is specified, it returns the last block.
+func blockOfArgs(p *ResolveParams) (module.Block, error) {
+	bm, err := blockManagerOf(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	height, hasHeight, err := int64Arg(p.Args, "height")
+	if err != nil {
+		return nil, err
+	}
+	hash, hasHash, err := bytesArg(p.Args, "hash")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case hasHeight && hasHash:
+		return nil, errors.IllegalArgumentError.New("BothHeightAndHash")
+	case hasHeight:
+		return blockByHeight(p.Context, height)
+	case hasHash:
+		blk, err := bm.GetBlock(hash)
+		if errors.NotFoundError.Equals(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if err := checkBaseHeight(p.Context, blk.Height()); err != nil {
+			return nil, err
+		}
+		return blk, nil
+	default:
+		return bm.GetLastBlock()
+	}
+}
+
+// transaction is the source of Transaction type.
+type transaction struct {
+	tx    module.Transaction
+	blk   module.Block
+	index int
+	jso   map[string]interface{}
+}
+
+func (t *transaction) jsonField(name string) (interface{}, error) {
+	if t.jso == nil {
+		jso, err := t.tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := jso.(map[string]interface{})
+		if !ok {
+			return nil, errors.InvalidStateError.Errorf("InvalidTransactionJSON(%T)", jso)
+		}
+		t.jso = m
+	}
+	return t.jso[name], nil
+}
+
+func transactionsOf(blk module.Block) ([]interface{}, error) {
+	var txs []interface{}
+	for it := blk.NormalTransactions().Iterator(); it.Has(); {
+		tx, idx, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, &transaction{tx: tx, blk: blk, index: idx})
+		if err := it.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
+
+// account is the source of Account type.
+type account struct {
+	addr module.Address
+	blk  module.Block
+}
+
+func source(f func(src interface{}) (interface{}, error)) ResolveFunc {
+	return func(p *ResolveParams) (interface{}, error) {
+		return f(p.Source)
+	}
+}
+
+func blockField(f func(blk module.Block) (interface{}, error)) ResolveFunc {
+	return source(func(src interface{}) (interface{}, error) {
+		return f(src.(module.Block))
+	})
+}
+
+func txField(f func(tx *transaction) (interface{}, error)) ResolveFunc {
+	return source(func(src interface{}) (interface{}, error) {
+		return f(src.(*transaction))
+	})
+}
+
+func txJSONField(name string) *FieldDef {
+	return &FieldDef{
+		Type: JSON,
+		Resolve: txField(func(tx *transaction) (interface{}, error) {
+			return tx.jsonField(name)
+		}),
+	}
+}
+
+func receiptField(f func(r module.Receipt) (interface{}, error)) ResolveFunc {
+	return source(func(src interface{}) (interface{}, error) {
+		return f(src.(module.Receipt))
+	})
+}
+
+// receiptJSON returns the receipt in the form of JSON-RPC as generic JSON
+// values, so event logs are decoded with their signatures.
+func receiptJSON(r module.Receipt) (map[string]interface{}, error) {
+	jso, err := r.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(jso)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(bs, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func eventLogField(name string) ResolveFunc {
+	return source(func(src interface{}) (interface{}, error) {
+		return src.(map[string]interface{})[name], nil
+	})
+}
+
+func sizeOf(n int) SizeFunc {
+	return func(args map[string]interface{}) int {
+		return n
+	}
+}
+
+func addressOrNil(addr module.Address) interface{} {
+	if addr == nil {
+		return nil
+	}
+	return addr
+}
+
+var (
+	blockType       = &Object{Name: "Block"}
+	transactionType = &Object{Name: "Transaction"}
+	receiptType     = &Object{Name: "Receipt"}
+	eventLogType    = &Object{Name: "EventLog"}
+	accountType     = &Object{Name: "Account"}
+	queryType       = &Object{Name: "Query"}
+)
+
+func init() {
+	blockType.Fields = map[string]*FieldDef{
+		"hash": {Type: Bytes, Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			return common.HexBytes(blk.ID()), nil
+		})},
+		"height": {Type: Long, Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			return blk.Height(), nil
+		})},
+		"timestamp": {Type: Long, Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			return blk.Timestamp(), nil
+		})},
+		"version": {Type: Int, Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			return blk.Version(), nil
+		})},
+		"prevHash": {Type: Bytes, Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			return common.HexBytes(blk.PrevID()), nil
+		})},
+		"proposer": {Type: Address, Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			return addressOrNil(blk.Proposer()), nil
+		})},
+		"transactionCount": {Type: Int, Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			txs, err := transactionsOf(blk)
+			return len(txs), err
+		})},
+		"transactions": {Type: &List{transactionType}, Size: sizeOf(ConfigTransactionsPerBlock), Resolve: blockField(func(blk module.Block) (interface{}, error) {
+			txs, err := transactionsOf(blk)
+			if txs == nil && err == nil {
+				txs = []interface{}{}
+			}
+			return txs, err
+		})},
+	}
+
+	transactionType.Fields = map[string]*FieldDef{
+		"hash": {Type: Bytes, Resolve: txField(func(tx *transaction) (interface{}, error) {
+			return common.HexBytes(tx.tx.ID()), nil
+		})},
+		"index": {Type: Int, Resolve: txField(func(tx *transaction) (interface{}, error) {
+			return tx.index, nil
+		})},
+		"block": {Type: blockType, Resolve: txField(func(tx *transaction) (interface{}, error) {
+			return tx.blk, nil
+		})},
+		"from": {Type: Address, Resolve: txField(func(tx *transaction) (interface{}, error) {
+			return addressOrNil(tx.tx.From()), nil
+		})},
+		"version":   txJSONField("version"),
+		"to":        txJSONField("to"),
+		"value":     txJSONField("value"),
+		"stepLimit": txJSONField("stepLimit"),
+		"timestamp": txJSONField("timestamp"),
+		"nid":       txJSONField("nid"),
+		"nonce":     txJSONField("nonce"),
+		"dataType":  txJSONField("dataType"),
+		"data":      txJSONField("data"),
+		"signature": txJSONField("signature"),
+		"receipt": {Type: receiptType, Resolve: func(p *ResolveParams) (interface{}, error) {
+			bm, err := blockManagerOf(p.Context)
+			if err != nil {
+				return nil, err
+			}
+			info, err := bm.GetTransactionInfo(p.Source.(*transaction).tx.ID())
+			if err != nil {
+				return nil, err
+			}
+			r, err := info.GetReceipt()
+			if block.ResultNotFinalizedError.Equals(err) {
+				return nil, nil
+			}
+			return r, err
+		}},
+	}
+
+	receiptType.Fields = map[string]*FieldDef{
+		"status": {Type: Int, Resolve: receiptField(func(r module.Receipt) (interface{}, error) {
+			return int(r.Status()), nil
+		})},
+		"stepUsed": {Type: HexInt, Resolve: receiptField(func(r module.Receipt) (interface{}, error) {
+			return new(common.HexInt).SetValue(r.StepUsed()), nil
+		})},
+		"stepPrice": {Type: HexInt, Resolve: receiptField(func(r module.Receipt) (interface{}, error) {
+			return new(common.HexInt).SetValue(r.StepPrice()), nil
+		})},
+		"cumulativeStepUsed": {Type: HexInt, Resolve: receiptField(func(r module.Receipt) (interface{}, error) {
+			return new(common.HexInt).SetValue(r.CumulativeStepUsed()), nil
+		})},
+		"scoreAddress": {Type: Address, Resolve: receiptField(func(r module.Receipt) (interface{}, error) {
+			return addressOrNil(r.SCOREAddress()), nil
+		})},
+		"failure": {Type: JSON, Resolve: receiptField(func(r module.Receipt) (interface{}, error) {
+			jso, err := receiptJSON(r)
+			if err != nil {
+				return nil, err
+			}
+			return jso["failure"], nil
+		})},
+		"logs": {Type: &List{eventLogType}, Size: sizeOf(ConfigLogsPerReceipt), Resolve: receiptField(func(r module.Receipt) (interface{}, error) {
+			jso, err := receiptJSON(r)
+			if err != nil {
+				return nil, err
+			}
+			logs := []interface{}{}
+			if els, ok := jso["eventLogs"].([]interface{}); ok {
+				logs = els
+			}
+			return logs, nil
+		})},
+	}
+
+	eventLogType.Fields = map[string]*FieldDef{
+		"scoreAddress": {Type: Address, Resolve: eventLogField("scoreAddress")},
+		"indexed":      {Type: &List{JSON}, Resolve: eventLogField("indexed")},
+		"data":         {Type: &List{JSON}, Resolve: eventLogField("data")},
+	}
+
+	accountType.Fields = map[string]*FieldDef{
+		"address": {Type: Address, Resolve: source(func(src interface{}) (interface{}, error) {
+			return src.(*account).addr, nil
+		})},
+		"isContract": {Type: Boolean, Resolve: source(func(src interface{}) (interface{}, error) {
+			return src.(*account).addr.IsContract(), nil
+		})},
+		"balance": {Type: HexInt, Resolve: func(p *ResolveParams) (interface{}, error) {
+			acct := p.Source.(*account)
+			sm, err := serviceManagerOf(p.Context)
+			if err != nil {
+				return nil, err
+			}
+			b, err := sm.GetBalance(acct.blk.Result(), acct.addr)
+			if err != nil {
+				return nil, statePrunedError(err, acct.blk)
+			}
+			return new(common.HexInt).SetValue(b), nil
+		}},
+		"scoreStatus": {Type: JSON, Resolve: func(p *ResolveParams) (interface{}, error) {
+			acct := p.Source.(*account)
+			if !acct.addr.IsContract() {
+				return nil, nil
+			}
+			sm, err := serviceManagerOf(p.Context)
+			if err != nil {
+				return nil, err
+			}
+			s, err := sm.GetSCOREStatus(acct.blk.Result(), acct.addr)
+			if errors.NotFoundError.Equals(err) {
+				return nil, nil
+			} else if err != nil {
+				return nil, statePrunedError(err, acct.blk)
+			}
+			return s.ToJSON(acct.blk.Height(), module.JSONVersion3)
+		}},
+	}
+
+	queryType.Fields = map[string]*FieldDef{
+		"block": {
+			Type: blockType,
+			Args: []string{"height", "hash"},
+			Resolve: func(p *ResolveParams) (interface{}, error) {
+				blk, err := blockOfArgs(p)
+				if blk == nil {
+					return nil, err
+				}
+				return blk, nil
+			},
+		},
+		"blocks": {
+			Type: &List{blockType},
+			Args: []string{"from", "to"},
+			Size: func(args map[string]interface{}) int {
+				from, _, _ := int64Arg(args, "from")
+				to, ok, err := int64Arg(args, "to")
+				if err != nil || !ok || from > to || to-from >= ConfigMaxBlocks {
+					return ConfigMaxBlocks
+				}
+				return int(to - from + 1)
+			},
+			Resolve: func(p *ResolveParams) (interface{}, error) {
+				bm, err := blockManagerOf(p.Context)
+				if err != nil {
+					return nil, err
+				}
+				from, ok, err := int64Arg(p.Args, "from")
+				if err != nil {
+					return nil, err
+				} else if !ok {
+					return nil, errors.IllegalArgumentError.New("NoFrom")
+				}
+				to, ok, err := int64Arg(p.Args, "to")
+				if err != nil {
+					return nil, err
+				} else if !ok {
+					last, err := bm.GetLastBlock()
+					if err != nil {
+						return nil, err
+					}
+					to = last.Height()
+					if to >= from+ConfigMaxBlocks {
+						to = from + ConfigMaxBlocks - 1
+					}
+				}
+				if from > to || to-from >= ConfigMaxBlocks {
+					return nil, errors.IllegalArgumentError.Errorf(
+						"InvalidRange(from=%d,to=%d,max=%d)", from, to, ConfigMaxBlocks)
+				}
+				blks := []interface{}{}
+				for h := from; h <= to; h++ {
+					blk, err := blockByHeight(p.Context, h)
+					if err != nil {
+						return nil, err
+					}
+					if blk == nil {
+						break
+					}
+					blks = append(blks, blk)
+				}
+				return blks, nil
+			},
+		},
+		"transaction": {
+			Type: transactionType,
+			Args: []string{"hash"},
+			Resolve: func(p *ResolveParams) (interface{}, error) {
+				bm, err := blockManagerOf(p.Context)
+				if err != nil {
+					return nil, err
+				}
+				hash, ok, err := bytesArg(p.Args, "hash")
+				if err != nil {
+					return nil, err
+				} else if !ok {
+					return nil, errors.IllegalArgumentError.New("NoHash")
+				}
+				info, err := bm.GetTransactionInfo(hash)
+				if errors.NotFoundError.Equals(err) {
+					return nil, nil
+				} else if err != nil {
+					return nil, err
+				}
+				if err := checkBaseHeight(p.Context, info.Block().Height()); err != nil {
+					return nil, err
+				}
+				tx, err := info.Transaction()
+				if err != nil {
+					return nil, err
+				}
+				return &transaction{tx: tx, blk: info.Block(), index: info.Index()}, nil
+			},
+		},
+		"account": {
+			Type: accountType,
+			Args: []string{"address", "height"},
+			Resolve: func(p *ResolveParams) (interface{}, error) {
+				addr, err := addressArg(p.Args, "address")
+				if err != nil {
+					return nil, err
+				}
+				blk, err := blockOfArgs(&ResolveParams{
+					Context: p.Context,
+					Args:    map[string]interface{}{"height": p.Args["height"]},
+				})
+				if err != nil {
+					return nil, err
+				} else if blk == nil {
+					return nil, errors.NotFoundError.Errorf("NoBlock(height=%v)", p.Args["height"])
+				}
+				return &account{addr: addr, blk: blk}, nil
+			},
+		},
+	}
+}
+
+// NewSchema returns the schema over blocks, transactions, receipts and
+// accounts of the chain given by WithChain.
+func NewSchema() *Schema {
+	return &Schema{
+		Query:     queryType,
+		MaxDepth:  ConfigMaxDepth,
+		MaxFields: ConfigMaxFields,
+		MaxCost:   ConfigMaxCost,
+		SDL:       schemaSDL,
+	}
+}
+
+var schemaSDL = fmt.Sprintf(`type Query {
+  block(height: Long, hash: Bytes): Block
+  blocks(from: Long!, to: Long): [Block]
+  transaction(hash: Bytes!): Transaction
+  account(address: Address!, height: Long): Account
+}
+
+type Block {
+  hash: Bytes
+  height: Long
+  timestamp: Long
+  version: Int
+  prevHash: Bytes
+  proposer: Address
+  transactionCount: Int
+  transactions: [Transaction]
+}
+
+type Transaction {
+  hash: Bytes
+  index: Int
+  block: Block
+  from: Address
+  version: JSON
+  to: JSON
+  value: JSON
+  stepLimit: JSON
+  timestamp: JSON
+  nid: JSON
+  nonce: JSON
+  dataType: JSON
+  data: JSON
+  signature: JSON
+  receipt: Receipt
+}
+
+type Receipt {
+  status: Int
+  stepUsed: HexInt
+  stepPrice: HexInt
+  cumulativeStepUsed: HexInt
+  scoreAddress: Address
+  failure: JSON
+  logs: [EventLog]
+}
+
+type EventLog {
+  scoreAddress: Address
+  indexed: [JSON]
+  data: [JSON]
+}
+
+type Account {
+  address: Address
+  isContract: Boolean
+  balance: HexInt
+  scoreStatus: JSON
+}
+
+# Maximum depth of the query is %d, and "blocks" returns up to %d blocks.
+# A selection set may have up to %d fields after expanding fragments.
+# The cost of the query, the estimated number of fields to be resolved,
+# must not exceed %d. Every field costs one including repeated or aliased
+# ones, and the selection of a list is multiplied by the number of items;
+# the range for "blocks", %d for "transactions" and %d for "logs".
+`, ConfigMaxDepth, ConfigMaxBlocks, ConfigMaxFields, ConfigMaxCost,
+	ConfigTransactionsPerBlock, ConfigLogsPerReceipt)
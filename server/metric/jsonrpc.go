@@ -12,6 +12,7 @@ import (
 
 var (
 	mkMethod  = NewMetricKey("method")
+	mkReason  = NewMetricKey("reason")
 	msFailure = &measure{
 		ms:    stats.Int64("jsonrpc_failure", "jsonrpc failures", "ns"),
 		msAvg: stats.Int64("jsonrpc_failure_avg", "moving average of jsonrpc failures", "ns"),
@@ -22,8 +23,9 @@ var (
 		msAvg: stats.Int64("jsonrpc_retrieve_avg", "moving average of jsonrpc retrieve methods", "ns"),
 		mks:   []tag.Key{mkMethod},
 	}
-	emptyMks = []tag.Key{}
-	msMap    = map[string]*measure{
+	msRejected = stats.Int64("jsonrpc_rejected", "jsonrpc requests rejected by limits", "count")
+	emptyMks   = []tag.Key{}
+	msMap      = map[string]*measure{
 		"icx_getLastBlock":     msRetrieve,
 		"icx_getBlockByHeight": msRetrieve,
 		"icx_getBlockByHash":   msRetrieve,
@@ -69,6 +71,16 @@ var (
 			stats.Int64("jsonrpc_get_trace_avg", "moving average of jsonrpc debug_getTrace method", "ns"),
 			emptyMks,
 		},
+		"debug_traceTransaction": {
+			stats.Int64("jsonrpc_trace_transaction", "jsonrpc debug_traceTransaction method", "ns"),
+			stats.Int64("jsonrpc_trace_transaction_avg", "moving average of jsonrpc debug_traceTransaction method", "ns"),
+			emptyMks,
+		},
+		"debug_getStateDiff": {
+			stats.Int64("jsonrpc_get_state_diff", "jsonrpc debug_getStateDiff method", "ns"),
+			stats.Int64("jsonrpc_get_state_diff_avg", "moving average of jsonrpc debug_getStateDiff method", "ns"),
+			emptyMks,
+		},
 		"debug_estimateStep": {
 			stats.Int64("jsonrpc_estimate_step", "jsonrpc debug_estimateStep method", "ns"),
 			stats.Int64("jsonrpc_estimate_step_avg", "moving average of jsonrpc debug_estimateStep method", "ns"),
@@ -100,6 +112,7 @@ func RegisterJsonrpc() {
 	RegisterMetricView(msFailure.msAvg, view.LastValue(), emptyMks)
 	RegisterMetricView(msRetrieve.ms, view.Count(), msRetrieve.mks)
 	RegisterMetricView(msRetrieve.msAvg, view.LastValue(), emptyMks)
+	RegisterMetricView(msRejected, view.Count(), []tag.Key{mkMethod, mkReason})
 	for _, v := range msMap {
 		if v != msRetrieve {
 			RegisterMetricView(v.ms, view.Count(), v.mks)
@@ -197,6 +210,13 @@ func (m *JsonrpcMetric) OnHandle(ctx context.Context, method string, ts time.Tim
 	jm.RemoveAndRecord(ctx, ts, m.expire)
 }
 
+// OnReject records the request rejected by the limit of the server.
+func (m *JsonrpcMetric) OnReject(ctx context.Context, method string, reason string) {
+	ctx = GetMetricContext(ctx, &mkMethod, method)
+	ctx = GetMetricContext(ctx, &mkReason, reason)
+	stats.Record(ctx, msRejected.M(1))
+}
+
 func NewJsonrpcMetric(expire time.Duration, durationsSize int, useDefault bool) *JsonrpcMetric {
 	jmsMtx.Lock()
 	defer jmsMtx.Unlock()
@@ -16,6 +16,16 @@ var (
 	mkDest     = NewMetricKey("dest")
 	mkProtocol = NewMetricKey("protocol")
 	networkMks = []tag.Key{mkDest, mkProtocol}
+
+	msPeerSend     = stats.Int64("network_peer_send", "send to peer", stats.UnitBytes)
+	msPeerRecv     = stats.Int64("network_peer_recv", "recv from peer", stats.UnitBytes)
+	mkPeer         = NewMetricKey("peer")
+	networkPeerMks = []tag.Key{mkPeer, mkProtocol}
+)
+
+const (
+	MaxPeerMetricTags   = 128
+	PeerMetricTagOthers = "others"
 )
 
 func RegisterNetwork() {
@@ -23,12 +33,19 @@ func RegisterNetwork() {
 	RegisterMetricView(msSend, view.Sum(), networkMks)
 	RegisterMetricView(msRecv, view.Count(), networkMks)
 	RegisterMetricView(msRecv, view.Sum(), networkMks)
+	RegisterMetricView(msPeerSend, view.Count(), networkPeerMks)
+	RegisterMetricView(msPeerSend, view.Sum(), networkPeerMks)
+	RegisterMetricView(msPeerRecv, view.Count(), networkPeerMks)
+	RegisterMetricView(msPeerRecv, view.Sum(), networkPeerMks)
 }
 
 type NetworkMetric struct {
 	ctx    context.Context
 	ctxMap map[string]context.Context
 	ctxMtx sync.RWMutex
+
+	peerCtxMap map[string]map[uint16]context.Context
+	peerCtxMtx sync.RWMutex
 }
 
 func (m *NetworkMetric) get(key string) (context.Context, bool) {
@@ -69,9 +86,55 @@ func (m *NetworkMetric) OnRecv(dest byte, ttl byte, hint byte, protocol uint16,
 	stats.Record(ctx, msRecv.M(int64(pktLen)))
 }
 
+func (m *NetworkMetric) getPeerMetricContext(peer string, protocol uint16) context.Context {
+	m.peerCtxMtx.RLock()
+	ctx, ok := m.peerCtxMap[m.peerTag(peer)][protocol]
+	m.peerCtxMtx.RUnlock()
+	if ok {
+		return ctx
+	}
+
+	m.peerCtxMtx.Lock()
+	defer m.peerCtxMtx.Unlock()
+	peer = m.peerTag(peer)
+	pm, ok := m.peerCtxMap[peer]
+	if !ok {
+		pm = make(map[uint16]context.Context)
+		m.peerCtxMap[peer] = pm
+	}
+	if ctx, ok = pm[protocol]; !ok {
+		ctx = GetMetricContext(m.ctx, &mkPeer, peer)
+		ctx = GetMetricContext(ctx, &mkProtocol, fmt.Sprintf("%#04x", protocol))
+		pm[protocol] = ctx
+	}
+	return ctx
+}
+
+// peerTag returns the tag value for the peer. Views keep rows of every tag
+// value even after the peer is closed, so only the first MaxPeerMetricTags
+// peers are tagged with their IDs, and the others are aggregated with
+// PeerMetricTagOthers. It must be called with peerCtxMtx held.
+func (m *NetworkMetric) peerTag(peer string) string {
+	if _, ok := m.peerCtxMap[peer]; ok || len(m.peerCtxMap) < MaxPeerMetricTags {
+		return peer
+	}
+	return PeerMetricTagOthers
+}
+
+func (m *NetworkMetric) OnPeerSend(peer string, protocol uint16, pktLen uint32) {
+	ctx := m.getPeerMetricContext(peer, protocol)
+	stats.Record(ctx, msPeerSend.M(int64(pktLen)))
+}
+
+func (m *NetworkMetric) OnPeerRecv(peer string, protocol uint16, pktLen uint32) {
+	ctx := m.getPeerMetricContext(peer, protocol)
+	stats.Record(ctx, msPeerRecv.M(int64(pktLen)))
+}
+
 func NewNetworkMetric(ctx context.Context) *NetworkMetric {
 	return &NetworkMetric{
-		ctx: ctx,
-		ctxMap: make(map[string]context.Context),
+		ctx:        ctx,
+		ctxMap:     make(map[string]context.Context),
+		peerCtxMap: make(map[string]map[uint16]context.Context),
 	}
 }
\ No newline at end of file
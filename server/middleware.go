@@ -3,9 +3,13 @@ package server
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -44,6 +48,168 @@ func ChainInjector(srv *Manager) echo.MiddlewareFunc {
 	}
 }
 
+type rpcRequestHeader struct {
+	ID     interface{} `json:"id"`
+	Method string      `json:"method"`
+}
+
+// rpcRequestsOf returns the headers of the requests in the raw message,
+// which may be a batch. Malformed requests are left to the handler.
+func rpcRequestsOf(raw json.RawMessage) []*rpcRequestHeader {
+	var reqs []*rpcRequestHeader
+	if err := json.Unmarshal(raw, &reqs); err != nil {
+		req := new(rpcRequestHeader)
+		if err := json.Unmarshal(raw, req); err != nil {
+			return nil
+		}
+		return []*rpcRequestHeader{req}
+	}
+	return reqs
+}
+
+// setRetryAfter sets Retry-After header and returns the seconds of it.
+func setRetryAfter(c echo.Context, retryAfter time.Duration) int64 {
+	secs := int64((retryAfter + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	c.Response().Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+	return secs
+}
+
+func limitExceeded(c echo.Context, reqs []*rpcRequestHeader, retryAfter time.Duration, msg string) error {
+	secs := setRetryAfter(c, retryAfter)
+	res := &jsonrpc.Response{
+		Version: jsonrpc.Version,
+		Error: jsonrpc.ErrorLackOfResource.New(msg,
+			map[string]interface{}{"retryAfter": secs}),
+	}
+	if len(reqs) == 1 {
+		res.ID = reqs[0].ID
+	}
+	return c.JSON(http.StatusTooManyRequests, res)
+}
+
+// batchTooLarge rejects the batch which can't be allowed by the limits even
+// if the client waits, so it's answered without Retry-After header.
+func batchTooLarge(c echo.Context, msg string) error {
+	res := &jsonrpc.Response{
+		Version: jsonrpc.Version,
+		Error:   jsonrpc.ErrorCodeInvalidRequest.New(msg),
+	}
+	return c.JSON(http.StatusBadRequest, res)
+}
+
+// RateLimit limits requests with token buckets for each client IP and for
+// each method of the client, and limits concurrent icx_call executions.
+// Rejected requests are answered with HTTP 429 with Retry-After header, and
+// the JSON-RPC error has retryAfter in seconds as its data. A batch which
+// can never be allowed, because it has more requests than the burst of the
+// limits, is answered with HTTP 400 without Retry-After header.
+// It must be placed after JsonRpc() and ChainInjector().
+func RateLimit(srv *Manager) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			raw, ok := c.Get("raw").(json.RawMessage)
+			if !ok {
+				return next(c)
+			}
+			reqs := rpcRequestsOf(raw)
+			methods := make([]string, 0, len(reqs))
+			calls := 0
+			for _, req := range reqs {
+				if req == nil {
+					continue
+				}
+				methods = append(methods, req.Method)
+				if req.Method == "icx_call" {
+					calls += 1
+				}
+			}
+
+			mctx := jsonrpc.NewContext(c).MetricContext()
+			if ok, method, d := srv.rl.Allow(c.RealIP(), methods); !ok {
+				srv.mtr.OnReject(mctx, method, "rate")
+				if d < 0 {
+					return batchTooLarge(c, fmt.Sprintf(
+						"Batch exceeds the rate limit for %s", method))
+				}
+				return limitExceeded(c, reqs, d, "Rate limit exceeded")
+			}
+			if calls > 0 {
+				if srv.rl.ExceedsMaxCalls(calls) {
+					srv.mtr.OnReject(mctx, "icx_call", "concurrency")
+					return batchTooLarge(c, "Batch exceeds the limit of concurrent calls")
+				}
+				if !srv.rl.AcquireCalls(calls) {
+					srv.mtr.OnReject(mctx, "icx_call", "concurrency")
+					return limitExceeded(c, reqs, callRetryAfter, "Too many concurrent calls")
+				}
+				defer srv.rl.ReleaseCalls(calls)
+			}
+			return next(c)
+		}
+	}
+}
+
+// RateLimitFor limits requests other than JSON-RPC, like websocket and
+// GraphQL, with the token buckets of the client. The request is counted as
+// a request of the method given by name, so it's limited by the rate for
+// each client and the rate for the method. Rejected requests are answered
+// with HTTP 429 with Retry-After header.
+func RateLimitFor(srv *Manager, name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if ok, _, d := srv.rl.Allow(c.RealIP(), []string{name}); !ok {
+				mctx := jsonrpc.NewContext(c).MetricContext()
+				srv.mtr.OnReject(mctx, name, "rate")
+				setRetryAfter(c, d)
+				return c.String(http.StatusTooManyRequests, "Rate limit exceeded")
+			}
+			return next(c)
+		}
+	}
+}
+
+// NewIPExtractor returns the extractor of the client IP for the limits.
+// Without trusted proxies, it uses the address of the connection and
+// ignores headers given by the client. Otherwise, it takes the client IP
+// from X-Forwarded-For header added by the proxies in the IP ranges given
+// in the form of "CIDR|IP[,CIDR|IP...]".
+func NewIPExtractor(proxies string) (echo.IPExtractor, error) {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, item := range strings.Split(proxies, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", item)
+			}
+			if ip.To4() != nil {
+				item += "/32"
+			} else {
+				item += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", item)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	if len(options) == 3 {
+		return echo.ExtractIPDirect(), nil
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 // Chunk()
 func Chunk() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -69,12 +235,11 @@ func NoneMiddlewareFunc(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-
 func Unauthorized(readOnly bool) echo.MiddlewareFunc {
 	if readOnly {
 		return func(next echo.HandlerFunc) echo.HandlerFunc {
 			return func(ctx echo.Context) error {
-				return ctx.String(http.StatusUnauthorized,"unauthorized")
+				return ctx.String(http.StatusUnauthorized, "unauthorized")
 			}
 		}
 	} else {
@@ -0,0 +1,243 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/common/cache"
+)
+
+const (
+	// maxClients is the maximum number of clients keeping their limiters.
+	// The limiters of the least recently seen clients are dropped first,
+	// and they start with full buckets when they are seen again.
+	maxClients = 10000
+	// callRetryAfter is the hint for clients rejected by the limit of
+	// concurrent calls.
+	callRetryAfter = time.Second
+)
+
+// ParseMethodRateLimit parses the rate limits for the methods in the form
+// of "method:rate[,method:rate...]". Rate is the number of requests per
+// second for each client.
+func ParseMethodRateLimit(s string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		kv := strings.SplitN(item, ":", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("invalid method rate limit %q", item)
+		}
+		v, err := strconv.Atoi(kv[1])
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid rate %q for %s", kv[1], kv[0])
+		}
+		limits[kv[0]] = v
+	}
+	return limits, nil
+}
+
+// FormatMethodRateLimit returns the string for the rate limits, which can
+// be parsed by ParseMethodRateLimit.
+func FormatMethodRateLimit(limits map[string]int) string {
+	items := make([]string, 0, len(limits))
+	for method, v := range limits {
+		items = append(items, method+":"+strconv.Itoa(v))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+type clientLimiter struct {
+	all     *rate.Limiter
+	methods map[string]*rate.Limiter
+}
+
+// rateLimiter limits requests with token buckets for each client and for
+// each method of the client. It also limits the number of concurrent
+// icx_call executions.
+type rateLimiter struct {
+	mtx         sync.Mutex
+	rate        int
+	methodRates map[string]int
+	clients     *cache.LRUCache
+
+	maxCalls int
+	calls    int
+}
+
+func newRateLimiter() *rateLimiter {
+	rl := new(rateLimiter)
+	rl.resetClientsInLock()
+	return rl
+}
+
+func newLimiter(r int) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(r), r)
+}
+
+func (rl *rateLimiter) SetRate(r int) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	rl.rate = r
+	rl.resetClientsInLock()
+}
+
+func (rl *rateLimiter) SetMethodRates(rates map[string]int) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	rl.methodRates = rates
+	rl.resetClientsInLock()
+}
+
+func (rl *rateLimiter) SetMaxCalls(n int) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	rl.maxCalls = n
+}
+
+func (rl *rateLimiter) hasRateInLock() bool {
+	return rl.rate > 0 || len(rl.methodRates) > 0
+}
+
+func (rl *rateLimiter) resetClientsInLock() {
+	r := rl.rate
+	rl.clients = cache.NewLRUCache(maxClients, func(string) (interface{}, error) {
+		cl := &clientLimiter{methods: make(map[string]*rate.Limiter)}
+		if r > 0 {
+			cl.all = newLimiter(r)
+		}
+		return cl, nil
+	})
+}
+
+func (rl *rateLimiter) clientInLock(ip string) *clientLimiter {
+	cl, _ := rl.clients.Get(ip)
+	return cl.(*clientLimiter)
+}
+
+// Allow consumes tokens for the methods requested by the client at once.
+// If any of the limits is exceeded, then it returns false with the method
+// and the duration to wait before retrying without consuming tokens.
+// The duration is negative if it requires more tokens than the burst of
+// the limit, so it would never be allowed.
+func (rl *rateLimiter) Allow(ip string, methods []string) (bool, string, time.Duration) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	if !rl.hasRateInLock() || len(methods) == 0 {
+		return true, "", 0
+	}
+	now := time.Now()
+	cl := rl.clientInLock(ip)
+
+	var rs []*rate.Reservation
+	cancel := func() {
+		for _, r := range rs {
+			r.CancelAt(now)
+		}
+	}
+	reserve := func(l *rate.Limiter, n int, method string) (bool, time.Duration) {
+		r := l.ReserveN(now, n)
+		if !r.OK() {
+			// more than the burst, it would never be allowed at once.
+			return false, -1
+		}
+		rs = append(rs, r)
+		if d := r.DelayFrom(now); d > 0 {
+			return false, d
+		}
+		return true, 0
+	}
+
+	if cl.all != nil {
+		if ok, d := reserve(cl.all, len(methods), ""); !ok {
+			cancel()
+			return false, methods[0], d
+		}
+	}
+	counts := make(map[string]int)
+	for _, method := range methods {
+		counts[method] += 1
+	}
+	for method, n := range counts {
+		r, ok := rl.methodRates[method]
+		if !ok || r <= 0 {
+			continue
+		}
+		l, ok := cl.methods[method]
+		if !ok {
+			l = newLimiter(r)
+			cl.methods[method] = l
+		}
+		if ok, d := reserve(l, n, method); !ok {
+			cancel()
+			return false, method, d
+		}
+	}
+	return true, "", 0
+}
+
+// ExceedsMaxCalls returns true if n concurrent icx_call executions are
+// more than the limit, so they would never be acquired at once.
+func (rl *rateLimiter) ExceedsMaxCalls(n int) bool {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	return rl.maxCalls > 0 && n > rl.maxCalls
+}
+
+// AcquireCalls occupies n slots of concurrent icx_call executions.
+// It returns false if there are no enough slots.
+func (rl *rateLimiter) AcquireCalls(n int) bool {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	if rl.maxCalls <= 0 {
+		return true
+	}
+	if rl.calls+n > rl.maxCalls {
+		return false
+	}
+	rl.calls += n
+	return true
+}
+
+// ReleaseCalls releases n slots occupied by AcquireCalls.
+func (rl *rateLimiter) ReleaseCalls(n int) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	rl.calls -= n
+	if rl.calls < 0 {
+		rl.calls = 0
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMethodRateLimit(t *testing.T) {
+	limits, err := ParseMethodRateLimit("icx_call:10, icx_getBalance:5")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"icx_call": 10, "icx_getBalance": 5}, limits)
+	assert.Equal(t, "icx_call:10,icx_getBalance:5", FormatMethodRateLimit(limits))
+
+	limits, err = ParseMethodRateLimit("")
+	assert.NoError(t, err)
+	assert.Empty(t, limits)
+
+	for _, s := range []string{"icx_call", "icx_call:x", ":10", "icx_call:-1"} {
+		_, err = ParseMethodRateLimit(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	rl := newRateLimiter()
+
+	ok, _, _ := rl.Allow("1.1.1.1", []string{"icx_call", "icx_call"})
+	assert.True(t, ok, "no limit")
+
+	rl.SetRate(2)
+	rl.SetMethodRates(map[string]int{"icx_call": 1})
+
+	ok, method, d := rl.Allow("1.1.1.1", []string{"icx_call", "icx_call"})
+	assert.False(t, ok)
+	assert.Equal(t, "icx_call", method)
+	assert.True(t, d < 0, "more than the burst of the method")
+
+	ok, _, _ = rl.Allow("1.1.1.1", []string{"icx_call"})
+	assert.True(t, ok, "tokens of rejected request shall be restored")
+
+	ok, method, _ = rl.Allow("1.1.1.1", []string{"icx_call"})
+	assert.False(t, ok)
+	assert.Equal(t, "icx_call", method)
+
+	ok, _, _ = rl.Allow("1.1.1.1", []string{"icx_getBalance"})
+	assert.True(t, ok)
+	ok, _, _ = rl.Allow("1.1.1.1", []string{"icx_getBalance"})
+	assert.False(t, ok, "limit for the client")
+
+	ok, _, _ = rl.Allow("2.2.2.2", []string{"icx_call", "icx_getBalance"})
+	assert.True(t, ok, "other client")
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	rl := newRateLimiter()
+	rl.SetRate(2)
+
+	ok, _, d := rl.Allow("1.1.1.1", []string{"icx_getBalance", "icx_getBalance", "icx_getBalance"})
+	assert.False(t, ok)
+	assert.True(t, d < 0, "more than the burst")
+
+	ok, _, _ = rl.Allow("1.1.1.1", []string{"icx_getBalance", "icx_getBalance"})
+	assert.True(t, ok)
+	ok, _, d = rl.Allow("1.1.1.1", []string{"icx_getBalance"})
+	assert.False(t, ok)
+	assert.True(t, d > 0)
+}
+
+func TestRateLimiter_MaxClients(t *testing.T) {
+	rl := newRateLimiter()
+	rl.SetRate(1)
+
+	ok, _, _ := rl.Allow("0.0.0.0", []string{"icx_call"})
+	assert.True(t, ok)
+	for i := 1; i <= maxClients+10; i++ {
+		rl.Allow(fmt.Sprintf("1.1.%d.%d", i/256, i%256), []string{"icx_call"})
+	}
+	assert.Equal(t, maxClients, rl.clients.Len())
+
+	ok, _, _ = rl.Allow("0.0.0.0", []string{"icx_call"})
+	assert.True(t, ok, "limiter of the least recently seen client is dropped")
+}
+
+func TestRateLimiter_Calls(t *testing.T) {
+	rl := newRateLimiter()
+	assert.True(t, rl.AcquireCalls(100))
+	rl.ReleaseCalls(100)
+
+	assert.False(t, rl.ExceedsMaxCalls(100))
+
+	rl.SetMaxCalls(2)
+	assert.False(t, rl.ExceedsMaxCalls(2))
+	assert.True(t, rl.ExceedsMaxCalls(3))
+	assert.True(t, rl.AcquireCalls(1))
+	assert.False(t, rl.AcquireCalls(2))
+	assert.True(t, rl.AcquireCalls(1))
+	assert.False(t, rl.AcquireCalls(1))
+	rl.ReleaseCalls(1)
+	assert.True(t, rl.AcquireCalls(1))
+}
+
+func TestNewIPExtractor(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	req.Header.Set("X-Real-IP", "5.6.7.8")
+
+	ipe, err := NewIPExtractor("")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", ipe(req))
+
+	ipe, err = NewIPExtractor("10.0.0.0/8")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", ipe(req))
+
+	ipe, err = NewIPExtractor("10.0.0.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", ipe(req))
+
+	for _, s := range []string{"10.0.0", "10.0.0.0/33"} {
+		_, err = NewIPExtractor(s)
+		assert.Error(t, err, s)
+	}
+}
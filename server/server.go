@@ -12,6 +12,7 @@ import (
 
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/graphql"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/server/v3"
 )
@@ -27,9 +28,14 @@ type Config struct {
 	JSONRPCDump           bool
 	JSONRPCIncludeDebug   bool
 	JSONRPCRosetta        bool
+	GraphQL               bool
 	JSONRPCDefaultChannel string
 	JSONRPCBatchLimit     int
 	WSMaxSession          int
+	RPCRateLimit          int
+	RPCMethodRateLimit    string
+	RPCCallConcurrency    int
+	RPCTrustedProxies     string
 }
 
 type Manager struct {
@@ -42,11 +48,13 @@ type Manager struct {
 	jsonrpcDefaultChannel string
 	jsonrpcMessageDump    int32
 	jsonrpcRosetta        int32
+	graphql               int32
 	jsonrpcIncludeDebug   int32
 	jsonrpcBatchLimit     int32
 	logger                log.Logger
 	metricsHandler        echo.HandlerFunc
 	mtr                   *metric.JsonrpcMetric
+	rl                    *rateLimiter
 }
 
 func NewManager(
@@ -61,6 +69,12 @@ func NewManager(
 
 	e.HTTPErrorHandler = HTTPErrorHandler
 	logger := l.WithFields(log.Fields{log.FieldKeyModule: "SR"})
+	if ipe, err := NewIPExtractor(config.RPCTrustedProxies); err != nil {
+		logger.Warnf("ignore invalid trusted proxies err=%+v", err)
+		e.IPExtractor = echo.ExtractIPDirect()
+	} else {
+		e.IPExtractor = ipe
+	}
 	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, false)
 	e.Logger.SetOutput(l.WriterLevel(log.DebugLevel))
 	m := &Manager{
@@ -75,10 +89,17 @@ func NewManager(
 		logger:                logger,
 		metricsHandler:        echo.WrapHandler(metric.PrometheusExporter()),
 		mtr:                   mtr,
+		rl:                    newRateLimiter(),
 	}
 	m.SetMessageDump(config.JSONRPCDump)
 	m.SetIncludeDebug(config.JSONRPCIncludeDebug)
 	m.SetRosetta(config.JSONRPCRosetta)
+	m.SetGraphQL(config.GraphQL)
+	m.SetRateLimit(config.RPCRateLimit)
+	if err := m.SetMethodRateLimit(config.RPCMethodRateLimit); err != nil {
+		logger.Warnf("ignore invalid method rate limit err=%+v", err)
+	}
+	m.SetCallConcurrency(config.RPCCallConcurrency)
 	return m
 }
 
@@ -167,6 +188,14 @@ func (srv *Manager) Rosetta() bool {
 	return atomicLoad(&srv.jsonrpcRosetta)
 }
 
+func (srv *Manager) SetGraphQL(enable bool) {
+	atomicStore(&srv.graphql, enable)
+}
+
+func (srv *Manager) GraphQL() bool {
+	return atomicLoad(&srv.graphql)
+}
+
 func (srv *Manager) SetBatchLimit(limitOfBatch int) {
 	atomic.StoreInt32(&srv.jsonrpcBatchLimit, int32(limitOfBatch))
 }
@@ -179,6 +208,29 @@ func (srv *Manager) SetWSMaxSession(limit int) {
 	srv.wssm.SetMaxSession(limit)
 }
 
+// SetRateLimit sets the number of requests per second for each client.
+// Zero disables the limit.
+func (srv *Manager) SetRateLimit(limit int) {
+	srv.rl.SetRate(limit)
+}
+
+// SetMethodRateLimit sets the number of requests per second for each method
+// of a client in the form of "method:rate[,method:rate...]".
+func (srv *Manager) SetMethodRateLimit(limits string) error {
+	rates, err := ParseMethodRateLimit(limits)
+	if err != nil {
+		return err
+	}
+	srv.rl.SetMethodRates(rates)
+	return nil
+}
+
+// SetCallConcurrency sets the number of concurrent icx_call executions.
+// Zero disables the limit.
+func (srv *Manager) SetCallConcurrency(limit int) {
+	srv.rl.SetMaxCalls(limit)
+}
+
 func (srv *Manager) Start() error {
 	srv.logger.Infoln("starting the server")
 	// CORS middleware
@@ -219,30 +271,42 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	mr := v3.MethodRepository(srv.mtr)
 	v3api := rpc.Group("/v3")
 	v3api.Use(JsonRpc(), Chunk())
-	v3api.POST("", mr.Handle, ChainInjector(srv))
-	v3api.POST("/", mr.Handle, ChainInjector(srv))
-	v3api.POST("/:channel", mr.Handle, ChainInjector(srv))
+	v3api.POST("", mr.Handle, ChainInjector(srv), RateLimit(srv))
+	v3api.POST("/", mr.Handle, ChainInjector(srv), RateLimit(srv))
+	v3api.POST("/:channel", mr.Handle, ChainInjector(srv), RateLimit(srv))
 
 	dmr := v3.DebugMethodRepository(srv.mtr)
 	v3dbg := rpc.Group("/v3d")
 	v3dbg.Use(srv.CheckDebug(), JsonRpc(), Chunk())
-	v3dbg.POST("", dmr.Handle, ChainInjector(srv))
-	v3dbg.POST("/", dmr.Handle, ChainInjector(srv))
-	v3dbg.POST("/:channel", dmr.Handle, ChainInjector(srv))
+	v3dbg.POST("", dmr.Handle, ChainInjector(srv), RateLimit(srv))
+	v3dbg.POST("/", dmr.Handle, ChainInjector(srv), RateLimit(srv))
+	v3dbg.POST("/:channel", dmr.Handle, ChainInjector(srv), RateLimit(srv))
 
 	// Rosetta APIs
 	rmr := v3.RosettaMethodRepository(srv.mtr)
 	rosetta := rpc.Group("/rosetta")
 	rosetta.Use(srv.CheckRosetta(), JsonRpc(), Chunk())
-	rosetta.POST("", rmr.Handle, ChainInjector(srv))
-	rosetta.POST("/", rmr.Handle, ChainInjector(srv))
-	rosetta.POST("/:channel", rmr.Handle, ChainInjector(srv))
+	rosetta.POST("", rmr.Handle, ChainInjector(srv), RateLimit(srv))
+	rosetta.POST("/", rmr.Handle, ChainInjector(srv), RateLimit(srv))
+	rosetta.POST("/:channel", rmr.Handle, ChainInjector(srv), RateLimit(srv))
+
+	// GraphQL API
+	gh := graphql.Handler(graphql.NewSchema())
+	gql := g.Group("/graphql")
+	// the handler limits the size of the body, so chunked bodies don't
+	// need to be read by Chunk in advance.
+	gql.Use(srv.CheckGraphQL())
+	for _, path := range []string{"", "/", "/:channel"} {
+		gql.GET(path, gh, ChainInjector(srv), RateLimitFor(srv, "graphql"))
+		gql.POST(path, gh, ChainInjector(srv), RateLimitFor(srv, "graphql"))
+	}
 
 	// group for websocket
 	ws := g.Group("")
-	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
-	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
-	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv))
+	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv), RateLimitFor(srv, "ws_block"))
+	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv), RateLimitFor(srv, "ws_event"))
+	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv), RateLimitFor(srv, "ws_btp"))
+	ws.GET("/v3/:channel/consensus", srv.wssm.RunConsensusSession, ChainInjector(srv), RateLimitFor(srv, "ws_consensus"))
 }
 
 func (srv *Manager) RegisterMetricsHandler(g *echo.Group) {
@@ -276,6 +340,17 @@ func (srv *Manager) CheckRosetta() echo.MiddlewareFunc {
 	}
 }
 
+func (srv *Manager) CheckGraphQL() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if !srv.GraphQL() {
+				return ctx.String(http.StatusNotFound, "graphql is false")
+			}
+			return next(ctx)
+		}
+	}
+}
+
 func (srv *Manager) Stop() error {
 	srv.logger.Infoln("shutting down the server")
 
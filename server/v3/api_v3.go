@@ -3,6 +3,7 @@ package v3
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/icon-project/goloop/block"
 	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/chain/index"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
@@ -52,6 +54,8 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getProofForResult", getProofForResult)
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
 	mr.RegisterMethod("icx_getScoreStatus", getScoreStatus)
+	mr.RegisterMethod("icx_getLogs", getLogs)
+	mr.RegisterMethod("icx_getTransactionsByAddress", getTransactionsByAddress)
 
 	mr.RegisterMethod("btp_getNetworkInfo", getBTPNetworkInfo)
 	mr.RegisterMethod("btp_getNetworkTypeInfo", getBTPNetworkTypeInfo)
@@ -196,6 +200,49 @@ func (c *contextWithSM) Init(ctx *jsonrpc.Context) error {
 	return nil
 }
 
+// StatePrunedError returns jsonrpc.ErrorCodeNotFound if err is
+// service.StatePrunedError for the state of the block. Otherwise,
+// it returns nil.
+func (c *contextWithSM) StatePrunedError(err error, blk module.Block) error {
+	if !service.StatePrunedError.Equals(err) {
+		return nil
+	}
+	return jsonrpc.ErrorCodeNotFound.Errorf("StatePruned(height=%d)", blk.Height())
+}
+
+// newTransitionForTrace returns the transition re-executing the block.
+// nblk is the next block of blk, which has patch transactions for blk.
+// If historical is true, then the transition is created on a temporary
+// layer over the state of the previous block, so it may fail with
+// the state pruned.
+func (c *contextWithSM) newTransitionForTrace(blk, nblk module.Block, historical bool) (module.Transition, error) {
+	csi, err := c.bm.NewConsensusInfo(blk)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	var tr1 module.Transition
+	if historical {
+		tc, ok := c.sm.(service.TraceTransitionCreator)
+		if !ok {
+			return nil, jsonrpc.ErrorCodeServer.New("HistoricalTraceNotSupported")
+		}
+		tr1, err = tc.CreateInitialTransitionForTrace(blk.Result(), blk.NextValidators())
+		if perr := c.StatePrunedError(err, blk); perr != nil {
+			return nil, perr
+		}
+	} else {
+		tr1, err = c.sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	}
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr2, err := c.sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return c.sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk), nil
+}
+
 type contextWithCS struct {
 	contextWithBM
 	cs module.Consensus
@@ -312,7 +359,9 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
 	result, err := c.sm.Call(blk.Result(), blk.NextValidators(), params.RawMessage(), bi)
 	if err != nil {
-		if service.InvalidQueryError.Equals(err) {
+		if perr := c.StatePrunedError(err, blk); perr != nil {
+			return nil, perr
+		} else if service.InvalidQueryError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 		} else if scoreresult.IsValid(err) {
 			return nil, jsonrpc.ErrScore(err, c.debug)
@@ -343,6 +392,9 @@ func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, erro
 
 	b, err := c.sm.GetBalance(blk.Result(), param.Address.Address())
 	if err != nil {
+		if perr := c.StatePrunedError(err, blk); perr != nil {
+			return nil, perr
+		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
 	balance.Set(b)
@@ -365,6 +417,9 @@ func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, err
 		return nil, err
 	}
 	info, err := c.sm.GetAPIInfo(b.Result(), param.Address.Address())
+	if perr := c.StatePrunedError(err, b); perr != nil {
+		return nil, perr
+	}
 	if service.NoActiveContractError.Equals(err) {
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, c.debug)
 	}
@@ -400,6 +455,9 @@ func getTotalSupply(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	var tsValue common.HexInt
 	ts, err := c.sm.GetTotalSupply(b.Result())
 	if err != nil {
+		if perr := c.StatePrunedError(err, b); perr != nil {
+			return nil, perr
+		}
 		return nil, c.AsRPCError(err)
 	}
 	tsValue.Set(ts)
@@ -719,6 +777,9 @@ func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	}
 	s, err := c.sm.GetSCOREStatus(b.Result(), param.Address.Address())
 	if err != nil {
+		if perr := c.StatePrunedError(err, b); perr != nil {
+			return nil, perr
+		}
 		return nil, c.AsRPCError(err)
 	}
 	jso, err := s.ToJSON(b.Height(), module.JSONVersion3)
@@ -728,6 +789,295 @@ func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	return jso, nil
 }
 
+const (
+	ConfigDefaultLogsLimit = 100
+	ConfigMaxLogsLimit     = 1000
+
+	// ConfigMaxLogsScan is the maximum number of indexed logs checked with
+	// the filter in a request. It returns the cursor to continue if there
+	// are more logs to check.
+	ConfigMaxLogsScan = 10000
+)
+
+type logResult struct {
+	BlockHeight common.HexInt64 `json:"blockHeight"`
+	BlockHash   common.HexBytes `json:"blockHash"`
+	TxHash      common.HexBytes `json:"txHash"`
+	TxIndex     common.HexInt32 `json:"txIndex"`
+	LogIndex    common.HexInt32 `json:"logIndex"`
+	EventLog    module.EventLog `json:"eventLog"`
+}
+
+type logsResult struct {
+	Logs   []*logResult    `json:"logs"`
+	Cursor common.HexBytes `json:"cursor,omitempty"`
+}
+
+const logCursorLen = 16
+
+func logCursorOf(loc index.LogLocator) []byte {
+	bs := make([]byte, logCursorLen)
+	binary.BigEndian.PutUint64(bs[0:8], uint64(loc.Height))
+	binary.BigEndian.PutUint32(bs[8:12], uint32(loc.TxIndex))
+	binary.BigEndian.PutUint32(bs[12:16], uint32(loc.LogIndex))
+	return bs
+}
+
+func logLocatorOf(cursor []byte) (index.LogLocator, error) {
+	if len(cursor) != logCursorLen {
+		return index.LogLocator{}, errors.IllegalArgumentError.Errorf(
+			"InvalidCursor(cursor=%#x)", cursor)
+	}
+	return index.LogLocator{
+		Height:   int64(binary.BigEndian.Uint64(cursor[0:8])),
+		TxIndex:  int(binary.BigEndian.Uint32(cursor[8:12])),
+		LogIndex: int(binary.BigEndian.Uint32(cursor[12:16])),
+	}, nil
+}
+
+func eventLogAt(r module.Receipt, idx int) (module.EventLog, error) {
+	for it, i := r.EventLogIterator(), 0; it.Has(); _, i = it.Next(), i+1 {
+		if i == idx {
+			return it.Get()
+		}
+	}
+	return nil, errors.NotFoundError.Errorf("NoEventLog(idx=%d)", idx)
+}
+
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param LogsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	filter := &EventFilter{
+		Signature: param.Event,
+		Indexed:   param.Indexed,
+		Data:      param.Data,
+	}
+	var addr module.Address
+	if param.Address != "" {
+		addr = param.Address.Address()
+		filter.Addr = common.AddressToPtr(addr)
+	}
+	if err := filter.Compile(); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	li, err := index.NewLogIndex(c.chain.Database())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	lower, upper, err := li.Range()
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeServer.New("LogIndexNotEnabled")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	from, err := param.FromBlock.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	to := upper - 1
+	if param.ToBlock != "" {
+		if to, err = param.ToBlock.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+	}
+	if from > to {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidRange(from=%d,to=%d)", from, to)
+	}
+	if from < lower || to >= upper {
+		return nil, jsonrpc.ErrorCodeNotFound.Errorf(
+			"NotIndexed(from=%d,to=%d,lower=%d,upper=%d)", from, to, lower, upper)
+	}
+
+	limit := int64(ConfigDefaultLogsLimit)
+	if param.Limit != "" {
+		if limit, err = param.Limit.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if limit <= 0 || limit > ConfigMaxLogsLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidLimit(limit=%d,max=%d)", limit, ConfigMaxLogsLimit)
+		}
+	}
+	var start index.LogLocator
+	if param.Cursor != "" {
+		if start, err = logLocatorOf(param.Cursor.Bytes()); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if start.Height > from {
+			from = start.Height
+		}
+	}
+
+	result := &logsResult{
+		Logs: []*logResult{},
+	}
+	var blk module.Block
+	var rl module.ReceiptList
+	scanned := 0
+	err = li.Find(addr, []byte(filter.Signature), from, to, func(loc index.LogLocator) (bool, error) {
+		if loc.Compare(start) < 0 {
+			return true, nil
+		}
+		if scanned >= ConfigMaxLogsScan {
+			result.Cursor = logCursorOf(loc)
+			return false, nil
+		}
+		scanned++
+		if blk == nil || blk.Height() != loc.Height {
+			var err error
+			if blk, err = c.bm.GetBlockByHeight(loc.Height); err != nil {
+				return false, err
+			}
+			rblk, err := c.bm.GetBlockByHeight(loc.Height + 1)
+			if err != nil {
+				return false, err
+			}
+			rl, err = c.sm.ReceiptListFromResult(rblk.Result(), module.TransactionGroupNormal)
+			if err != nil {
+				return false, err
+			}
+		}
+		r, err := rl.Get(loc.TxIndex)
+		if err != nil {
+			return false, err
+		}
+		el, err := eventLogAt(r, loc.LogIndex)
+		if err != nil {
+			return false, err
+		}
+		if !filter.MatchLog(el) {
+			return true, nil
+		}
+		if int64(len(result.Logs)) >= limit {
+			result.Cursor = logCursorOf(loc)
+			return false, nil
+		}
+		tx, err := blk.NormalTransactions().Get(loc.TxIndex)
+		if err != nil {
+			return false, err
+		}
+		result.Logs = append(result.Logs, &logResult{
+			BlockHeight: common.HexInt64{Value: blk.Height()},
+			BlockHash:   blk.ID(),
+			TxHash:      tx.ID(),
+			TxIndex:     common.HexInt32{Value: int32(loc.TxIndex)},
+			LogIndex:    common.HexInt32{Value: int32(loc.LogIndex)},
+			EventLog:    el,
+		})
+		return true, nil
+	})
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	return result, nil
+}
+
+const (
+	ConfigDefaultTxsLimit = 20
+	ConfigMaxTxsLimit     = 100
+)
+
+type transactionsResult struct {
+	Total        common.HexInt64  `json:"total"`
+	Transactions []interface{}    `json:"transactions"`
+	Cursor       *common.HexInt64 `json:"cursor,omitempty"`
+}
+
+func getTransactionsByAddress(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithBM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param TransactionsByAddressParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	ti, err := index.NewTxIndex(c.chain.Database())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	if _, err := ti.Height(); errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeServer.New("TxIndexNotEnabled")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	addr := param.Address.Address()
+	total, err := ti.Count(addr)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	limit := int64(ConfigDefaultTxsLimit)
+	if param.Limit != "" {
+		if limit, err = param.Limit.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if limit <= 0 || limit > ConfigMaxTxsLimit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidLimit(limit=%d,max=%d)", limit, ConfigMaxTxsLimit)
+		}
+	}
+	before := total
+	if param.Cursor != "" {
+		if before, err = param.Cursor.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if before < 0 || before > total {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidCursor(cursor=%d,total=%d)", before, total)
+		}
+	}
+
+	result := &transactionsResult{
+		Total:        common.HexInt64{Value: total},
+		Transactions: []interface{}{},
+	}
+	var blk module.Block
+	err = ti.Find(addr, before, func(pos int64, loc index.TxLocator) (bool, error) {
+		if int64(len(result.Transactions)) >= limit {
+			result.Cursor = &common.HexInt64{Value: pos + 1}
+			return false, nil
+		}
+		if blk == nil || blk.Height() != loc.Height {
+			var err error
+			if blk, err = c.bm.GetBlockByHeight(loc.Height); err != nil {
+				return false, err
+			}
+		}
+		tx, err := blk.NormalTransactions().Get(loc.TxIndex)
+		if err != nil {
+			return false, err
+		}
+		res, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return false, err
+		}
+		jso := res.(map[string]interface{})
+		jso["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
+		jso["blockHeight"] = "0x" + strconv.FormatInt(blk.Height(), 16)
+		jso["txIndex"] = "0x" + strconv.FormatInt(int64(loc.TxIndex), 16)
+		result.Transactions = append(result.Transactions, jso)
+		return true, nil
+	})
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	return result, nil
+}
+
 func getBTPNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1111,18 +1461,39 @@ func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository
 	RegisterValidationRule(mr.Validator())
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
+	mr.RegisterMethod("debug_traceTransaction", traceTransaction)
+	mr.RegisterMethod("debug_getStateDiff", getStateDiff)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
 
 	return mr
 }
 
+const (
+	ConfigTraceTimeout           = time.Second * 5
+	ConfigHistoricalTraceTimeout = time.Second * 60
+)
+
 func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	return doTraceTransaction(ctx, params, false)
+}
+
+// traceTransaction re-executes the block of the transaction on a scratch
+// layer over the state of the previous block, so it works for any
+// transaction whose pre-state is kept.
+// The trace isn't streamed. Only the trace of the transaction is collected,
+// so it's returned at once like debug_getTrace, and JSON-RPC has no way to
+// send a response in parts.
+func traceTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	return doTraceTransaction(ctx, params, true)
+}
+
+func doTraceTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params, historical bool) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
 		return nil, err
 	}
 
-	var param TransactionHashParam
+	var param TraceParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
@@ -1151,49 +1522,127 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
-	csi, err := c.bm.NewConsensusInfo(blk)
+	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
-	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+	tr2, err := c.newTransitionForTrace(blk, nblk, historical)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		return nil, err
 	}
-	tr1, err := c.sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	timeout := ConfigTraceTimeout
+	if historical {
+		timeout = ConfigHistoricalTraceTimeout
+	}
+
+	cb := &traceCallback{
+		channel: make(chan interface{}, 10),
+	}
+	ti := module.TraceInfo{
+		Range:    module.TraceRangeTransaction,
+		Group:    txInfo.Group(),
+		Index:    txInfo.Index(),
+		Callback: cb,
+	}
+	if param.Mode == "call" {
+		cb.ct = trace.NewCallTracer()
+		ti.TraceMode = module.TraceModeCall
+	} else {
+		cb.logs = make([]interface{}, 0, 100)
+		ti.TraceMode = module.TraceModeInvoke
+	}
+	canceller, err := tr2.ExecuteForTrace(ti)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
-	tr2, err := c.sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
+
+	timer := time.After(timeout)
+	for {
+		select {
+		case <-timer:
+			canceller()
+			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
+				"Not enough time to get result of %x", param.Hash.Bytes())
+		case <-cb.channel:
+			if cb.ct != nil {
+				return cb.callTraceToJSON(), nil
+			}
+			return cb.invokeTraceToJSON(), nil
+		}
+	}
+}
+
+func getStateDiff(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param StateDiffParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	var blk module.Block
+	var err error
+	if len(param.Hash) > 0 {
+		blk, err = c.GetBlockByID(param.Hash.Bytes())
+	} else if len(param.Height) > 0 {
+		blk, err = c.GetBlockByHeight(param.Height)
+	} else {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NoBlockHashOrHeight")
+	}
 	if err != nil {
+		return nil, err
+	}
+	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
-	tr2 = c.sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk)
+	tr, err := c.newTransitionForTrace(blk, nblk, true)
+	if err != nil {
+		return nil, err
+	}
 
 	cb := &traceCallback{
-		logs:    make([]interface{}, 0, 100),
 		channel: make(chan interface{}, 10),
+		sd:      trace.NewStateDiffTracer(),
 	}
 	ti := module.TraceInfo{
-		TraceMode: module.TraceModeInvoke,
-		Range:     module.TraceRangeTransaction,
-		Group:     txInfo.Group(),
-		Index:     txInfo.Index(),
+		TraceMode: module.TraceModeStateDiff,
+		Range:     module.TraceRangeBlock,
 		Callback:  cb,
 	}
-	canceller, err := tr2.ExecuteForTrace(ti)
-	if err != nil {
+	if len(param.TxIndex) > 0 {
+		idx, err := param.TxIndex.ParseInt(32)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		ti.Range = module.TraceRangeTransaction
+		ti.Group = module.TransactionGroupNormal
+		ti.Index = int(idx)
+	}
+	canceller, err := tr.ExecuteForTrace(ti)
+	if errors.IllegalArgumentError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
 
-	timer := time.After(time.Second * 5)
+	timer := time.After(ConfigHistoricalTraceTimeout)
 	for {
 		select {
 		case <-timer:
 			canceller()
 			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
-				"Not enough time to get result of %x", param.Hash.Bytes())
+				"Not enough time to get state diff of height=%d", blk.Height())
 		case <-cb.channel:
-			return cb.invokeTraceToJSON(), nil
+			if cb.last != nil {
+				return nil, jsonrpc.ErrorCodeSystem.Wrap(cb.last, c.debug)
+			}
+			return cb.stateDiffToJSON(blk), nil
 		}
 	}
 }
@@ -43,10 +43,38 @@ type ScoreAddressParam struct {
 	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
 }
 
+type LogsParam struct {
+	FromBlock jsonrpc.HexInt   `json:"fromBlock" validate:"required,t_int"`
+	ToBlock   jsonrpc.HexInt   `json:"toBlock,omitempty" validate:"optional,t_int"`
+	Address   jsonrpc.Address  `json:"addr,omitempty" validate:"optional,t_addr_score"`
+	Event     string           `json:"event" validate:"required"`
+	Indexed   []*string        `json:"indexed,omitempty"`
+	Data      []*string        `json:"data,omitempty"`
+	Limit     jsonrpc.HexInt   `json:"limit,omitempty" validate:"optional,t_int"`
+	Cursor    jsonrpc.HexBytes `json:"cursor,omitempty"`
+}
+
+type TransactionsByAddressParam struct {
+	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	Limit   jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+	Cursor  jsonrpc.HexInt  `json:"cursor,omitempty" validate:"optional,t_int"`
+}
+
 type TransactionHashParam struct {
 	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
 }
 
+type TraceParam struct {
+	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Mode string           `json:"mode,omitempty" validate:"optional,oneof=invoke call"`
+}
+
+type StateDiffParam struct {
+	Hash    jsonrpc.HexBytes `json:"hash,omitempty" validate:"optional,t_hash"`
+	Height  jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+	TxIndex jsonrpc.HexInt   `json:"txIndex,omitempty" validate:"optional,t_int"`
+}
+
 type TransactionParamForEstimate struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
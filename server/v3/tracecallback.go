@@ -19,6 +19,8 @@ type traceCallback struct {
 	ts      time.Time
 	channel chan interface{}
 	bt      *trace.BalanceTracer
+	ct      *trace.CallTracer
+	sd      *trace.StateDiffTracer
 }
 
 type traceLog struct {
@@ -69,6 +71,40 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 	return result
 }
 
+func (t *traceCallback) callTraceToJSON() interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := make(map[string]interface{})
+	if txs := t.ct.ToJSON(); len(txs) > 0 {
+		for k, v := range txs[0] {
+			result[k] = v
+		}
+	}
+	if t.last == nil {
+		result["status"] = "0x1"
+	} else {
+		result["status"] = "0x0"
+		status, _ := scoreresult.StatusOf(t.last)
+		result["failure"] = map[string]interface{}{
+			"code":    status,
+			"message": t.last.Error(),
+		}
+	}
+	return result
+}
+
+func (t *traceCallback) stateDiffToJSON(blk module.Block) interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return map[string]interface{}{
+		"blockHash":    "0x" + hex.EncodeToString(blk.ID()),
+		"blockHeight":  fmt.Sprintf("%#x", blk.Height()),
+		"transactions": t.sd.ToJSON(),
+	}
+}
+
 func (t *traceCallback) balanceChangeToJSON(blk module.Block) interface{} {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -94,6 +130,16 @@ func (t *traceCallback) OnTransactionStart(txIndex int, txHash []byte, isBlockTx
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionStart(txIndex, txHash, isBlockTx)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionStart(txIndex, txHash, isBlockTx)
+	}
+	if t.sd != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.sd.OnTransactionStart(txIndex, txHash, isBlockTx)
+	}
 	return nil
 }
 
@@ -105,6 +151,12 @@ func (t *traceCallback) OnTransactionReset() error {
 	if t.bt != nil {
 		return t.bt.OnTransactionReset()
 	}
+	if t.ct != nil {
+		return t.ct.OnTransactionReset()
+	}
+	if t.sd != nil {
+		return t.sd.OnTransactionReset()
+	}
 	return nil
 }
 
@@ -114,6 +166,16 @@ func (t *traceCallback) OnTransactionEnd(txIndex int, txHash []byte) error {
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionEnd(txIndex, txHash)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionEnd(txIndex, txHash)
+	}
+	if t.sd != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.sd.OnTransactionEnd(txIndex, txHash)
+	}
 	return nil
 }
 
@@ -123,6 +185,11 @@ func (t *traceCallback) OnFrameEnter() error {
 		defer t.lock.Unlock()
 		return t.bt.OnFrameEnter()
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnFrameEnter()
+	}
 	return nil
 }
 
@@ -132,6 +199,11 @@ func (t *traceCallback) OnFrameExit(success bool) error {
 		defer t.lock.Unlock()
 		return t.bt.OnFrameExit(success)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnFrameExit(success)
+	}
 	return nil
 }
 
@@ -143,3 +215,57 @@ func (t *traceCallback) OnBalanceChange(opType module.OpType, from, to module.Ad
 	}
 	return nil
 }
+
+func (t *traceCallback) OnCallStart(from, to module.Address, value *big.Int, method string, params interface{}) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallStart(from, to, value, method, params)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnCallEnd(stepUsed *big.Int, status error) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallEnd(stepUsed, status)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnEvent(addr module.Address, indexed, data [][]byte) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnEvent(addr, indexed, data)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnBalanceDiff(addr module.Address, old, new *big.Int) error {
+	if t.sd != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.sd.OnBalanceDiff(addr, old, new)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnStorageDiff(addr module.Address, key, old, new []byte) error {
+	if t.sd != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.sd.OnStorageDiff(addr, key, old, new)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnContractDiff(addr module.Address, old, new interface{}) error {
+	if t.sd != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.sd.OnContractDiff(addr, old, new)
+	}
+	return nil
+}
@@ -103,7 +103,7 @@ loop:
 			}
 			lb := blk.LogsBloom()
 			for i, f := range br.EventFilters {
-				if lb.Contain(f.lb) {
+				if lb.Contain(f.LogsBloom()) {
 					if rl == nil {
 						rl, err = sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
 						if err != nil {
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	wsConsensusEventBufferSize = 128
+	wsConsensusStatus          = "status"
+)
+
+var wsConsensusEventTypes = []string{
+	module.ConsensusEventStep,
+	module.ConsensusEventProposal,
+	module.ConsensusEventVote,
+	module.ConsensusEventTimeout,
+}
+
+type ConsensusRequest struct {
+	// Events are the types of the events to be notified. All types of
+	// the events are notified if it's empty.
+	Events []string `json:"events,omitempty"`
+	events map[string]bool
+}
+
+func (r *ConsensusRequest) Compile() error {
+	if len(r.Events) == 0 {
+		return nil
+	}
+	r.events = make(map[string]bool)
+	for _, e := range r.Events {
+		found := false
+		for _, t := range wsConsensusEventTypes {
+			if e == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown event type(%s)", e)
+		}
+		r.events[e] = true
+	}
+	return nil
+}
+
+func (r *ConsensusRequest) Match(ev *module.ConsensusEvent) bool {
+	return r.events == nil || r.events[ev.Type]
+}
+
+type ConsensusNotification struct {
+	Type             string           `json:"type"`
+	Height           common.HexInt64  `json:"height"`
+	Round            common.HexInt32  `json:"round"`
+	Step             string           `json:"step,omitempty"`
+	Proposer         bool             `json:"proposer,omitempty"`
+	Validators       []module.Address `json:"validators,omitempty"`
+	ProposedBy       module.Address   `json:"proposedBy,omitempty"`
+	BlockPartSetHash common.HexBytes  `json:"blockPartSetHash,omitempty"`
+	VoteType         string           `json:"voteType,omitempty"`
+	Votes            *common.HexInt32 `json:"votes,omitempty"`
+	Voted            string           `json:"voted,omitempty"`
+}
+
+// votedString returns the flags of voted validators in the form of "1101".
+func votedString(voted []bool) string {
+	bs := make([]byte, len(voted))
+	for i, v := range voted {
+		if v {
+			bs[i] = '1'
+		} else {
+			bs[i] = '0'
+		}
+	}
+	return string(bs)
+}
+
+func newConsensusNotification(ev *module.ConsensusEvent) *ConsensusNotification {
+	cn := &ConsensusNotification{
+		Type:             ev.Type,
+		Height:           common.HexInt64{Value: ev.Height},
+		Round:            common.HexInt32{Value: ev.Round},
+		Step:             ev.Step,
+		Validators:       ev.Validators,
+		ProposedBy:       ev.Proposer,
+		BlockPartSetHash: ev.BlockPartSetHash,
+		VoteType:         ev.VoteType,
+	}
+	if ev.Type == module.ConsensusEventVote {
+		cn.Votes = &common.HexInt32{Value: int32(ev.Votes)}
+		cn.Voted = votedString(ev.Voted)
+	}
+	return cn
+}
+
+func (wm *wsSessionManager) RunConsensusSession(ctx echo.Context) error {
+	var cr ConsensusRequest
+	wss, err := wm.initSession(ctx, &cr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if err := cr.Compile(); err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), err.Error())
+		return nil
+	}
+
+	cs := wss.chain.Consensus()
+	if cs == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+	cw, ok := cs.(module.ConsensusEventWatcher)
+	if !ok {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "NotSupported")
+		return nil
+	}
+	evch, stop := cw.WatchEvents(wsConsensusEventBufferSize)
+	defer stop()
+
+	_ = wss.response(0, "")
+
+	if st := cs.GetStatus(); st != nil {
+		if err := wss.WriteJSON(&ConsensusNotification{
+			Type:     wsConsensusStatus,
+			Height:   common.HexInt64{Value: st.Height},
+			Round:    common.HexInt32{Value: st.Round},
+			Proposer: st.Proposer,
+		}); err != nil {
+			wm.logger.Infof("fail to write json ConsensusNotification err:%+v\n", err)
+			return nil
+		}
+	}
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case ev, ok := <-evch:
+			if !ok {
+				_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+				break loop
+			}
+			if !cr.Match(ev) {
+				continue
+			}
+			if err = wss.WriteJSON(newConsensusNotification(ev)); err != nil {
+				wm.logger.Infof("fail to write json ConsensusNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+func TestConsensusRequest_Compile(t *testing.T) {
+	r := &ConsensusRequest{}
+	assert.NoError(t, r.Compile())
+	assert.True(t, r.Match(&module.ConsensusEvent{Type: module.ConsensusEventVote}))
+
+	r = &ConsensusRequest{Events: []string{module.ConsensusEventStep, module.ConsensusEventTimeout}}
+	assert.NoError(t, r.Compile())
+	assert.True(t, r.Match(&module.ConsensusEvent{Type: module.ConsensusEventTimeout}))
+	assert.False(t, r.Match(&module.ConsensusEvent{Type: module.ConsensusEventVote}))
+
+	r = &ConsensusRequest{Events: []string{"unknown"}}
+	assert.Error(t, r.Compile())
+}
+
+type testConsensus struct {
+	module.Consensus
+	ch chan *module.ConsensusEvent
+}
+
+func (c *testConsensus) GetStatus() *module.ConsensusStatus {
+	return &module.ConsensusStatus{Height: 10, Round: 1}
+}
+
+func (c *testConsensus) WatchEvents(size int) (<-chan *module.ConsensusEvent, func()) {
+	return c.ch, func() {}
+}
+
+type testConsensusChain struct {
+	module.Chain
+	cs module.Consensus
+}
+
+func (c *testConsensusChain) Consensus() module.Consensus {
+	return c.cs
+}
+
+func TestWsSessionManager_RunConsensusSession(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	cch := make(chan *testWebSocketConn, 1)
+	upgrader := newTestWebsocketUpgrader(func(ctx echo.Context, conn *testWebSocketConn) {
+		cch <- conn
+	})
+	wm := newWSSessionManagerWithUpgrader(logger, 2, upgrader)
+	cs := &testConsensus{ch: make(chan *module.ConsensusEvent, 3)}
+	chain := &testConsensusChain{cs: cs}
+
+	done := make(chan struct{})
+	go func() {
+		_ = wm.RunConsensusSession(newTestContext(chain))
+		close(done)
+	}()
+	conn := <-cch
+	assert.NoError(t, conn.clientWriteJSON(&ConsensusRequest{
+		Events: []string{module.ConsensusEventVote},
+	}))
+
+	var res WSResponse
+	bs, err := conn.clientRead()
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(bs, &res))
+	assert.Equal(t, 0, res.Code)
+
+	var cn ConsensusNotification
+	bs, err = conn.clientRead()
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(bs, &cn))
+	assert.Equal(t, wsConsensusStatus, cn.Type)
+	assert.Equal(t, common.HexInt64{Value: 10}, cn.Height)
+
+	// step is filtered out
+	cs.ch <- &module.ConsensusEvent{
+		Type:   module.ConsensusEventStep,
+		Height: 10,
+		Round:  1,
+		Step:   "Prevote",
+	}
+	cs.ch <- &module.ConsensusEvent{
+		Type:     module.ConsensusEventVote,
+		Height:   10,
+		Round:    1,
+		Step:     "Prevote",
+		VoteType: "PreVote",
+		Votes:    2,
+		Voted:    []bool{true, false, true, false},
+	}
+	bs, err = conn.clientRead()
+	assert.NoError(t, err)
+	cn = ConsensusNotification{}
+	assert.NoError(t, json.Unmarshal(bs, &cn))
+	assert.Equal(t, module.ConsensusEventVote, cn.Type)
+	assert.Equal(t, "PreVote", cn.VoteType)
+	assert.Equal(t, &common.HexInt32{Value: 2}, cn.Votes)
+	assert.Equal(t, "1010", cn.Voted)
+
+	// session stops when consensus stops
+	close(cs.ch)
+	bs, err = conn.clientRead()
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(bs, &res))
+	assert.Equal(t, int(jsonrpc.ErrorCodeServer), res.Code)
+	<-done
+}
@@ -148,18 +148,23 @@ func (cc *callContext) pushFrame(handler ContractHandler, limit *big.Int) *callF
 		frame.snapshot = cc.GetSnapshot()
 	}
 	logger.OnFrameEnter(cc.frame.fid)
+	if ci, ok := handler.(callInfoProvider); ok && logger.TraceMode() == module.TraceModeCall {
+		logger.OnCallStart(ci.callInfo())
+	}
 	frame.fid = cc.nextFID
 	cc.nextFID += 1
 	cc.frame = frame
 	return frame
 }
 
-func (cc *callContext) popFrame(success bool) *callFrame {
+func (cc *callContext) popFrame(status error) *callFrame {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
 
 	frame := cc.frame
-	cc.frame.log.OnFrameExit(success, &frame.stepUsed)
+	success := status == nil
+	frame.log.OnCallEnd(&frame.stepUsed, status)
+	frame.log.OnFrameExit(success, &frame.stepUsed)
 	if !frame.isReadOnly {
 		if success {
 			frame.parent.applyFrameLogsOf(frame)
@@ -214,6 +219,7 @@ func (cc *callContext) addLogToFrame(addr module.Address, indexed [][]byte, data
 		addr, indexed[0],
 		common.SliceOfHexBytes(indexed[1:]),
 		common.SliceOfHexBytes(data))
+	cc.frame.log.OnEvent(addr, indexed, data)
 	cc.frame.addLog(addr, indexed, data)
 	return nil
 }
@@ -385,7 +391,7 @@ func (cc *callContext) handleResult(target *callFrame, status error, result *cod
 		return false
 	}
 
-	current := cc.popFrame(status == nil)
+	current := cc.popFrame(status)
 	if current == nil {
 		return false
 	}
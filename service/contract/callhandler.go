@@ -363,6 +363,15 @@ func (h *CallHandler) ensureMethodAndParams(eeType state.EEType) error {
 	return err
 }
 
+func (h *CallHandler) callInfo() (from, to module.Address, value *big.Int, method string, params interface{}) {
+	if h.paramObj != nil {
+		params, _ = common.DecodeAnyForJSON(h.paramObj)
+	} else if h.params != nil {
+		params = json.RawMessage(h.params)
+	}
+	return h.From, h.To, h.Value, h.name, params
+}
+
 func (h *CallHandler) GetMethodName() string {
 	return h.name
 }
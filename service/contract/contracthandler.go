@@ -44,6 +44,10 @@ type (
 		EEType() state.EEType
 		eeproxy.CallContext
 	}
+
+	callInfoProvider interface {
+		callInfo() (from, to module.Address, value *big.Int, method string, params interface{})
+	}
 )
 
 type CommonHandler struct {
@@ -61,6 +65,11 @@ func NewCommonHandler(from, to module.Address, value *big.Int, call bool, log lo
 		Log: trace.LoggerOf(log)}
 }
 
+// callInfo returns the details of the call for TraceModeCall.
+func (h *CommonHandler) callInfo() (from, to module.Address, value *big.Int, method string, params interface{}) {
+	return h.From, h.To, h.Value, "", nil
+}
+
 func (h *CommonHandler) Prepare(ctx Context) (state.WorldContext, error) {
 	lq := []state.LockRequest{
 		{string(h.From.ID()), state.AccountWriteLock},
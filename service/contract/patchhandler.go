@@ -2,8 +2,10 @@ package contract
 
 import (
 	"encoding/json"
+	"math/big"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/scoredb"
 	"github.com/icon-project/goloop/service/scoreresult"
@@ -77,6 +79,74 @@ func (h *patchHandler) handleSkipTransaction(cc CallContext) error {
 	return nil
 }
 
+func (h *patchHandler) handleDoubleSign(cc CallContext) error {
+	decode := cc.PatchDecoder()
+	if decode == nil {
+		h.Log.Warn("PatchHandler: patch decoder isn't set")
+		return scoreresult.InvalidParameterError.New("PatchDecoderIsNil")
+	}
+	pd, err := decode(h.patch.Type, h.patch.Data)
+	if err != nil {
+		h.Log.Warnf("PatchHandler: decode fail err=%+v", err)
+		return scoreresult.InvalidParameterError.Wrap(err, "DecodeFail")
+	}
+	p := pd.(module.DoubleSignPatch)
+	if p.Height() >= cc.BlockHeight() || p.Height() < 1 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidHeight(bh=%d,ph=%d)",
+			cc.BlockHeight(), p.Height())
+	}
+	if p.Height() < cc.BlockHeight()-module.DoubleSignEvidenceWindow {
+		return scoreresult.InvalidParameterError.Errorf("TooOldEvidence(bh=%d,ph=%d)",
+			cc.BlockHeight(), p.Height())
+	}
+	// validators of the height and the next height are recorded in the
+	// state, so the evidence is verified without blocks
+	as := cc.GetAccountState(state.SystemID)
+	vl, err := state.ValidatorsForHeight(as, p.Height())
+	if err != nil {
+		h.Log.Warnf("PatchHandler: no validators height=%d err=%v", p.Height(), err)
+		return scoreresult.InvalidParameterError.Wrap(err, "NoValidatorsForEvidence")
+	}
+	nvl, err := state.ValidatorsForHeight(as, p.Height()+1)
+	if err != nil {
+		h.Log.Warnf("PatchHandler: no validators height=%d err=%v", p.Height()+1, err)
+		return scoreresult.InvalidParameterError.Wrap(err, "NoValidatorsForEvidence")
+	}
+	nvHash := nvl.Hash()
+	if nvHash == nil {
+		// nil skips the binding check
+		nvHash = []byte{}
+	}
+	nid := scoredb.NewVarDB(as, state.VarNetwork).Int64()
+	if err := p.Verify(int(nid), vl, nvHash); err != nil {
+		h.Log.Warnf("FailToVerifyDoubleSignPatch(err=%v)", err)
+		return scoreresult.InvalidParameterError.Wrap(err, "VerifyDoubleSignPatchFail")
+	}
+	signer := p.Signer()
+	dsDB := scoredb.NewDictDB(as, state.VarDoubleSigns, 2)
+	if dsDB.Get(signer, p.Height()) != nil {
+		return scoreresult.InvalidParameterError.Errorf(
+			"AlreadyHandled(signer=%s,height=%d)", signer, p.Height())
+	}
+	if err := dsDB.Set(signer, p.Height(), cc.BlockHeight()); err != nil {
+		return err
+	}
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte("DoubleSign(Address,int)"), signer.Bytes()},
+		[][]byte{intconv.Int64ToBytes(p.Height())},
+	)
+	h.Log.Warnf("PatchHandler: DOUBLE SIGN signer=%s height=%d", signer, p.Height())
+
+	score, err := cc.ContractManager().GetSystemScore(CID_CHAIN, cc, state.SystemAddress, new(big.Int))
+	if err != nil {
+		return err
+	}
+	if dsh, ok := score.(DoubleSignHandler); ok {
+		return dsh.HandleDoubleSign(signer, p.Height())
+	}
+	return nil
+}
+
 func (h *patchHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, module.Address) {
 	vs := cc.GetValidatorState()
 	if idx := vs.IndexOf(h.From); idx < 0 {
@@ -93,9 +163,14 @@ func (h *patchHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, modu
 	case module.PatchTypeSkipTransaction:
 		s := h.handleSkipTransaction(cc)
 		return s, nil, nil
-	default:
-		return scoreresult.InvalidParameterError.Errorf("InvalidDataType(%s)", h.patch.Type), nil, nil
+	case module.PatchTypeDoubleSign:
+		if !cc.Revision().Has(module.DoubleSignEvidence) {
+			break
+		}
+		s := h.handleDoubleSign(cc)
+		return s, nil, nil
 	}
+	return scoreresult.InvalidParameterError.Errorf("InvalidDataType(%s)", h.patch.Type), nil, nil
 }
 
 func newPatchHandler(ch *CommonHandler, data []byte) (ContractHandler, error) {
@@ -117,7 +192,7 @@ func ParsePatchData(data []byte) (*Patch, error) {
 			"InvalidJSON(json=%s)", data)
 	}
 	switch p.Type {
-	case module.PatchTypeSkipTransaction:
+	case module.PatchTypeSkipTransaction, module.PatchTypeDoubleSign:
 		// do nothing
 	default:
 		return nil, scoreresult.InvalidParameterError.Errorf(
@@ -125,3 +200,17 @@ func ParsePatchData(data []byte) (*Patch, error) {
 	}
 	return p, nil
 }
+
+// CheckPatchData parses the patch data, and checks the type of the patch
+// is enabled in the revision.
+func CheckPatchData(rev module.Revision, data []byte) error {
+	p, err := ParsePatchData(data)
+	if err != nil {
+		return err
+	}
+	if p.Type == module.PatchTypeDoubleSign && !rev.Has(module.DoubleSignEvidence) {
+		return scoreresult.InvalidParameterError.Errorf(
+			"UnknownPatchType(%s)", p.Type)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestCheckPatchData(t *testing.T) {
+	skipTxs := []byte(`{"type":"skip_txs","data":"0x00"}`)
+	doubleSign := []byte(`{"type":"double_sign","data":"0x00"}`)
+
+	var rev module.Revision
+	assert.NoError(t, CheckPatchData(rev, skipTxs))
+	assert.Error(t, CheckPatchData(rev, doubleSign))
+
+	rev |= module.DoubleSignEvidence
+	assert.NoError(t, CheckPatchData(rev, skipTxs))
+	assert.NoError(t, CheckPatchData(rev, doubleSign))
+
+	assert.Error(t, CheckPatchData(rev, []byte(`{"type":"unknown"}`)))
+	assert.Error(t, CheckPatchData(rev, []byte(`invalid`)))
+}
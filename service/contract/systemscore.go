@@ -38,6 +38,12 @@ type SystemScore interface {
 	GetAPI() *scoreapi.Info
 }
 
+// DoubleSignHandler is implemented by the system score handling
+// double signing of the validator depending on the platform.
+type DoubleSignHandler interface {
+	HandleDoubleSign(signer module.Address, height int64) error
+}
+
 func getSystemScore(contentID string, cc CallContext, from module.Address, value *big.Int) (score SystemScore, err error) {
 	v, ok := systemScoreModules[contentID]
 	if ok == false {
@@ -15,6 +15,7 @@ const (
 	NotContractAddressError
 	InvalidPatchDataError
 	CommittedTransactionError
+	StatePrunedError
 )
 
 var (
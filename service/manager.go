@@ -177,6 +177,37 @@ func (m *manager) CreateInitialTransition(result []byte,
 	return newInitTransition(m.db, result, valList, m.cm, m.eem, m.chain, m.log, m.plt, m.tsc, m.tim)
 }
 
+// TraceTransitionCreator is implemented by the service manager supporting
+// re-execution of historical blocks for trace.
+type TraceTransitionCreator interface {
+	CreateInitialTransitionForTrace(result []byte, vl module.ValidatorList) (module.Transition, error)
+}
+
+// CreateInitialTransitionForTrace creates an initial Transition like
+// CreateInitialTransition, but the transitions following it work on a
+// scratch layer over the database, so re-executing historical blocks for
+// trace doesn't write anything to the database. Transactions are executed
+// one by one and the execution stops after the target transaction for
+// module.TraceRangeTransaction.
+// It returns StatePrunedError if the state of the result is not kept.
+func (m *manager) CreateInitialTransitionForTrace(result []byte,
+	valList module.ValidatorList,
+) (module.Transition, error) {
+	tr, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStateOf(m.db, tr.StateHash); err != nil {
+		return nil, err
+	}
+	t, err := newInitTransition(db.NewLayerDB(m.db), result, valList, m.cm, m.eem, m.chain, m.log, m.plt, m.tsc, m.tim)
+	if err != nil {
+		return nil, err
+	}
+	t.replay = true
+	return t, nil
+}
+
 // CreateTransition creates a Transition following parent Transition with txs
 // transactions.
 // parent transition should have a valid result.
@@ -207,11 +238,49 @@ func (m *manager) SendPatch(data module.Patch) error {
 		}
 		m.skipTxPatch.Store(patch)
 		return nil
+	} else if data.Type() == module.PatchTypeDoubleSign {
+		patch, ok := data.(module.DoubleSignPatch)
+		if !ok {
+			return InvalidPatchDataError.New("Invalid Double Sign Patch Data")
+		}
+		if patch.Height() < 1 {
+			return InvalidPatchDataError.Errorf(
+				"InvalidHeightValue(height=%d)", patch.Height())
+		}
+		prev, err := m.chain.BlockManager().GetBlockByHeight(patch.Height() - 1)
+		if err != nil {
+			return InvalidPatchDataError.Wrap(err, "NoBlockForDoubleSignPatch")
+		}
+		// the voted blocks may not be finalized yet, so binding to the chain
+		// is checked on execution
+		if err := patch.Verify(m.chain.NID(), prev.NextValidators(), nil); err != nil {
+			return InvalidPatchDataError.Wrap(err, "InvalidDoubleSignPatch")
+		}
+		return m.sendPatchTransaction(patch)
 	} else {
 		return InvalidPatchDataError.New("UnknownPatch")
 	}
 }
 
+// sendPatchTransaction adds the patch transaction to the pool, and propagates
+// it to the other validators, so any proposer can include it.
+func (m *manager) sendPatchTransaction(p module.Patch) error {
+	tx, err := transaction.NewPatchTransaction(
+		p, m.chain.NID(), common.UnixMicroFromTime(time.Now()), m.chain.Wallet())
+	if err != nil {
+		return err
+	}
+	if err := m.tm.Add(tx, true, true); err != nil {
+		return err
+	}
+	if err := m.txReactor.PropagateTransaction(tx); err != nil {
+		if !network.NotAvailableError.Equals(err) {
+			m.log.Tracef("FAIL to propagate patch tx err=%+v", err)
+		}
+	}
+	return nil
+}
+
 // GetPatches returns all patch transactions based on the parent transition.
 // If it doesn't have any patches, it returns nil.
 func (m *manager) GetPatches(parent module.Transition, bi module.BlockInfo) module.TransactionList {
@@ -825,6 +894,17 @@ func (m *manager) ExportResult(result []byte, vh []byte, d db.Database) error {
 	return e.Run()
 }
 
+func (m *manager) ExportState(result []byte, vh []byte, d db.Database) error {
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	e := merkle.PrepareCopyContext(m.db, d)
+	ess := m.plt.NewExtensionWithBuilder(e.Builder(), r.ExtensionData)
+	state.NewWorldSnapshotWithBuilder(e.Builder(), r.StateHash, vh, ess, r.BTPData)
+	return e.Run()
+}
+
 func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error {
 	r, err := newTransitionResultFromBytes(result)
 	if err != nil {
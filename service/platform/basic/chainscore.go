@@ -22,6 +22,7 @@ import (
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
@@ -576,6 +577,27 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, Revision9, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setRoundTimeouts",
+		scoreapi.FlagExternal, 6,
+		[]scoreapi.Parameter{
+			{"propose", scoreapi.Integer, nil, nil},
+			{"prevote", scoreapi.Integer, nil, nil},
+			{"precommit", scoreapi.Integer, nil, nil},
+			{"newRound", scoreapi.Integer, nil, nil},
+			{"delta", scoreapi.Integer, nil, nil},
+			{"thresholdFactor", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, Revision10, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getRoundTimeouts",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, Revision10, 0},
 }
 
 func (s *ChainScore) GetAPI() *scoreapi.Info {
@@ -610,12 +632,48 @@ type Chain struct {
 	CommitTimeout      *common.HexInt64  `json:"commitTimeout"`
 	TimestampThreshold *common.HexInt64  `json:"timestampThreshold"`
 	RoundLimitFactor   *common.HexInt64  `json:"roundLimitFactor"`
+	RoundTimeouts      *RoundTimeouts    `json:"roundTimeouts"`
 	MinimizeBlockGen   *common.HexInt16  `json:"minimizeBlockGen"`
 	DepositTerm        *common.HexInt64  `json:"depositTerm"`
 	DepositIssueRate   *common.HexInt64  `json:"depositIssueRate"`
 	FeeSharingEnabled  *common.HexInt16  `json:"feeSharingEnabled"`
 }
 
+// RoundTimeouts are timeouts for consensus rounds in milli-second.
+// Zero or missing value means the system default value.
+type RoundTimeouts struct {
+	Propose         *common.HexInt64 `json:"propose"`
+	Prevote         *common.HexInt64 `json:"prevote"`
+	Precommit       *common.HexInt64 `json:"precommit"`
+	NewRound        *common.HexInt64 `json:"newRound"`
+	Delta           *common.HexInt64 `json:"delta"`
+	ThresholdFactor *common.HexInt64 `json:"thresholdFactor"`
+}
+
+var roundTimeoutKeys = []string{
+	state.RoundTimeoutPropose,
+	state.RoundTimeoutPrevote,
+	state.RoundTimeoutPrecommit,
+	state.RoundTimeoutNewRound,
+	state.RoundTimeoutDelta,
+	state.RoundTimeoutThresholdFactor,
+}
+
+// roundTimeoutMax returns the maximum value for the key of round timeouts.
+func roundTimeoutMax(key string) int64 {
+	if key == state.RoundTimeoutThresholdFactor {
+		return module.MaxRoundThresholdFactor
+	}
+	return int64(module.MaxRoundTimeout / time.Millisecond)
+}
+
+// values returns values in the order of roundTimeoutKeys
+func (t *RoundTimeouts) values() []*common.HexInt64 {
+	return []*common.HexInt64{
+		t.Propose, t.Prevote, t.Precommit, t.NewRound, t.Delta, t.ThresholdFactor,
+	}
+}
+
 func (s *ChainScore) Install(param []byte) error {
 	chain := Chain{}
 	if param != nil {
@@ -687,6 +745,22 @@ func (s *ChainScore) Install(param []byte) error {
 		}
 	}
 
+	if chain.RoundTimeouts != nil {
+		rtDB := scoredb.NewDictDB(as, state.VarRoundTimeouts, 1)
+		for i, value := range chain.RoundTimeouts.values() {
+			if value == nil {
+				continue
+			}
+			if value.Value < 0 || value.Value > roundTimeoutMax(roundTimeoutKeys[i]) {
+				return scoreresult.IllegalFormatError.Errorf(
+					"InvalidRoundTimeout(%s=%s)", roundTimeoutKeys[i], value)
+			}
+			if err := rtDB.Set(roundTimeoutKeys[i], value.Value); err != nil {
+				return err
+			}
+		}
+	}
+
 	if chain.MinimizeBlockGen != nil {
 		yn := chain.MinimizeBlockGen.Value != 0
 		if err := scoredb.NewVarDB(as, state.VarMinimizeBlockGen).Set(yn); err != nil {
@@ -1468,6 +1542,49 @@ func (s *ChainScore) Ex_setRoundLimitFactor(f *common.HexInt) error {
 	return factor.Set(f)
 }
 
+func (s *ChainScore) Ex_setRoundTimeouts(
+	propose, prevote, precommit, newRound, delta, thresholdFactor *common.HexInt,
+) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	values := []*common.HexInt{
+		propose, prevote, precommit, newRound, delta, thresholdFactor,
+	}
+	for i, value := range values {
+		if value.Sign() < 0 || !value.IsInt64() ||
+			value.Int64() > roundTimeoutMax(roundTimeoutKeys[i]) {
+			return scoreresult.InvalidParameterError.Errorf(
+				"InvalidRoundTimeout(%s=%s)", roundTimeoutKeys[i], value)
+		}
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	rtDB := scoredb.NewDictDB(as, state.VarRoundTimeouts, 1)
+	for i, value := range values {
+		if err := rtDB.Set(roundTimeoutKeys[i], value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ChainScore) Ex_getRoundTimeouts() (map[string]interface{}, error) {
+	if err := s.tryChargeCall(); err != nil {
+		return nil, err
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	rtDB := scoredb.NewDictDB(as, state.VarRoundTimeouts, 1)
+	timeouts := make(map[string]interface{})
+	for _, key := range roundTimeoutKeys {
+		var value int64
+		if v := rtDB.Get(key); v != nil {
+			value = v.Int64()
+		}
+		timeouts[key] = value
+	}
+	return timeouts, nil
+}
+
 func (s *ChainScore) Ex_getMinimizeBlockGen() (bool, error) {
 	if err := s.tryChargeCall(); err != nil {
 		return false, err
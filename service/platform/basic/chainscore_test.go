@@ -0,0 +1,136 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package basic_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/platform/basic"
+	"github.com/icon-project/goloop/test"
+)
+
+func roundTimeoutsParams(values ...int64) map[string]string {
+	keys := []string{"propose", "prevote", "precommit", "newRound", "delta", "thresholdFactor"}
+	params := make(map[string]string)
+	for i, v := range values {
+		params[keys[i]] = fmt.Sprintf("0x%x", v)
+	}
+	return params
+}
+
+func TestChainScore_SetRoundTimeouts(t *testing.T) {
+	assert := assert.New(t)
+	f := test.NewNode(t)
+	defer f.Close()
+
+	// transactions in a block take effect when the result is finalized
+	// with the next block
+	finalizeWithTX := func(tx *test.Transaction) {
+		f.ProposeFinalizeBlockWithTX(consensus.NewEmptyCommitVoteList(), tx.String())
+		f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	}
+
+	finalizeWithTX(test.NewTx().Call("setRevision", map[string]string{
+		"code": fmt.Sprintf("0x%x", basic.MaxRevision),
+	}))
+	def := f.Chain.Regulator().RoundTimeouts()
+
+	finalizeWithTX(test.NewTx().Call("setRoundTimeouts",
+		roundTimeoutsParams(2000, 3000, 4000, 5000, 100, 3)))
+	rt := module.RoundTimeouts{
+		Propose:         2 * time.Second,
+		Prevote:         3 * time.Second,
+		Precommit:       4 * time.Second,
+		NewRound:        5 * time.Second,
+		Delta:           100 * time.Millisecond,
+		ThresholdFactor: 3,
+	}
+	assert.Equal(rt, f.Chain.Regulator().RoundTimeouts())
+
+	// invalid values are refused
+	maxMS := int64(module.MaxRoundTimeout / time.Millisecond)
+	for _, values := range [][]int64{
+		{maxMS + 1, 1000, 1000, 1000, 0, 2},
+		{1000, 1000, 1000, 1000, maxMS + 1, 2},
+		{1000, 1000, 1000, 1000, 0, module.MaxRoundThresholdFactor + 1},
+		{1000, -1, 1000, 1000, 0, 2},
+	} {
+		finalizeWithTX(test.NewTx().Call("setRoundTimeouts", roundTimeoutsParams(values...)))
+		assert.Equal(rt, f.Chain.Regulator().RoundTimeouts(), values)
+	}
+
+	// zero values are the default values
+	finalizeWithTX(test.NewTx().Call("setRoundTimeouts",
+		roundTimeoutsParams(0, 0, 0, 0, 0, 0)))
+	assert.Equal(def, f.Chain.Regulator().RoundTimeouts())
+
+	// maximum values are accepted
+	finalizeWithTX(test.NewTx().Call("setRoundTimeouts",
+		roundTimeoutsParams(maxMS, maxMS, maxMS, maxMS, maxMS, module.MaxRoundThresholdFactor)))
+	assert.Equal(module.RoundTimeouts{
+		Propose:         module.MaxRoundTimeout,
+		Prevote:         module.MaxRoundTimeout,
+		Precommit:       module.MaxRoundTimeout,
+		NewRound:        module.MaxRoundTimeout,
+		Delta:           module.MaxRoundTimeout,
+		ThresholdFactor: module.MaxRoundThresholdFactor,
+	}, f.Chain.Regulator().RoundTimeouts())
+}
+
+func TestChainScore_RoundTimeoutsBeforeRevision10(t *testing.T) {
+	assert := assert.New(t)
+	f := test.NewNode(t)
+	defer f.Close()
+
+	finalizeWithTX := func(tx *test.Transaction) module.Receipt {
+		f.ProposeFinalizeBlockWithTX(consensus.NewEmptyCommitVoteList(), tx.String())
+		f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+		ti, err := f.BM.GetTransactionInfo(tx.ID())
+		assert.NoError(err)
+		rct, err := ti.GetReceipt()
+		assert.NoError(err)
+		return rct
+	}
+
+	rct := finalizeWithTX(test.NewTx().Call("setRevision", map[string]string{
+		"code": fmt.Sprintf("0x%x", basic.Revision9),
+	}))
+	assert.Equal(module.StatusSuccess, rct.Status())
+	def := f.Chain.Regulator().RoundTimeouts()
+
+	rct = finalizeWithTX(test.NewTx().Call("setRoundTimeouts",
+		roundTimeoutsParams(2000, 3000, 4000, 5000, 100, 3)))
+	assert.Equal(module.StatusMethodNotFound, rct.Status())
+	rct = finalizeWithTX(test.NewTx().Call("getRoundTimeouts", nil))
+	assert.Equal(module.StatusMethodNotFound, rct.Status())
+	assert.Equal(def, f.Chain.Regulator().RoundTimeouts())
+
+	rct = finalizeWithTX(test.NewTx().Call("setRevision", map[string]string{
+		"code": fmt.Sprintf("0x%x", basic.Revision10),
+	}))
+	assert.Equal(module.StatusSuccess, rct.Status())
+	rct = finalizeWithTX(test.NewTx().Call("setRoundTimeouts",
+		roundTimeoutsParams(3000, 3000, 3000, 3000, 0, 2)))
+	assert.Equal(module.StatusSuccess, rct.Status())
+	assert.NotEqual(def, f.Chain.Regulator().RoundTimeouts())
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+)
+
+// StorageDiff is a change of a storage value. Old or New is nil if the
+// value didn't exist.
+type StorageDiff struct {
+	Key []byte
+	Old []byte
+	New []byte
+}
+
+// AccountDiff is a change of an account between two world states.
+type AccountDiff struct {
+	ID []byte
+
+	// Old and New are snapshots of the account before and after the change.
+	Old AccountSnapshot
+	New AccountSnapshot
+
+	BalanceChanged  bool
+	ContractChanged bool
+	Storage         []*StorageDiff
+}
+
+type touchedAccount struct {
+	keys   []string
+	keySet map[string]bool
+}
+
+func (a *touchedAccount) addKey(k []byte) {
+	if a.keySet == nil {
+		a.keySet = make(map[string]bool)
+	}
+	if !a.keySet[string(k)] {
+		a.keySet[string(k)] = true
+		a.keys = append(a.keys, string(k))
+	}
+}
+
+// StateDiffRecorder records the accounts and the storage keys modified
+// through the world state returned by WorldState. Values are not recorded
+// on modification, but compared on Collect, so reverted changes don't
+// appear in the result.
+type StateDiffRecorder struct {
+	lock     sync.Mutex
+	ids      []string
+	accounts map[string]*touchedAccount
+}
+
+func (r *StateDiffRecorder) touch(id []byte) *touchedAccount {
+	a, ok := r.accounts[string(id)]
+	if !ok {
+		a = new(touchedAccount)
+		r.accounts[string(id)] = a
+		r.ids = append(r.ids, string(id))
+	}
+	return a
+}
+
+func (r *StateDiffRecorder) onAccountChange(id []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.touch(id)
+}
+
+func (r *StateDiffRecorder) onStorageChange(id []byte, k []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.touch(id).addKey(k)
+}
+
+// WorldState returns the world state recording the modifications of
+// the accounts.
+func (r *StateDiffRecorder) WorldState(ws WorldState) WorldState {
+	return &stateDiffWorldState{WorldState: ws, rec: r}
+}
+
+func contractEqual(c1, c2 ContractSnapshot) bool {
+	if c1 == nil || c2 == nil {
+		return c1 == c2
+	}
+	return c1.Equal(c2)
+}
+
+type accountSnapshotGetter interface {
+	GetAccountSnapshot(id []byte) AccountSnapshot
+	Database() db.Database
+}
+
+func accountSnapshotOf(ws accountSnapshotGetter, id []byte) AccountSnapshot {
+	if ass := ws.GetAccountSnapshot(id); ass != nil {
+		return ass
+	}
+	return newAccountSnapshot(ws.Database())
+}
+
+// Clear clears the records.
+func (r *StateDiffRecorder) Clear() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.ids, r.accounts = nil, make(map[string]*touchedAccount)
+}
+
+// Collect returns the differences of the recorded accounts between before
+// and after, then clears the records. Accounts without difference are not
+// included in the result, and Old and New of the result are never nil.
+func (r *StateDiffRecorder) Collect(before WorldSnapshot, after WorldState) ([]*AccountDiff, error) {
+	r.lock.Lock()
+	ids, accounts := r.ids, r.accounts
+	r.ids, r.accounts = nil, make(map[string]*touchedAccount)
+	r.lock.Unlock()
+
+	var diffs []*AccountDiff
+	for _, id := range ids {
+		diff := &AccountDiff{
+			ID:  []byte(id),
+			Old: accountSnapshotOf(before, []byte(id)),
+			New: accountSnapshotOf(after, []byte(id)),
+		}
+		diff.BalanceChanged = diff.Old.GetBalance().Cmp(diff.New.GetBalance()) != 0
+		diff.ContractChanged = !contractEqual(diff.Old.Contract(), diff.New.Contract()) ||
+			!contractEqual(diff.Old.NextContract(), diff.New.NextContract())
+
+		for _, k := range accounts[id].keys {
+			key := []byte(k)
+			v1, err := diff.Old.GetValue(key)
+			if err != nil {
+				return nil, err
+			}
+			v2, err := diff.New.GetValue(key)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(v1, v2) {
+				diff.Storage = append(diff.Storage, &StorageDiff{key, v1, v2})
+			}
+		}
+		if diff.BalanceChanged || diff.ContractChanged || len(diff.Storage) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+func NewStateDiffRecorder() *StateDiffRecorder {
+	return &StateDiffRecorder{
+		accounts: make(map[string]*touchedAccount),
+	}
+}
+
+type stateDiffWorldState struct {
+	WorldState
+	rec *StateDiffRecorder
+}
+
+func (ws *stateDiffWorldState) GetAccountState(id []byte) AccountState {
+	as := ws.WorldState.GetAccountState(id)
+	if as == nil {
+		return nil
+	}
+	return &stateDiffAccountState{AccountState: as, id: id, rec: ws.rec}
+}
+
+// stateDiffAccountState reports modifications of the account to
+// the recorder.
+type stateDiffAccountState struct {
+	AccountState
+	id  []byte
+	rec *StateDiffRecorder
+}
+
+func (s *stateDiffAccountState) SetBalance(v *big.Int) {
+	s.rec.onAccountChange(s.id)
+	s.AccountState.SetBalance(v)
+}
+
+func (s *stateDiffAccountState) SetValue(k, v []byte) ([]byte, error) {
+	s.rec.onStorageChange(s.id, k)
+	return s.AccountState.SetValue(k, v)
+}
+
+func (s *stateDiffAccountState) DeleteValue(k []byte) ([]byte, error) {
+	s.rec.onStorageChange(s.id, k)
+	return s.AccountState.DeleteValue(k)
+}
+
+func (s *stateDiffAccountState) InitContractAccount(address module.Address) bool {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.InitContractAccount(address)
+}
+
+func (s *stateDiffAccountState) DeployContract(code []byte, eeType EEType, contentType string, params []byte, txHash []byte) ([]byte, error) {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.DeployContract(code, eeType, contentType, params, txHash)
+}
+
+func (s *stateDiffAccountState) SetAPIInfo(info *scoreapi.Info) {
+	s.rec.onAccountChange(s.id)
+	s.AccountState.SetAPIInfo(info)
+}
+
+func (s *stateDiffAccountState) ActivateNextContract() error {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.ActivateNextContract()
+}
+
+func (s *stateDiffAccountState) AcceptContract(txHash []byte, auditTxHash []byte) error {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.AcceptContract(txHash, auditTxHash)
+}
+
+func (s *stateDiffAccountState) RejectContract(txHash []byte, auditTxHash []byte) error {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.RejectContract(txHash, auditTxHash)
+}
+
+func (s *stateDiffAccountState) SetContractOwner(owner module.Address) error {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.SetContractOwner(owner)
+}
+
+func (s *stateDiffAccountState) AddDeposit(dc DepositContext, value *big.Int) error {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.AddDeposit(dc, value)
+}
+
+func (s *stateDiffAccountState) WithdrawDeposit(dc DepositContext, id []byte, value *big.Int) (*big.Int, *big.Int, error) {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.WithdrawDeposit(dc, id, value)
+}
+
+func (s *stateDiffAccountState) PaySteps(pc PayContext, steps *big.Int) (*big.Int, *big.Int, error) {
+	s.rec.onAccountChange(s.id)
+	return s.AccountState.PaySteps(pc, steps)
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestStateDiffRecorder_Collect(t *testing.T) {
+	database := db.NewMapDB()
+	rec := NewStateDiffRecorder()
+	ws := rec.WorldState(NewWorldState(database, nil, nil, nil, nil))
+
+	id1 := []byte("account1")
+	id2 := []byte("account2")
+	id3 := []byte("account3")
+
+	as1 := ws.GetAccountState(id1)
+	as1.SetBalance(big.NewInt(100))
+	_, err := as1.SetValue([]byte("k1"), []byte("v1"))
+	assert.NoError(t, err)
+	_, err = as1.SetValue([]byte("k2"), []byte("v2"))
+	assert.NoError(t, err)
+
+	diffs, err := rec.Collect(ws.GetSnapshot(), ws)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	before := ws.GetSnapshot()
+	as1 = ws.GetAccountState(id1)
+	as1.SetBalance(big.NewInt(200))
+	_, err = as1.SetValue([]byte("k1"), []byte("v1-new"))
+	assert.NoError(t, err)
+	_, err = as1.DeleteValue([]byte("k2"))
+	assert.NoError(t, err)
+
+	// changed and restored
+	as2 := ws.GetAccountState(id2)
+	as2.SetBalance(big.NewInt(10))
+	as2.SetBalance(big.NewInt(0))
+
+	// changed and reverted
+	snapshot := ws.GetSnapshot()
+	ws.GetAccountState(id3).SetBalance(big.NewInt(30))
+	assert.NoError(t, ws.Reset(snapshot))
+
+	diffs, err = rec.Collect(before, ws)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 1)
+
+	d := diffs[0]
+	assert.Equal(t, id1, d.ID)
+	assert.True(t, d.BalanceChanged)
+	assert.Equal(t, int64(100), d.Old.GetBalance().Int64())
+	assert.Equal(t, int64(200), d.New.GetBalance().Int64())
+	assert.False(t, d.ContractChanged)
+	assert.Equal(t, []*StorageDiff{
+		{[]byte("k1"), []byte("v1"), []byte("v1-new")},
+		{[]byte("k2"), []byte("v2"), nil},
+	}, d.Storage)
+
+	diffs, err = rec.Collect(before, ws)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+}
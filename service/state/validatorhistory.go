@@ -0,0 +1,94 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+)
+
+// validatorHistoryEntry has the validators voting for the blocks from
+// Height until the height of the next entry.
+type validatorHistoryEntry struct {
+	Height     int64
+	Validators []byte
+}
+
+func validatorHistoryOf(store containerdb.BytesStoreState) ([]validatorHistoryEntry, error) {
+	var entries []validatorHistoryEntry
+	if bs := scoredb.NewVarDB(store, VarValidatorHistory).Bytes(); len(bs) > 0 {
+		if _, err := codec.BC.UnmarshalFromBytes(bs, &entries); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// RecordValidatorHistory records the validators voting for the blocks from
+// the height if they are different from the last recorded ones. On update,
+// it removes the entries which are used only for the heights before
+// minHeight.
+func RecordValidatorHistory(
+	store containerdb.BytesStoreState, height int64, vss ValidatorSnapshot, minHeight int64,
+) error {
+	entries, err := validatorHistoryOf(store)
+	if err != nil {
+		return err
+	}
+	vbs := vss.Bytes()
+	if l := len(entries); l > 0 && bytes.Equal(entries[l-1].Validators, vbs) {
+		return nil
+	}
+	entries = append(entries, validatorHistoryEntry{height, vbs})
+	for len(entries) > 1 && entries[1].Height <= minHeight {
+		entries = entries[1:]
+	}
+	return scoredb.NewVarDB(store, VarValidatorHistory).Set(codec.BC.MustMarshalToBytes(entries))
+}
+
+// ValidatorsForHeight returns the validators voting for the blocks of the
+// height, which are recorded by RecordValidatorHistory.
+func ValidatorsForHeight(store containerdb.BytesStoreState, height int64) (module.ValidatorList, error) {
+	entries, err := validatorHistoryOf(store)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Height <= height {
+			return validatorSnapshotFromBytes(entries[i].Validators)
+		}
+	}
+	return nil, errors.NotFoundError.Errorf("NoValidatorsForHeight(height=%d)", height)
+}
+
+func validatorSnapshotFromBytes(bs []byte) (*validatorSnapshot, error) {
+	vss := &validatorSnapshot{
+		validatorList: &validatorList{},
+	}
+	if len(bs) > 0 {
+		if _, err := codec.BC.UnmarshalFromBytes(bs, &vss.validators); err != nil {
+			return nil, err
+		}
+		vss.serialized = bs
+	}
+	return vss, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestValidatorHistory(t *testing.T) {
+	dbase := db.NewMapDB()
+	as := newAccountState(dbase, nil, nil, false)
+
+	vss1, err := ValidatorSnapshotFromSlice(dbase, newDummyValidators(4))
+	assert.NoError(t, err)
+	vss2, err := ValidatorSnapshotFromSlice(dbase, newDummyValidatorsFrom(2, 4))
+	assert.NoError(t, err)
+
+	_, err = ValidatorsForHeight(as, 10)
+	assert.Error(t, err)
+
+	assert.NoError(t, RecordValidatorHistory(as, 10, vss1, 0))
+	ass := as.GetSnapshot()
+	// same validators are not recorded again
+	assert.NoError(t, RecordValidatorHistory(as, 11, vss1, 1))
+	assert.True(t, as.GetSnapshot().Equal(ass))
+	assert.NoError(t, RecordValidatorHistory(as, 20, vss2, 10))
+
+	_, err = ValidatorsForHeight(as, 9)
+	assert.Error(t, err)
+	for _, c := range []struct {
+		height int64
+		vss    ValidatorSnapshot
+	}{
+		{10, vss1}, {19, vss1}, {20, vss2}, {30, vss2},
+	} {
+		vl, err := ValidatorsForHeight(as, c.height)
+		assert.NoError(t, err)
+		assert.Equal(t, c.vss.Hash(), vl.Hash(), c.height)
+		assert.Equal(t, c.vss.Len(), vl.Len())
+	}
+
+	// old validators are removed on update
+	assert.NoError(t, RecordValidatorHistory(as, 30, vss1, 20))
+	_, err = ValidatorsForHeight(as, 19)
+	assert.Error(t, err)
+	vl, err := ValidatorsForHeight(as, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, vss2.Hash(), vl.Hash())
+	assert.Equal(t, 0, vl.IndexOf(newDummyAddress(2)))
+}
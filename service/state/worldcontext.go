@@ -32,6 +32,7 @@ const (
 	VarBlockInterval      = "block_interval"
 	VarCommitTimeout      = "commit_timeout"
 	VarRoundLimitFactor   = "round_limit_factor"
+	VarRoundTimeouts      = "round_timeouts"
 	VarMinimizeBlockGen   = "minimize_block_gen"
 	VarTxHashToAddress    = "tx_to_address"
 	VarDepositTerm        = "deposit_term"
@@ -39,6 +40,18 @@ const (
 	VarNextBlockVersion   = "next_block_version"
 	VarEnabledEETypes     = "enabled_ee_types"
 	VarSystemDepositUsage = "system_deposit_usage"
+	VarDoubleSigns        = "double_signs"
+	VarValidatorHistory   = "validator_history"
+)
+
+// Keys of VarRoundTimeouts. Timeouts are in milli-second.
+const (
+	RoundTimeoutPropose         = "propose"
+	RoundTimeoutPrevote         = "prevote"
+	RoundTimeoutPrecommit       = "precommit"
+	RoundTimeoutNewRound        = "newRound"
+	RoundTimeoutDelta           = "delta"
+	RoundTimeoutThresholdFactor = "thresholdFactor"
 )
 
 const (
@@ -0,0 +1,235 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type callEvent struct {
+	addr    module.Address
+	indexed [][]byte
+	data    [][]byte
+}
+
+type callNode struct {
+	parent   *callNode
+	from     module.Address
+	to       module.Address
+	value    *big.Int
+	method   string
+	params   interface{}
+	stepUsed *big.Int
+	status   error
+	failed   bool
+	events   []*callEvent
+	calls    []*callNode
+}
+
+func (n *callNode) toJSON() map[string]interface{} {
+	jso := make(map[string]interface{})
+	if n.from != nil {
+		jso["from"] = n.from
+	}
+	if n.to != nil {
+		jso["to"] = n.to
+	}
+	if n.value != nil {
+		jso["value"] = new(common.HexInt).SetValue(n.value)
+	}
+	if len(n.method) > 0 {
+		jso["method"] = n.method
+	}
+	if n.params != nil {
+		jso["params"] = n.params
+	}
+	if n.stepUsed != nil {
+		jso["stepUsed"] = new(common.HexInt).SetValue(n.stepUsed)
+	}
+	if n.status == nil && !n.failed {
+		jso["status"] = "0x1"
+	} else {
+		jso["status"] = "0x0"
+		if n.status != nil {
+			code, _ := scoreresult.StatusOf(n.status)
+			jso["failure"] = map[string]interface{}{
+				"code":    code,
+				"message": n.status.Error(),
+			}
+		}
+	}
+	if len(n.events) > 0 {
+		events := make([]interface{}, len(n.events))
+		for i, e := range n.events {
+			events[i] = txresult.EventLogToJSON(e.addr, e.indexed, e.data)
+		}
+		jso["events"] = events
+	}
+	if len(n.calls) > 0 {
+		calls := make([]interface{}, len(n.calls))
+		for i, c := range n.calls {
+			calls[i] = c.toJSON()
+		}
+		jso["calls"] = calls
+	}
+	return jso
+}
+
+type callTransaction struct {
+	index     int
+	hash      []byte
+	isBlockTx bool
+	calls     []*callNode
+}
+
+func (t *callTransaction) toJSON() map[string]interface{} {
+	prefix := "0x"
+	if t.isBlockTx {
+		prefix = "bx"
+	}
+	calls := make([]interface{}, len(t.calls))
+	for i, c := range t.calls {
+		calls[i] = c.toJSON()
+	}
+	return map[string]interface{}{
+		"txIndex": fmt.Sprintf("%#x", t.index),
+		"txHash":  prefix + hex.EncodeToString(t.hash),
+		"calls":   calls,
+	}
+}
+
+// CallTracer builds the tree of inter-contract calls for TraceModeCall.
+// Each frame becomes a node having its caller, callee, method, params,
+// value, steps used, status and emitted events.
+type CallTracer struct {
+	txs     []*callTransaction
+	curTx   *callTransaction
+	curNode *callNode
+}
+
+func (ct *CallTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if ct.curTx != nil {
+		return errors.InvalidStateError.Errorf(
+			"Invalid curTx: txIndex=%d txHash=%#x curTx=%#x",
+			txIndex, txHash, ct.curTx.hash)
+	}
+	ct.curTx = &callTransaction{index: txIndex, hash: txHash, isBlockTx: isBlockTx}
+	ct.txs = append(ct.txs, ct.curTx)
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionReset() error {
+	if ct.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	ct.curTx.calls = nil
+	ct.curNode = nil
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	if ct.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	// frames may be left open on timeout, then they are regarded as failed.
+	for n := ct.curNode; n != nil; n = n.parent {
+		n.failed = true
+	}
+	ct.curTx = nil
+	ct.curNode = nil
+	return nil
+}
+
+func (ct *CallTracer) OnFrameEnter() error {
+	if ct.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	node := &callNode{parent: ct.curNode}
+	if ct.curNode != nil {
+		ct.curNode.calls = append(ct.curNode.calls, node)
+	} else {
+		ct.curTx.calls = append(ct.curTx.calls, node)
+	}
+	ct.curNode = node
+	return nil
+}
+
+func (ct *CallTracer) OnFrameExit(success bool) error {
+	if ct.curNode == nil {
+		return errors.InvalidStateError.New("No frame")
+	}
+	ct.curNode.failed = !success
+	ct.curNode = ct.curNode.parent
+	return nil
+}
+
+func (ct *CallTracer) OnBalanceChange(opType module.OpType, from, to module.Address, amount *big.Int) error {
+	return nil
+}
+
+func (ct *CallTracer) OnCallStart(from, to module.Address, value *big.Int, method string, params interface{}) error {
+	if ct.curNode == nil {
+		return errors.InvalidStateError.New("No frame")
+	}
+	n := ct.curNode
+	n.from, n.to, n.method, n.params = from, to, method, params
+	if value != nil {
+		n.value = new(big.Int).Set(value)
+	}
+	return nil
+}
+
+func (ct *CallTracer) OnCallEnd(stepUsed *big.Int, status error) error {
+	if ct.curNode == nil {
+		return errors.InvalidStateError.New("No frame")
+	}
+	if stepUsed != nil {
+		ct.curNode.stepUsed = new(big.Int).Set(stepUsed)
+	}
+	ct.curNode.status = status
+	return nil
+}
+
+func (ct *CallTracer) OnEvent(addr module.Address, indexed, data [][]byte) error {
+	if ct.curNode == nil {
+		// events out of frames are not related to any call.
+		return nil
+	}
+	ct.curNode.events = append(ct.curNode.events, &callEvent{addr, indexed, data})
+	return nil
+}
+
+// ToJSON returns the call trees of the transactions.
+func (ct *CallTracer) ToJSON() []map[string]interface{} {
+	jso := make([]map[string]interface{}, 0, len(ct.txs))
+	for _, tx := range ct.txs {
+		jso = append(jso, tx.toJSON())
+	}
+	return jso
+}
+
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
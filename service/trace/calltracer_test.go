@@ -0,0 +1,88 @@
+package trace
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+func TestCallTracer_Tree(t *testing.T) {
+	ct := NewCallTracer()
+
+	eoa := common.MustNewAddressFromString("hx100")
+	score1 := common.MustNewAddressFromString("cx101")
+	score2 := common.MustNewAddressFromString("cx102")
+	txHash := newRandomHash(32)
+
+	assert.NoError(t, ct.OnTransactionStart(0, txHash, false))
+
+	assert.NoError(t, ct.OnFrameEnter())
+	assert.NoError(t, ct.OnCallStart(eoa, score1, big.NewInt(10), "transfer",
+		json.RawMessage(`{"to":"cx102"}`)))
+
+	// successful inter-call with an event
+	assert.NoError(t, ct.OnFrameEnter())
+	assert.NoError(t, ct.OnCallStart(score1, score2, big.NewInt(0), "deposit", nil))
+	assert.NoError(t, ct.OnEvent(score2, [][]byte{[]byte("Deposit(int)")}, [][]byte{{0x01}}))
+	assert.NoError(t, ct.OnCallEnd(big.NewInt(100), nil))
+	assert.NoError(t, ct.OnFrameExit(true))
+
+	// failed inter-call
+	assert.NoError(t, ct.OnFrameEnter())
+	assert.NoError(t, ct.OnCallStart(score1, score2, nil, "withdraw", nil))
+	assert.NoError(t, ct.OnCallEnd(big.NewInt(200), scoreresult.ErrInvalidParameter))
+	assert.NoError(t, ct.OnFrameExit(false))
+
+	assert.NoError(t, ct.OnCallEnd(big.NewInt(1000), nil))
+	assert.NoError(t, ct.OnFrameExit(true))
+	assert.NoError(t, ct.OnTransactionEnd(0, txHash))
+
+	txs := ct.ToJSON()
+	assert.Len(t, txs, 1)
+	calls := txs[0]["calls"].([]interface{})
+	assert.Len(t, calls, 1)
+
+	root := calls[0].(map[string]interface{})
+	assert.Equal(t, "0x1", root["status"])
+	assert.Equal(t, "transfer", root["method"])
+	assert.Equal(t, "0x3e8", root["stepUsed"].(*common.HexInt).String())
+	assert.Equal(t, "0xa", root["value"].(*common.HexInt).String())
+
+	subs := root["calls"].([]interface{})
+	assert.Len(t, subs, 2)
+
+	deposit := subs[0].(map[string]interface{})
+	assert.Equal(t, "0x1", deposit["status"])
+	assert.Len(t, deposit["events"], 1)
+	assert.Nil(t, deposit["calls"])
+
+	withdraw := subs[1].(map[string]interface{})
+	assert.Equal(t, "0x0", withdraw["status"])
+	assert.NotNil(t, withdraw["failure"])
+	assert.Nil(t, withdraw["value"])
+
+	_, err := json.Marshal(txs)
+	assert.NoError(t, err)
+}
+
+func TestCallTracer_Reset(t *testing.T) {
+	ct := NewCallTracer()
+	txHash := newRandomHash(32)
+
+	assert.NoError(t, ct.OnTransactionStart(0, txHash, false))
+	assert.NoError(t, ct.OnFrameEnter())
+	assert.NoError(t, ct.OnTransactionReset())
+	assert.NoError(t, ct.OnTransactionEnd(0, txHash))
+
+	txs := ct.ToJSON()
+	assert.Len(t, txs, 1)
+	assert.Len(t, txs[0]["calls"], 0)
+
+	// frame without the transaction
+	assert.Error(t, ct.OnFrameEnter())
+}
@@ -209,6 +209,74 @@ func (l *Logger) OnFrameExit(success bool, stepUsed *big.Int) {
 	}
 }
 
+func (l *Logger) callTraceCallback() module.CallTraceCallback {
+	if l.TraceMode() != module.TraceModeCall {
+		return nil
+	}
+	cb, _ := l.cb.(module.CallTraceCallback)
+	return cb
+}
+
+func (l *Logger) OnCallStart(from, to module.Address, value *big.Int, method string, params interface{}) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallStart(from, to, value, method, params); err != nil {
+			l.Warnf("OnCallStart() error: from=%s to=%s method=%s err=%#v",
+				from, to, method, err)
+		}
+	}
+}
+
+func (l *Logger) OnCallEnd(stepUsed *big.Int, status error) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallEnd(stepUsed, status); err != nil {
+			l.Warnf("OnCallEnd() error: steps=%d status=%v err=%#v",
+				stepUsed, status, err)
+		}
+	}
+}
+
+func (l *Logger) OnEvent(addr module.Address, indexed, data [][]byte) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnEvent(addr, indexed, data); err != nil {
+			l.Warnf("OnEvent() error: score=%s err=%#v", addr, err)
+		}
+	}
+}
+
+func (l *Logger) stateDiffTraceCallback() module.StateDiffTraceCallback {
+	if l.TraceMode() != module.TraceModeStateDiff {
+		return nil
+	}
+	cb, _ := l.cb.(module.StateDiffTraceCallback)
+	return cb
+}
+
+func (l *Logger) OnBalanceDiff(addr module.Address, old, new *big.Int) {
+	if cb := l.stateDiffTraceCallback(); cb != nil {
+		if err := cb.OnBalanceDiff(addr, old, new); err != nil {
+			l.Warnf("OnBalanceDiff() error: addr=%s old=%d new=%d err=%#v",
+				addr, old, new, err)
+		}
+	}
+}
+
+func (l *Logger) OnStorageDiff(addr module.Address, key, old, new []byte) {
+	if cb := l.stateDiffTraceCallback(); cb != nil {
+		if err := cb.OnStorageDiff(addr, key, old, new); err != nil {
+			l.Warnf("OnStorageDiff() error: addr=%s key=%#x err=%#v",
+				addr, key, err)
+		}
+	}
+}
+
+func (l *Logger) OnContractDiff(addr module.Address, old, new interface{}) {
+	if cb := l.stateDiffTraceCallback(); cb != nil {
+		if err := cb.OnContractDiff(addr, old, new); err != nil {
+			l.Warnf("OnContractDiff() error: addr=%s err=%#v", addr, err)
+		}
+	}
+}
+
 func (l *Logger) OnBalanceChange(opType module.OpType, from, to module.Address, amount *big.Int) {
 	if l.TraceMode() == module.TraceModeNone {
 		return
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type storageDiff struct {
+	key, old, new []byte
+}
+
+func bytesToJSON(bs []byte) interface{} {
+	if bs == nil {
+		return nil
+	}
+	return "0x" + hex.EncodeToString(bs)
+}
+
+func (d *storageDiff) toJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"key": bytesToJSON(d.key),
+		"old": bytesToJSON(d.old),
+		"new": bytesToJSON(d.new),
+	}
+}
+
+type accountDiff struct {
+	addr     module.Address
+	balance  []*big.Int
+	contract []interface{}
+	storage  []*storageDiff
+}
+
+func (d *accountDiff) toJSON() map[string]interface{} {
+	jso := map[string]interface{}{
+		"address": d.addr,
+	}
+	if d.balance != nil {
+		jso["balance"] = map[string]interface{}{
+			"old": new(common.HexInt).SetValue(d.balance[0]),
+			"new": new(common.HexInt).SetValue(d.balance[1]),
+		}
+	}
+	if d.contract != nil {
+		jso["contract"] = map[string]interface{}{
+			"old": d.contract[0],
+			"new": d.contract[1],
+		}
+	}
+	if len(d.storage) > 0 {
+		storage := make([]interface{}, len(d.storage))
+		for i, s := range d.storage {
+			storage[i] = s.toJSON()
+		}
+		jso["storage"] = storage
+	}
+	return jso
+}
+
+type stateDiffTransaction struct {
+	index     int
+	hash      []byte
+	isBlockTx bool
+	accounts  []*accountDiff
+}
+
+func (t *stateDiffTransaction) accountOf(addr module.Address) *accountDiff {
+	for _, a := range t.accounts {
+		if a.addr.Equal(addr) {
+			return a
+		}
+	}
+	a := &accountDiff{addr: addr}
+	t.accounts = append(t.accounts, a)
+	return a
+}
+
+func (t *stateDiffTransaction) toJSON() map[string]interface{} {
+	prefix := "0x"
+	if t.isBlockTx {
+		prefix = "bx"
+	}
+	accounts := make([]interface{}, len(t.accounts))
+	for i, a := range t.accounts {
+		accounts[i] = a.toJSON()
+	}
+	return map[string]interface{}{
+		"txIndex":  fmt.Sprintf("%#x", t.index),
+		"txHash":   prefix + hex.EncodeToString(t.hash),
+		"accounts": accounts,
+	}
+}
+
+// StateDiffTracer collects changes of accounts for TraceModeStateDiff.
+// Each transaction has the list of accounts with their old and new
+// balance, contract and storage values.
+type StateDiffTracer struct {
+	txs   []*stateDiffTransaction
+	curTx *stateDiffTransaction
+}
+
+func (st *StateDiffTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if st.curTx != nil {
+		return errors.InvalidStateError.Errorf(
+			"Invalid curTx: txIndex=%d txHash=%#x curTx=%#x",
+			txIndex, txHash, st.curTx.hash)
+	}
+	st.curTx = &stateDiffTransaction{index: txIndex, hash: txHash, isBlockTx: isBlockTx}
+	st.txs = append(st.txs, st.curTx)
+	return nil
+}
+
+func (st *StateDiffTracer) OnTransactionReset() error {
+	if st.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	st.curTx.accounts = nil
+	return nil
+}
+
+func (st *StateDiffTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	if st.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	st.curTx = nil
+	return nil
+}
+
+func (st *StateDiffTracer) OnFrameEnter() error {
+	return nil
+}
+
+func (st *StateDiffTracer) OnFrameExit(success bool) error {
+	return nil
+}
+
+func (st *StateDiffTracer) OnBalanceChange(opType module.OpType, from, to module.Address, amount *big.Int) error {
+	return nil
+}
+
+func (st *StateDiffTracer) OnBalanceDiff(addr module.Address, old, new *big.Int) error {
+	if st.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	st.curTx.accountOf(addr).balance = []*big.Int{old, new}
+	return nil
+}
+
+func (st *StateDiffTracer) OnStorageDiff(addr module.Address, key, old, new []byte) error {
+	if st.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	a := st.curTx.accountOf(addr)
+	a.storage = append(a.storage, &storageDiff{key, old, new})
+	return nil
+}
+
+func (st *StateDiffTracer) OnContractDiff(addr module.Address, old, new interface{}) error {
+	if st.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	st.curTx.accountOf(addr).contract = []interface{}{old, new}
+	return nil
+}
+
+// ToJSON returns the changes of the transactions.
+func (st *StateDiffTracer) ToJSON() []map[string]interface{} {
+	jso := make([]map[string]interface{}, 0, len(st.txs))
+	for _, tx := range st.txs {
+		jso = append(jso, tx.toJSON())
+	}
+	return jso
+}
+
+func NewStateDiffTracer() *StateDiffTracer {
+	return &StateDiffTracer{}
+}
@@ -0,0 +1,70 @@
+package trace
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+)
+
+func TestStateDiffTracer_Basic(t *testing.T) {
+	st := NewStateDiffTracer()
+
+	eoa := common.MustNewAddressFromString("hx100")
+	score := common.MustNewAddressFromString("cx101")
+	txHash := newRandomHash(32)
+
+	assert.Error(t, st.OnBalanceDiff(eoa, big.NewInt(1), big.NewInt(0)))
+
+	assert.NoError(t, st.OnTransactionStart(0, txHash, false))
+	assert.NoError(t, st.OnBalanceDiff(eoa, big.NewInt(100), big.NewInt(90)))
+	assert.NoError(t, st.OnStorageDiff(score, []byte{0x01}, nil, []byte{0x02}))
+	assert.NoError(t, st.OnStorageDiff(score, []byte{0x02}, []byte{0x03}, nil))
+	assert.NoError(t, st.OnContractDiff(score, nil, map[string]interface{}{}))
+	assert.NoError(t, st.OnTransactionEnd(0, txHash))
+
+	txs := st.ToJSON()
+	assert.Len(t, txs, 1)
+	assert.Equal(t, "0x0", txs[0]["txIndex"])
+
+	accounts := txs[0]["accounts"].([]interface{})
+	assert.Len(t, accounts, 2)
+
+	a1 := accounts[0].(map[string]interface{})
+	assert.Equal(t, eoa, a1["address"])
+	balance := a1["balance"].(map[string]interface{})
+	assert.Equal(t, "0x64", balance["old"].(*common.HexInt).String())
+	assert.Equal(t, "0x5a", balance["new"].(*common.HexInt).String())
+	assert.Nil(t, a1["storage"])
+
+	a2 := accounts[1].(map[string]interface{})
+	assert.Equal(t, score, a2["address"])
+	assert.Nil(t, a2["balance"])
+	assert.NotNil(t, a2["contract"])
+	storage := a2["storage"].([]interface{})
+	assert.Len(t, storage, 2)
+	assert.Equal(t, map[string]interface{}{
+		"key": "0x01", "old": nil, "new": "0x02",
+	}, storage[0])
+
+	_, err := json.Marshal(txs)
+	assert.NoError(t, err)
+}
+
+func TestStateDiffTracer_Reset(t *testing.T) {
+	st := NewStateDiffTracer()
+	eoa := common.MustNewAddressFromString("hx100")
+	txHash := newRandomHash(32)
+
+	assert.NoError(t, st.OnTransactionStart(0, txHash, false))
+	assert.NoError(t, st.OnBalanceDiff(eoa, big.NewInt(100), big.NewInt(90)))
+	assert.NoError(t, st.OnTransactionReset())
+	assert.NoError(t, st.OnTransactionEnd(0, txHash))
+
+	txs := st.ToJSON()
+	assert.Len(t, txs, 1)
+	assert.Empty(t, txs[0]["accounts"])
+}
@@ -31,7 +31,16 @@ func NewPatchTransaction(p module.Patch, nid int, ts int64, w module.Wallet) (Tr
 	tx.Data = js
 
 	// sign
-	sig, err := w.Sign(v3tx.TxHash())
+	var sig []byte
+	if ps, ok := w.(module.PayloadSigner); ok {
+		var bs []byte
+		if bs, err = tx.serialize(); err != nil {
+			return nil, err
+		}
+		sig, err = ps.SignPayload(module.SignTypeTransaction, bs)
+	} else {
+		sig, err = w.Sign(v3tx.TxHash())
+	}
 	if err != nil {
 		return nil, err
 	}
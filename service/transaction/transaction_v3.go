@@ -34,8 +34,8 @@ type transactionV3Data struct {
 	Data      json.RawMessage  `json:"data,omitempty"`
 }
 
-func (tx *transactionV3Data) calcHash() ([]byte, error) {
-	// sha := sha3.New256()
+// serialize returns the bytes for the hash of the transaction.
+func (tx *transactionV3Data) serialize() ([]byte, error) {
 	sha := bytes.NewBuffer(nil)
 	sha.Write([]byte("icx_sendTransaction"))
 
@@ -99,7 +99,15 @@ func (tx *transactionV3Data) calcHash() ([]byte, error) {
 	sha.Write([]byte(".version."))
 	sha.Write([]byte(tx.Version.String()))
 
-	return crypto.SHA3Sum256(sha.Bytes()), nil
+	return sha.Bytes(), nil
+}
+
+func (tx *transactionV3Data) calcHash() ([]byte, error) {
+	bs, err := tx.serialize()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.SHA3Sum256(bs), nil
 }
 
 type transactionV3 struct {
@@ -229,7 +237,11 @@ func (tx *transactionV3) ValidateNetwork(nid int) bool {
 }
 
 func (tx *transactionV3) PreValidate(wc state.WorldContext, update bool) error {
-	if tx.DataType == nil || *tx.DataType != contract.DataTypePatch {
+	if tx.DataType != nil && *tx.DataType == contract.DataTypePatch {
+		if err := contract.CheckPatchData(wc.Revision(), tx.Data); err != nil {
+			return InvalidTxValue.Wrap(err, "TxData is invalid")
+		}
+	} else {
 		// stepLimit >= default step + input steps
 		cnt, err := MeasureBytesOfData(wc.Revision(), tx.Data)
 		if err != nil {
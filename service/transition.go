@@ -10,6 +10,7 @@ import (
 	"github.com/icon-project/goloop/btp"
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -19,6 +20,7 @@ import (
 	"github.com/icon-project/goloop/service/scoredb"
 	"github.com/icon-project/goloop/service/state"
 	ssync "github.com/icon-project/goloop/service/sync2"
+	"github.com/icon-project/goloop/service/trace"
 	"github.com/icon-project/goloop/service/transaction"
 	"github.com/icon-project/goloop/service/txresult"
 )
@@ -60,6 +62,10 @@ func (s transitionStep) String() string {
 	}
 }
 
+// errTraceTargetDone is returned on executing transactions after the
+// target transaction of the trace, so the others don't need to be executed.
+var errTraceTargetDone = errors.New("TraceTargetDone")
+
 type transitionCallbackForTrace struct {
 	info *module.TraceInfo
 }
@@ -86,6 +92,11 @@ type transitionContext struct {
 	tsc   *TxTimestampChecker
 	sass  state.AccountSnapshot
 	tim   TXIDManager
+
+	// replay is set for re-executing historical blocks for trace, then
+	// transactions are executed one by one and the execution stops after
+	// the target transaction of the trace.
+	replay bool
 }
 
 func (tc *transitionContext) onWorldFinalize(wss state.WorldSnapshot) {
@@ -100,6 +111,10 @@ func (tc *transitionContext) onWorldFinalize(wss state.WorldSnapshot) {
 				time.Duration(interval)*time.Millisecond,
 				time.Duration(timeout)*time.Millisecond)
 		}
+		revision := int(scoredb.NewVarDB(as, state.VarRevision).Int64())
+		if tc.plt.ToRevision(revision).Has(module.RoundTimeoutsInState) {
+			regulator.SetRoundTimeouts(roundTimeoutsOf(as))
+		}
 
 		tsThreshold := scoredb.NewVarDB(as, state.VarTimestampThreshold).Int64()
 		if tsThreshold > 0 {
@@ -111,6 +126,28 @@ func (tc *transitionContext) onWorldFinalize(wss state.WorldSnapshot) {
 	tc.plt.OnExtensionSnapshotFinalization(wss.GetExtensionSnapshot(), tc.log)
 }
 
+func roundTimeoutsOf(as containerdb.BytesStoreState) module.RoundTimeouts {
+	rtDB := scoredb.NewDictDB(as, state.VarRoundTimeouts, 1)
+	ms := func(key string) time.Duration {
+		if v := rtDB.Get(key); v != nil {
+			return time.Duration(v.Int64()) * time.Millisecond
+		}
+		return 0
+	}
+	var factor int
+	if v := rtDB.Get(state.RoundTimeoutThresholdFactor); v != nil {
+		factor = int(v.Int64())
+	}
+	return module.RoundTimeouts{
+		Propose:         ms(state.RoundTimeoutPropose),
+		Prevote:         ms(state.RoundTimeoutPrevote),
+		Precommit:       ms(state.RoundTimeoutPrecommit),
+		NewRound:        ms(state.RoundTimeoutNewRound),
+		Delta:           ms(state.RoundTimeoutDelta),
+		ThresholdFactor: factor,
+	}
+}
+
 type transition struct {
 	parent *transition
 	pid    *transitionID
@@ -143,7 +180,8 @@ type transition struct {
 
 	syncer ssync.Syncer
 
-	ti *module.TraceInfo
+	ti  *module.TraceInfo
+	sdr *state.StateDiffRecorder
 
 	ptxIDs   TXIDLogger
 	ntxIDs   TXIDLogger
@@ -407,6 +445,10 @@ func (t *transition) newWorldContext(execution bool) (state.WorldContext, error)
 	}
 	if execution {
 		ws.EnableNodeCache()
+		if t.ti != nil && t.ti.TraceMode == module.TraceModeStateDiff {
+			t.sdr = state.NewStateDiffRecorder()
+			ws = t.sdr.WorldState(ws)
+		}
 	}
 	return state.NewWorldContext(ws, t.bi, t.csi, t.plt), nil
 }
@@ -661,18 +703,31 @@ func (t *transition) doExecute(alreadyValidated bool) {
 
 	ctx.GetBTPState().StoreValidators(ctx.GetValidatorState())
 
+	if ctx.Revision().Has(module.DoubleSignEvidence) {
+		// validators before the execution vote for the next height
+		if err := state.RecordValidatorHistory(
+			ctx.GetAccountState(state.SystemID),
+			ctx.BlockHeight()+1,
+			ctx.GetValidatorState().GetSnapshot(),
+			ctx.BlockHeight()-module.DoubleSignEvidenceWindow,
+		); err != nil {
+			t.reportExecution(err)
+			return
+		}
+	}
+
 	if err := t.plt.OnExecutionBegin(ctx, t.log); err != nil {
 		t.reportExecution(err)
 		return
 	}
 	patchReceipts := make([]txresult.Receipt, t.ptxCount)
 	if err := t.executeTxsSequential(t.patchTransactions, ctx, patchReceipts); err != nil {
-		t.reportExecution(err)
+		t.reportTraceOrExecution(err)
 		return
 	}
 	normalReceipts := make([]txresult.Receipt, t.ntxCount)
 	if err := t.executeTxs(t.normalTransactions, ctx, normalReceipts); err != nil {
-		t.reportExecution(err)
+		t.reportTraceOrExecution(err)
 		return
 	}
 	cumulativeSteps := big.NewInt(0)
@@ -788,11 +843,86 @@ func (t *transition) validateTxs(l module.TransactionList, wc state.WorldContext
 	return nil
 }
 
+// isTraceTarget returns true if the transaction is the target of the trace
+// for TraceRangeTransaction on replaying the block, then the execution stops
+// after it.
+func (t *transition) isTraceTarget(group module.TransactionGroup, idx int) bool {
+	return t.replay && t.ti != nil && t.ti.Range == module.TraceRangeTransaction &&
+		t.ti.Group == group && t.ti.Index == idx
+}
+
+// needSequentialForTrace returns true if the trace requires transactions
+// to be executed one by one.
+func (t *transition) needSequentialForTrace() bool {
+	return t.ti != nil && ((t.replay && t.ti.Range == module.TraceRangeTransaction) ||
+		t.ti.TraceMode == module.TraceModeStateDiff)
+}
+
+func addressOfAccountDiff(d *state.AccountDiff) module.Address {
+	if d.Old.IsContract() || d.New.IsContract() {
+		return common.NewContractAddress(d.ID)
+	}
+	return common.NewAccountAddress(d.ID)
+}
+
+func contractsToJSON(ass state.AccountSnapshot) interface{} {
+	if !ass.IsContract() {
+		return nil
+	}
+	ret := make(map[string]interface{})
+	if c := ass.Contract(); c != nil {
+		ret["current"] = contractToJSON(c, module.JSONVersion3)
+	}
+	if c := ass.NextContract(); c != nil {
+		ret["next"] = contractToJSON(c, module.JSONVersion3)
+	}
+	return ret
+}
+
+// traceStateDiff reports the changes made by the transaction, which are
+// recorded since wss, to the trace logger.
+func (t *transition) traceStateDiff(ctx contract.Context, wss state.WorldSnapshot, tlog *trace.Logger) error {
+	if t.sdr == nil {
+		return nil
+	}
+	if tlog.TraceMode() != module.TraceModeStateDiff {
+		t.sdr.Clear()
+		return nil
+	}
+	diffs, err := t.sdr.Collect(wss, ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		addr := addressOfAccountDiff(d)
+		if d.BalanceChanged {
+			tlog.OnBalanceDiff(addr, d.Old.GetBalance(), d.New.GetBalance())
+		}
+		if d.ContractChanged {
+			tlog.OnContractDiff(addr, contractsToJSON(d.Old), contractsToJSON(d.New))
+		}
+		for _, s := range d.Storage {
+			tlog.OnStorageDiff(addr, s.Key, s.Old, s.New)
+		}
+	}
+	return nil
+}
+
+// reportTraceOrExecution reports the end of the execution. If it's stopped
+// after the target transaction of the trace, then it reports success without
+// the result, which is not used for trace.
+func (t *transition) reportTraceOrExecution(e error) {
+	if e == errTraceTargetDone {
+		e = nil
+	}
+	t.reportExecution(e)
+}
+
 func (t *transition) executeTxs(l module.TransactionList, ctx contract.Context, rctBuf []txresult.Receipt) error {
 	if l == nil {
 		return nil
 	}
-	if ctx.SkipTransactionEnabled() {
+	if ctx.SkipTransactionEnabled() || t.needSequentialForTrace() {
 		// it will skip skippable transactions
 		return t.executeTxsSequential(l, ctx, rctBuf)
 	}
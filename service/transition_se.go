@@ -82,9 +82,16 @@ func (t *transition) executeTxsSequential(l module.TransactionList, ctx contract
 			traceLogger.OnTransactionReset()
 		}
 
+		if err := t.traceStateDiff(ctx, wcs, traceLogger); err != nil {
+			t.log.Warnf("Fail to trace state diff err=%+v", err)
+			return err
+		}
 		traceLogger.OnTransactionEnd(cnt, txo.ID(), txInfo.From, ctx.Treasury(), ctx.Revision(), rctBuf[cnt])
 		duration := time.Since(ts)
 		t.log.Tracef("END   TX <0x%x> duration=%s", txo.ID(), duration)
+		if t.isTraceTarget(txo.Group(), cnt) {
+			return errTraceTargetDone
+		}
 		cnt++
 	}
 	return nil
@@ -124,6 +124,25 @@ func (tr *transitionResult) Bytes() []byte {
 	}
 }
 
+// checkStateOf returns StatePrunedError if the root of the world state
+// is not in the database. Otherwise, missing nodes are regarded as empty
+// accounts, so it would return wrong values.
+func checkStateOf(dbase db.Database, stateHash []byte) error {
+	if len(stateHash) == 0 {
+		return nil
+	}
+	bk, err := dbase.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	if has, err := bk.Has(stateHash); err != nil {
+		return err
+	} else if !has {
+		return StatePrunedError.Errorf("StatePruned(hash=%#x)", stateHash)
+	}
+	return nil
+}
+
 func NewWorldSnapshot(database db.Database, plt base.Platform, result []byte, vl module.ValidatorList) (state.WorldSnapshot, error) {
 	return newWorldSnapshot(database, plt, result, vl)
 }
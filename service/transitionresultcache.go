@@ -134,6 +134,9 @@ func (c *transitionResultCache) getWorldSnapshotInLock(result []byte, vh []byte)
 		return nil, err
 	}
 	if item.worldSnapshot == nil {
+		if err := checkStateOf(item.database, item.transactionResult.StateHash); err != nil {
+			return nil, err
+		}
 		item.worldSnapshot = state.NewWorldSnapshot(
 			item.database,
 			item.transactionResult.StateHash,
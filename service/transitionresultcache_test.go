@@ -48,3 +48,24 @@ func Test_transitionResultCache_GetWorldSnapshot(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, ws)
 }
+
+func Test_transitionResultCache_GetWorldSnapshotPruned(t *testing.T) {
+	mdb := db.NewMapDB()
+	logger := log.GlobalLogger()
+	trc := newTransitionResultCache(mdb, &testPlatform{}, 10, 10, logger)
+
+	stateHash := []byte("01234567890123456789012345678901")
+	result := (&transitionResult{StateHash: stateHash}).Bytes()
+
+	ws, err := trc.GetWorldSnapshot(result, nil)
+	assert.True(t, StatePrunedError.Equals(err))
+	assert.Nil(t, ws)
+
+	bk, err := mdb.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set(stateHash, []byte{0xc0}))
+
+	ws, err = trc.GetWorldSnapshot(result, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, ws)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
 )
 
 type Chain struct {
@@ -40,6 +41,8 @@ type Chain struct {
 	log       log.Logger
 	regulator module.Regulator
 	nm        *NetworkManager
+	netMgr    module.NetworkManager
+	nt        module.NetworkTransport
 	bm        module.BlockManager
 	sm        module.ServiceManager
 	cs        module.Consensus
@@ -105,11 +108,31 @@ func (c *Chain) TransactionTimeout() time.Duration {
 }
 
 func (c *Chain) ChildrenLimit() int {
-	panic("implement me")
+	return -1
 }
 
 func (c *Chain) NephewsLimit() int {
-	panic("implement me")
+	return -1
+}
+
+func (c *Chain) PeerBanThreshold() int {
+	return 0
+}
+
+func (c *Chain) PeerScoreHalfLife() time.Duration {
+	return 0
+}
+
+func (c *Chain) PeerBanDuration() time.Duration {
+	return 0
+}
+
+func (c *Chain) BandwidthLimits() string {
+	return ""
+}
+
+func (c *Chain) PacketCapture() string {
+	return ""
 }
 
 func (c *Chain) ValidateTxOnSend() bool {
@@ -147,6 +170,9 @@ func (c *Chain) ServiceManager() module.ServiceManager {
 }
 
 func (c *Chain) NetworkManager() module.NetworkManager {
+	if c.netMgr != nil {
+		return c.netMgr
+	}
 	return c.nm
 }
 
@@ -269,9 +295,61 @@ func (c *Chain) DoDBTask(f func(database db.Database)) {
 }
 
 func (c *Chain) Close() {
+	if c.netMgr != nil {
+		c.netMgr.Term()
+		_ = c.nt.Close()
+	}
 	c.nm.Close()
 }
 
+type memoryNetworkChain struct {
+	*Chain
+}
+
+func (c memoryNetworkChain) MetricContext() context.Context {
+	return context.Background()
+}
+
+// useMemoryNetwork makes the chain use the network manager over the
+// transport on the memory network instead of NetworkManager.
+func (c *Chain) useMemoryNetwork(n *network.MemoryNetwork, address string) error {
+	nt := network.NewMemoryTransport(n, address, c.wallet, c.log)
+	if err := nt.Listen(); err != nil {
+		return err
+	}
+	nm := network.NewManager(memoryNetworkChain{c}, nt, "", module.RoleValidator)
+	// peers become friends only if they support all default protocols,
+	// so register the ones which are not served by the test service
+	for _, pi := range []module.ProtocolInfo{
+		module.ProtoTransaction, module.ProtoStateSync,
+	} {
+		_, err := nm.RegisterReactor(
+			pi.String(), pi, idleReactor{}, nil, 1,
+			module.NotRegisteredProtocolPolicyNone,
+		)
+		if err != nil {
+			nm.Term()
+			_ = nt.Close()
+			return err
+		}
+	}
+	c.nt = nt
+	c.netMgr = nm
+	return nil
+}
+
+type idleReactor struct{}
+
+func (r idleReactor) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	return false, nil
+}
+
+func (r idleReactor) OnJoin(id module.PeerID) {
+}
+
+func (r idleReactor) OnLeave(id module.PeerID) {
+}
+
 func NewChain(
 	t *testing.T,
 	w module.Wallet,
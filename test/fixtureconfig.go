@@ -27,6 +27,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/service/platform/basic"
 )
 
@@ -48,6 +49,7 @@ type FixtureConfig struct {
 	Wallet            module.Wallet
 	AddDefaultNode    *bool
 	WAL               func() consensus.WALManager
+	MemoryNetwork     *network.MemoryNetwork
 }
 
 func NewFixtureConfig(t *testing.T, o ...FixtureOption) *FixtureConfig {
@@ -147,5 +149,8 @@ func (cf *FixtureConfig) Override(cf2 *FixtureConfig) *FixtureConfig {
 	if cf2.WAL != nil {
 		res.WAL = cf2.WAL
 	}
+	if cf2.MemoryNetwork != nil {
+		res.MemoryNetwork = cf2.MemoryNetwork
+	}
 	return &res
 }
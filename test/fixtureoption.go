@@ -20,6 +20,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
 )
 
 type FixtureOption func(cf *FixtureConfig) *FixtureConfig
@@ -63,6 +64,13 @@ func UseGenesisStorage(gs module.GenesisStorage) FixtureOption {
 	})
 }
 
+// UseMemoryNetwork option makes nodes connect to each other over the memory
+// network with the network manager. Nodes are connected by
+// NodeInterconnectOnMemory instead of NodeInterconnect.
+func UseMemoryNetwork(n *network.MemoryNetwork) FixtureOption {
+	return UseConfig(&FixtureConfig{MemoryNetwork: n})
+}
+
 func UseWallet(w module.Wallet) FixtureOption {
 	return UseConfig(&FixtureConfig{Wallet: w})
 }
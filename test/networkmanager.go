@@ -39,6 +39,7 @@ type NetworkManager struct {
 	peers    []Peer
 	handlers []*nmHandler
 	roles    map[string]module.Role
+	reports  map[string][]module.Offence
 }
 
 func indexOf(pl []Peer, id module.PeerID) int {
@@ -53,11 +54,12 @@ func indexOf(pl []Peer, id module.PeerID) int {
 func NewNetworkManager(t *testing.T, a module.Address) *NetworkManager {
 	const chLen = 1024
 	n := &NetworkManager{
-		t:      t,
-		roles:  make(map[string]module.Role),
-		id:     network.NewPeerIDFromAddress(a),
-		rCh:    make(chan packetEntry, chLen),
-		stopCh: make(chan struct{}),
+		t:       t,
+		roles:   make(map[string]module.Role),
+		reports: make(map[string][]module.Offence),
+		id:      network.NewPeerIDFromAddress(a),
+		rCh:     make(chan packetEntry, chLen),
+		stopCh:  make(chan struct{}),
 	}
 	go n.handlePacketLoop()
 	return n
@@ -300,3 +302,18 @@ func (h *nmHandler) Unicast(pi module.ProtocolInfo, b []byte, id module.PeerID)
 func (h *nmHandler) GetPeers() []module.PeerID {
 	return h.n.GetPeers()
 }
+
+func (h *nmHandler) ReportPeer(id module.PeerID, offence module.Offence) {
+	nmMu.Lock()
+	defer nmMu.Unlock()
+
+	h.n.reports[id.String()] = append(h.n.reports[id.String()], offence)
+}
+
+// Reports returns the offences reported for the peer.
+func (n *NetworkManager) Reports(id module.PeerID) []module.Offence {
+	nmMu.Lock()
+	defer nmMu.Unlock()
+
+	return append([]module.Offence(nil), n.reports[id.String()]...)
+}
@@ -17,6 +17,7 @@
 package test
 
 import (
+	"encoding/hex"
 	"io"
 	"os"
 	"path"
@@ -31,6 +32,7 @@ import (
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/service/contract"
 	"github.com/icon-project/goloop/service/eeproxy"
 )
@@ -47,6 +49,9 @@ type Node struct {
 	PrevBlock module.Block
 	LastBlock module.Block
 	Platform  base.Platform
+
+	// NetAddress is the address on the memory network if it's used.
+	NetAddress string
 }
 
 type NodeContext struct {
@@ -71,6 +76,12 @@ func NewNode(t *testing.T, o ...FixtureOption) *Node {
 	c, err := NewChain(t, w, dbase, logger, cf.CVSD, cf.Genesis)
 	assert.NoError(t, err)
 	c.Logger().SetLevel(log.TraceLevel)
+	var netAddress string
+	if cf.MemoryNetwork != nil {
+		netAddress = hex.EncodeToString(w.Address().ID()) + ":8080"
+		err = c.useMemoryNetwork(cf.MemoryNetwork, netAddress)
+		assert.NoError(t, err)
+	}
 
 	// set up sm
 	RegisterTransactionFactory()
@@ -120,6 +131,8 @@ func NewNode(t *testing.T, o ...FixtureOption) *Node {
 		PrevBlock: nil,
 		LastBlock: lastBlk,
 		Platform:  plt,
+
+		NetAddress: netAddress,
 	}
 }
 
@@ -400,6 +413,25 @@ func NodeInterconnect(nodes []*Node) {
 	}
 }
 
+// NodeInterconnectOnMemory connects the nodes using the memory network to
+// each other as static peers, and starts their network managers. Call it
+// after the consensus is started as protocols of the reactors are
+// negotiated on connection.
+func NodeInterconnectOnMemory(nodes []*Node) {
+	for _, n := range nodes {
+		for _, n2 := range nodes {
+			if n != n2 {
+				err := network.AddStaticPeer(n.Chain, n2.NetAddress)
+				assert.NoError(n.T, err)
+			}
+		}
+	}
+	for _, n := range nodes {
+		err := n.Chain.netMgr.Start()
+		assert.NoError(n.T, err)
+	}
+}
+
 func NodeWaitForBlock(nodes []*Node, h int64) module.Block {
 	var blk module.Block
 	for _, n := range nodes {
@@ -17,12 +17,15 @@
 package test
 
 import (
+	"sync"
 	"time"
 
 	"github.com/icon-project/goloop/module"
 )
 
 type regulatorImpl struct {
+	mu            sync.Mutex
+	roundTimeouts module.RoundTimeouts
 }
 
 func (r *regulatorImpl) MaxTxCount() int {
@@ -49,6 +52,35 @@ func (r *regulatorImpl) SetBlockInterval(i time.Duration, d time.Duration) {
 	// do nothing
 }
 
+func (r *regulatorImpl) RoundTimeouts() module.RoundTimeouts {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.roundTimeouts
+}
+
+func (r *regulatorImpl) SetRoundTimeouts(t module.RoundTimeouts) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	defaultRoundTimeouts(&t)
+	r.roundTimeouts = t
+}
+
+// defaultRoundTimeouts replaces zero values with default values.
+func defaultRoundTimeouts(t *module.RoundTimeouts) {
+	for _, v := range []*time.Duration{&t.Propose, &t.Prevote, &t.Precommit, &t.NewRound} {
+		if *v == 0 {
+			*v = time.Second
+		}
+	}
+	if t.ThresholdFactor == 0 {
+		t.ThresholdFactor = 2
+	}
+}
+
 func NewRegulator() module.Regulator {
-	return &regulatorImpl{}
+	r := &regulatorImpl{}
+	defaultRoundTimeouts(&r.roundTimeouts)
+	return r
 }
@@ -254,6 +254,17 @@ func (sm *ServiceManager) getSystemByteStoreState(result []byte) (containerdb.By
 	return scoredb.NewStateStoreWith(ass), nil
 }
 
+func (sm *ServiceManager) ExportState(result []byte, vh []byte, dst db.Database) error {
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	e := merkle.PrepareCopyContext(sm.dbase, dst)
+	ess := sm.plt.NewExtensionWithBuilder(e.Builder(), r.ExtensionData)
+	state.NewWorldSnapshotWithBuilder(e.Builder(), r.StateHash, vh, ess, r.BTPData)
+	return e.Run()
+}
+
 func (sm *ServiceManager) ImportResult(result []byte, vh []byte, src db.Database) error {
 	panic("implement me")
 }
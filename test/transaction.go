@@ -33,6 +33,7 @@ import (
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/contract"
 	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
 	"github.com/icon-project/goloop/service/state"
 	"github.com/icon-project/goloop/service/transaction"
 	"github.com/icon-project/goloop/service/txresult"
@@ -208,12 +209,16 @@ func (t *Transaction) Execute(ctx contract.Context, wcs state.WorldSnapshot, est
 		err, _, _, _ = cc.Call(ch, big.NewInt((1<<63)-1))
 		if err != nil {
 			log.Errorf("error in test transaction: tx from=%s tx data=%s err=%+v", c.From, c.Data, err)
-			chainScoreError = err
+			if chainScoreError == nil {
+				chainScoreError = err
+			}
 		}
 		cc.GetBTPMessages(r)
 	}
 	log.Infof("Execute transaction height=%d tx=%s chainScoreError=%+v", ctx.BlockHeight(), t, chainScoreError)
-	r.SetResult(module.StatusSuccess, big.NewInt(0), big.NewInt(0), nil)
+	// the status of the first failed call is kept in the receipt
+	status, _ := scoreresult.StatusOf(chainScoreError)
+	r.SetResult(status, big.NewInt(0), big.NewInt(0), nil)
 	return r, nil
 }
 
@@ -76,6 +76,11 @@ class SystemInterface(InterfaceScore):
     def setRoundLimitFactor(self, factor: int):
         pass
 
+    @interface
+    def setRoundTimeouts(self, propose: int, prevote: int, precommit: int,
+                         newRound: int, delta: int, thresholdFactor: int):
+        pass
+
     @interface
     def setDeployerWhiteListEnabled(self, yn: bool):
         pass
@@ -190,6 +195,12 @@ class Governance(IconScoreBase):
     def setRoundLimitFactor(self, factor: int):
         self.system_score.setRoundLimitFactor(factor)
 
+    @external
+    def setRoundTimeouts(self, propose: int, prevote: int, precommit: int,
+                         newRound: int, delta: int, thresholdFactor: int):
+        self.system_score.setRoundTimeouts(propose, prevote, precommit,
+                                           newRound, delta, thresholdFactor)
+
     @external
     def setDeployerWhiteListEnabled(self, yn: bool):
         self.system_score.setDeployerWhiteListEnabled(yn)